@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"film-library/src/internal/config"
+	"film-library/src/internal/db"
+	"film-library/src/internal/export"
+)
+
+func main() {
+	format := flag.String("format", export.FormatCSV, "file format, one of [csv, ndjson, excel]")
+	out := flag.String("o", "catalogue-"+time.Now().Format("20060102")+".zip", "archive path")
+	flag.Parse()
+
+	cfg := config.New()
+	database, err := db.NewDatabase(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer database.Close()
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := export.NewService(export.NewRepository(database.GetDB()))
+
+	log.Printf("exporting to %s\n", *out)
+	err = s.WriteArchive(context.Background(), &export.ExportRequest{FormatQuery: *format}, f)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		os.Remove(*out)
+		log.Fatal(err)
+	}
+}
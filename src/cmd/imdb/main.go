@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+
+	"film-library/src/internal/config"
+	"film-library/src/internal/db"
+	"film-library/src/internal/history"
+	"film-library/src/internal/imdb"
+)
+
+func main() {
+	titles := flag.String("titles", "", "path to title.basics.tsv(.gz)")
+	names := flag.String("names", "", "path to name.basics.tsv(.gz)")
+	principals := flag.String("principals", "", "path to title.principals.tsv(.gz)")
+	types := flag.String("types", "movie", "comma separated title types to import")
+	adult := flag.Bool("adult", false, "import adult titles")
+	batch := flag.Int("batch", imdb.DefaultBatchSize, "rows upserted per statement")
+	flag.Parse()
+
+	if len(*titles) == 0 && len(*names) == 0 && len(*principals) == 0 {
+		flag.Usage()
+		log.Fatal("no dataset given")
+	}
+
+	cfg := config.New()
+	database, err := db.NewDatabase(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	conn := database.GetContextDB()
+	txManager := database.GetTxManager()
+	historyService := history.NewService(history.NewRepository(conn), txManager)
+	s := imdb.NewService(imdb.NewRepository(conn), txManager, historyService, *batch)
+	tf := imdb.ToTitleFilter(strings.Split(*types, ","), *adult)
+
+	// credits refer to films and actors, so principals go last
+	for _, v := range []struct {
+		path string
+		run  func(*imdb.File) (*imdb.Stats, error)
+	}{
+		{*titles, func(f *imdb.File) (*imdb.Stats, error) { return s.ImportTitles(ctx, f, tf) }},
+		{*names, func(f *imdb.File) (*imdb.Stats, error) { return s.ImportNames(ctx, f) }},
+		{*principals, func(f *imdb.File) (*imdb.Stats, error) { return s.ImportPrincipals(ctx, f) }},
+	} {
+		if len(v.path) == 0 {
+			continue
+		}
+
+		log.Printf("importing %s\n", v.path)
+		f, err := imdb.Open(v.path)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		stats, err := v.run(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("%s: %d rows read, %d upserted, %d skipped\n", v.path, stats.Read, stats.Upserted, stats.Skipped)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"film-library/src/internal/config"
+	"film-library/src/internal/db"
+	"film-library/src/internal/history"
+	"film-library/src/internal/importer"
+)
+
+// extFormats maps input file extensions to formats used when -format is
+// not given.
+var extFormats = map[string]string{
+	".csv":    importer.FormatCSV,
+	".jsonl":  importer.FormatJSONL,
+	".ndjson": importer.FormatJSONL,
+}
+
+func main() {
+	format := flag.String("format", "", "input format, one of [csv, jsonl], guessed from the file extension by default")
+	flag.Usage = func() {
+		log.Printf("usage: importer [-format csv|jsonl] <file|->\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	path := flag.Arg(0)
+
+	if len(*format) == 0 {
+		*format = extFormats[strings.ToLower(filepath.Ext(path))]
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	cfg := config.New()
+	database, err := db.NewDatabase(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer database.Close()
+
+	conn := database.GetContextDB()
+	tx := database.GetTxManager()
+	hs := history.NewService(history.NewRepository(conn), tx)
+	s := importer.NewService(importer.NewRepository(conn), tx, hs)
+
+	log.Println("importing")
+	res, err := s.ImportFilms(context.Background(), &importer.ImportRequest{
+		Format: *format,
+		Body:   in,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(res); err != nil {
+		log.Fatal(err)
+	}
+
+	if !res.Committed {
+		log.Printf("import rolled back, %d rows failed\n", res.Failed)
+		os.Exit(1)
+	}
+	log.Printf("imported: %d created, %d updated, %d skipped\n", res.Created, res.Updated, res.Skipped)
+}
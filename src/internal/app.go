@@ -1,19 +1,33 @@
 package app
 
 import (
+	"context"
 	"log"
 
+	"film-library/src/internal/collection"
 	"film-library/src/internal/config"
 	"film-library/src/internal/db"
+	"film-library/src/internal/export"
 	"film-library/src/internal/film"
+	"film-library/src/internal/franchise"
+	"film-library/src/internal/genre"
+	"film-library/src/internal/history"
+	"film-library/src/internal/idempotency"
+	"film-library/src/internal/importer"
+	"film-library/src/internal/media"
 	"film-library/src/internal/models"
+	"film-library/src/internal/review"
 	"film-library/src/internal/router"
+	"film-library/src/internal/search"
+	"film-library/src/internal/trash"
 	"film-library/src/internal/user"
+	"film-library/src/internal/watchlist"
 )
 
 type App struct {
-	Router *router.Router
-	Config *config.Config
+	Router      *router.Router
+	Config      *config.Config
+	idempotency *idempotency.Service
 }
 
 func NewApp(cfg *config.Config) *App {
@@ -22,27 +36,81 @@ func NewApp(cfg *config.Config) *App {
 		log.Fatal(err)
 	}
 
-	userRepo := user.NewRepository(database.GetDB())
+	conn := database.GetContextDB()
+	txManager := database.GetTxManager()
+
+	userRepo := user.NewRepository(conn)
 	userService := user.NewService(userRepo, cfg)
 	userHandler := user.NewHandler(userService)
 
-	actorRepo := models.NewRepository(database.GetDB())
-	actorService := models.NewService(actorRepo)
+	historyRepo := history.NewRepository(conn)
+	historyService := history.NewService(historyRepo, txManager)
+	historyHandler := history.NewHandler(historyService)
+
+	actorRepo := models.NewRepository(conn)
+	actorService := models.NewService(actorRepo, txManager, historyService)
 	actorHandler := models.NewHandler(actorService)
 
-	filmRepo := film.NewRepository(database.GetDB())
-	filmService := film.NewService(filmRepo)
+	filmRepo := film.NewRepository(conn)
+	filmService := film.NewService(filmRepo, txManager, historyService)
 	filmHandler := film.NewHandler(filmService)
 
-	router := router.NewRouter(cfg, userHandler, actorHandler, filmHandler)
+	searchRepo := search.NewRepository(conn)
+	searchService := search.NewService(searchRepo)
+	searchHandler := search.NewHandler(searchService)
+
+	genreRepo := genre.NewRepository(conn)
+	genreService := genre.NewService(genreRepo)
+	genreHandler := genre.NewHandler(genreService)
+
+	reviewRepo := review.NewRepository(conn)
+	reviewService := review.NewService(reviewRepo)
+	reviewHandler := review.NewHandler(reviewService)
+
+	watchlistRepo := watchlist.NewRepository(conn)
+	watchlistService := watchlist.NewService(watchlistRepo)
+	watchlistHandler := watchlist.NewHandler(watchlistService)
+
+	collectionRepo := collection.NewRepository(conn)
+	collectionService := collection.NewService(collectionRepo, txManager)
+	collectionHandler := collection.NewHandler(collectionService)
+
+	franchiseRepo := franchise.NewRepository(conn)
+	franchiseService := franchise.NewService(franchiseRepo)
+	franchiseHandler := franchise.NewHandler(franchiseService)
+
+	mediaStorage := media.NewLocalStorage(cfg.MediaDir, cfg.MediaURL)
+	mediaRepo := media.NewRepository(conn)
+	mediaService := media.NewService(mediaRepo, mediaStorage)
+	mediaHandler := media.NewHandler(mediaService, cfg.MaxUploadSize)
+
+	importRepo := importer.NewRepository(conn)
+	importService := importer.NewService(importRepo, txManager, historyService)
+	importHandler := importer.NewHandler(importService, cfg.MaxUploadSize)
+
+	exportRepo := export.NewRepository(conn)
+	exportService := export.NewService(exportRepo)
+	exportHandler := export.NewHandler(exportService)
+
+	trashRepo := trash.NewRepository(conn)
+	trashService := trash.NewService(trashRepo, txManager, historyService, mediaStorage, cfg.TrashRetention)
+	trashHandler := trash.NewHandler(trashService)
+
+	idempotencyRepo := idempotency.NewRepository(conn)
+	idempotencyService := idempotency.NewService(idempotencyRepo, cfg.IdempotencyTTL, cfg.IdempotencyLease)
+
+	router := router.NewRouter(cfg, userHandler, actorHandler, filmHandler, searchHandler, genreHandler, reviewHandler, watchlistHandler, collectionHandler, franchiseHandler, mediaHandler, importHandler, exportHandler, trashHandler, historyHandler, idempotencyService)
 
 	return &App{
-		Router: router,
-		Config: cfg,
+		Router:      router,
+		Config:      cfg,
+		idempotency: idempotencyService,
 	}
 }
 
 func (a *App) Run() {
+	go a.idempotency.Sweep(context.Background(), a.Config.IdempotencySweep)
+
 	log.Printf("server running %s", a.Config.Addr())
 	if err := a.Router.Run(a.Config.Addr()); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,189 @@
+package collection
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"film-library/src/internal/tools"
+)
+
+const (
+	VisibilityPublic   = "public"
+	VisibilityPrivate  = "private"
+	VisibilityUnlisted = "unlisted"
+)
+
+type Collection struct {
+	ID          int
+	UserID      int
+	Username    string
+	Name        string
+	Description string
+	Visibility  string
+	ShareToken  string
+	EntryCount  int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Entry struct {
+	Film    FilmShort
+	Note    string
+	AddedAt time.Time
+}
+
+type FilmShort struct {
+	ID          int
+	Name        string
+	ReleaseDate time.Time
+}
+
+// Query selects a page of collections, OwnerID of zero and empty
+// Visibility match collections of any user and visibility.
+type Query struct {
+	OwnerID    int
+	Visibility string
+	Limit      int
+	Cursor     *tools.Cursor
+	Backward   bool
+}
+
+type CollectionRepository interface {
+	Get(ctx context.Context, id int) (*Collection, error)
+	GetByShareToken(ctx context.Context, token string) (*Collection, error)
+	GetAll(ctx context.Context, q *Query) ([]*Collection, error)
+	Count(ctx context.Context, q *Query) (int, error)
+	Add(ctx context.Context, c *Collection) (*Collection, error)
+	Update(ctx context.Context, c *Collection) error
+	Delete(ctx context.Context, id int) error
+	SetShareToken(ctx context.Context, id int, token string) error
+	Lock(ctx context.Context, id int) error
+	GetEntries(ctx context.Context, id int) ([]*Entry, error)
+	PutEntry(ctx context.Context, id int, e *Entry) error
+	DeleteEntry(ctx context.Context, id, filmID int) error
+	ReorderEntries(ctx context.Context, id int, filmIDs []int) error
+}
+
+type CollectionService interface {
+	GetPublic(ctx context.Context, req *GetCollectionsRequest) (*GetCollectionsResponse, error)
+	GetOwn(ctx context.Context, req *GetCollectionsRequest) (*GetCollectionsResponse, error)
+	Get(ctx context.Context, req *CollectionIdRequest) (*CollectionResponse, error)
+	GetShared(ctx context.Context, req *ShareTokenRequest) (*CollectionResponse, error)
+	Add(ctx context.Context, req *AddCollectionRequest) (*CollectionResponse, error)
+	Update(ctx context.Context, req *CollectionIdInfoRequest) (*CollectionResponse, error)
+	Delete(ctx context.Context, req *CollectionIdRequest) (*CollectionResponse, error)
+	ResetShareLink(ctx context.Context, req *CollectionIdRequest) (*CollectionResponse, error)
+	PutEntry(ctx context.Context, req *EntryRequest) (*CollectionResponse, error)
+	DeleteEntry(ctx context.Context, req *EntryRequest) (*CollectionResponse, error)
+	ReorderEntries(ctx context.Context, req *ReorderRequest) (*CollectionResponse, error)
+}
+
+type CollectionHandler interface {
+	GetPublic(w http.ResponseWriter, r *http.Request)
+	GetOwn(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	GetShared(w http.ResponseWriter, r *http.Request)
+	Add(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	ResetShareLink(w http.ResponseWriter, r *http.Request)
+	PutEntry(w http.ResponseWriter, r *http.Request)
+	DeleteEntry(w http.ResponseWriter, r *http.Request)
+	ReorderEntries(w http.ResponseWriter, r *http.Request)
+}
+
+type CollectionInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
+}
+
+type CollectionOwner struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+type FilmShortResponse struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ReleaseDate string `json:"releasedate"`
+}
+
+type EntryInfo struct {
+	Note string `json:"note"`
+}
+
+type EntryResponse struct {
+	Position int               `json:"position"`
+	Film     FilmShortResponse `json:"film"`
+	Note     string            `json:"note,omitempty"`
+	AddedAt  time.Time         `json:"addedAt"`
+}
+
+// CollectionResponse carries ShareToken only when sent to the owner and
+// Entries only when a single collection is requested.
+type CollectionResponse struct {
+	ID         int              `json:"id"`
+	Owner      CollectionOwner  `json:"owner"`
+	Info       CollectionInfo   `json:"info"`
+	ShareToken string           `json:"shareToken,omitempty"`
+	EntryCount int              `json:"entryCount"`
+	Entries    []*EntryResponse `json:"entries,omitempty"`
+	CreatedAt  time.Time        `json:"createdAt"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
+}
+
+type GetCollectionsRequest struct {
+	UserID      int
+	LimitQuery  string
+	AfterQuery  string
+	BeforeQuery string
+	TotalQuery  string
+}
+
+type GetCollectionsResponse struct {
+	Collections []*CollectionResponse `json:"collections"`
+	NextCursor  string                `json:"nextCursor,omitempty"`
+	PrevCursor  string                `json:"prevCursor,omitempty"`
+	Total       *int                  `json:"total,omitempty"`
+}
+
+// CollectionIdRequest identifies a collection together with the user
+// acting on it.
+type CollectionIdRequest struct {
+	ID     string
+	UserID int
+}
+
+type ShareTokenRequest struct {
+	Token string
+}
+
+type AddCollectionRequest struct {
+	UserID int
+	Info   CollectionInfo
+}
+
+type CollectionIdInfoRequest struct {
+	ID     string
+	UserID int
+	Info   CollectionInfo
+}
+
+type EntryRequest struct {
+	ID     string
+	FilmID string
+	UserID int
+	Info   EntryInfo
+}
+
+type OrderInfo struct {
+	FilmIDs []int `json:"filmIds"`
+}
+
+type ReorderRequest struct {
+	ID     string
+	UserID int
+	Info   OrderInfo
+}
@@ -0,0 +1,113 @@
+package collection
+
+import (
+	"strings"
+	"time"
+
+	"film-library/src/internal/tools"
+)
+
+// ToCollectionResponse hides the share token from everyone except the
+// owner of the collection.
+func ToCollectionResponse(c *Collection, entries []*Entry, isOwner bool) *CollectionResponse {
+	res := &CollectionResponse{
+		ID: c.ID,
+		Owner: CollectionOwner{
+			ID:       c.UserID,
+			Username: c.Username,
+		},
+		Info: CollectionInfo{
+			Name:        c.Name,
+			Description: c.Description,
+			Visibility:  c.Visibility,
+		},
+		EntryCount: c.EntryCount,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+	}
+
+	if isOwner {
+		res.ShareToken = c.ShareToken
+	}
+
+	if entries != nil {
+		res.EntryCount = len(entries)
+		res.Entries = make([]*EntryResponse, 0, len(entries))
+		for i, v := range entries {
+			res.Entries = append(res.Entries, &EntryResponse{
+				Position: i + 1,
+				Film: FilmShortResponse{
+					ID:          v.Film.ID,
+					Name:        v.Film.Name,
+					ReleaseDate: v.Film.ReleaseDate.Format(time.DateOnly),
+				},
+				Note:    v.Note,
+				AddedAt: v.AddedAt,
+			})
+		}
+	}
+
+	return res
+}
+
+func ToCollection(ci *CollectionInfo) *Collection {
+	visibility := ci.Visibility
+	if len(visibility) == 0 {
+		visibility = VisibilityPrivate
+	}
+
+	return &Collection{
+		Name:        strings.TrimSpace(ci.Name),
+		Description: strings.TrimSpace(ci.Description),
+		Visibility:  visibility,
+	}
+}
+
+// ToFilterConditions returns conditions selecting collections that match
+// q regardless of the page requested.
+func ToFilterConditions(q *Query) tools.Cond {
+	var conds []tools.Cond
+
+	if q.OwnerID != 0 {
+		conds = append(conds, tools.Expr("c.user_id = ?", q.OwnerID))
+	}
+
+	if len(q.Visibility) != 0 {
+		conds = append(conds, tools.Expr("c.visibility = ?", q.Visibility))
+	}
+
+	return tools.And(conds...)
+}
+
+// ToQueryConditions applies filters, keyset position, ordering and limit
+// of q to the collections query, newest collections come first.
+func ToQueryConditions(q *Query, qb *tools.SelectBuilder) *tools.SelectBuilder {
+	qb.Where(ToFilterConditions(q))
+
+	cmp, order := "<", "DESC"
+	if q.Backward {
+		cmp, order = ">", "ASC"
+	}
+
+	if q.Cursor != nil {
+		qb.Where(tools.Expr("c.collection_id "+cmp+" ?", q.Cursor.ID))
+	}
+
+	return qb.OrderBy("c.collection_id " + order).Limit(q.Limit)
+}
+
+func ToQuery(req *GetCollectionsRequest) *Query {
+	limit, cursor, backward := tools.ToPageQuery(req.LimitQuery, req.AfterQuery, req.BeforeQuery)
+
+	return &Query{
+		Limit:    limit,
+		Cursor:   cursor,
+		Backward: backward,
+	}
+}
+
+func ToCollectionCursor(c *Collection) *tools.Cursor {
+	return &tools.Cursor{
+		ID: c.ID,
+	}
+}
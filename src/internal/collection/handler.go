@@ -0,0 +1,424 @@
+package collection
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+var _ CollectionHandler = (*Handler)(nil)
+
+type Handler struct {
+	service CollectionService
+}
+
+func NewHandler(cs CollectionService) *Handler {
+	return &Handler{
+		service: cs,
+	}
+}
+
+func (h *Handler) GetPublic(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	res, err := h.service.GetPublic(r.Context(), &GetCollectionsRequest{
+		UserID:      uc.ID,
+		LimitQuery:  r.URL.Query().Get("limit"),
+		AfterQuery:  r.URL.Query().Get("after"),
+		BeforeQuery: r.URL.Query().Get("before"),
+		TotalQuery:  r.URL.Query().Get("total"),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to get public collections err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetOwn(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	res, err := h.service.GetOwn(r.Context(), &GetCollectionsRequest{
+		UserID:      uc.ID,
+		LimitQuery:  r.URL.Query().Get("limit"),
+		AfterQuery:  r.URL.Query().Get("after"),
+		BeforeQuery: r.URL.Query().Get("before"),
+		TotalQuery:  r.URL.Query().Get("total"),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to get own collections err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := CollectionIdRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+
+	res, err := h.service.Get(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get collection err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrCollectionNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetShared(w http.ResponseWriter, r *http.Request) {
+	req := ShareTokenRequest{
+		Token: r.PathValue("token"),
+	}
+
+	res, err := h.service.GetShared(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get shared collection err=%s\n", err.Error())
+
+		if errors.Is(err, ErrCollectionNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := AddCollectionRequest{
+		UserID: uc.ID,
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.Add(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to add collection err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := CollectionIdInfoRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.Update(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to update collection err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrCollectionNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrForbidden) {
+			tools.Forbidden(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := CollectionIdRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+
+	res, err := h.service.Delete(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete collection err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrCollectionNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrForbidden) {
+			tools.Forbidden(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) ResetShareLink(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := CollectionIdRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+
+	res, err := h.service.ResetShareLink(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to reset collection share link err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrCollectionNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrForbidden) {
+			tools.Forbidden(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) PutEntry(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := EntryRequest{
+		ID:     r.PathValue("id"),
+		FilmID: r.PathValue("filmId"),
+		UserID: uc.ID,
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.PutEntry(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to put film into collection err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrCollectionNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrForbidden) {
+			tools.Forbidden(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrFilmNotExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "film with given id does not exist",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := EntryRequest{
+		ID:     r.PathValue("id"),
+		FilmID: r.PathValue("filmId"),
+		UserID: uc.ID,
+	}
+
+	res, err := h.service.DeleteEntry(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to remove film from collection err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrCollectionNotExist) || errors.Is(err, ErrEntryNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrForbidden) {
+			tools.Forbidden(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) ReorderEntries(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := ReorderRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.ReorderEntries(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to reorder collection err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrCollectionNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrForbidden) {
+			tools.Forbidden(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
@@ -0,0 +1,441 @@
+package collection
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/tools"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrCollectionNotExist = errors.New("collection does not exist")
+	ErrEntryNotExist      = errors.New("film is not in the collection")
+	ErrFilmNotExist       = errors.New("film with given id does not exist")
+)
+
+const collectionColumns = `
+	c.collection_id, c.user_id, u.user_name, c.collection_name, c.collection_description,
+	c.visibility, c.share_token, c.created_at, c.updated_at,
+	(SELECT COUNT(*) FROM collection_entry ce WHERE ce.collection_id = c.collection_id) entry_count`
+
+var _ CollectionRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) Get(ctx context.Context, id int) (*Collection, error) {
+	const op = "collection.Repository.Get"
+
+	const query = `
+		SELECT ` + collectionColumns + `
+		FROM collection c
+		INNER JOIN users u USING (user_id)
+		WHERE c.collection_id = $1`
+
+	c, err := r.getOne(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c, nil
+}
+
+func (r *Repository) GetByShareToken(ctx context.Context, token string) (*Collection, error) {
+	const op = "collection.Repository.GetByShareToken"
+
+	const query = `
+		SELECT ` + collectionColumns + `
+		FROM collection c
+		INNER JOIN users u USING (user_id)
+		WHERE c.share_token = $1`
+
+	c, err := r.getOne(ctx, query, token)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c, nil
+}
+
+func (r *Repository) getOne(ctx context.Context, query string, arg any) (*Collection, error) {
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var c Collection
+	err = stmt.QueryRowContext(ctx, arg).Scan(&c.ID, &c.UserID, &c.Username, &c.Name, &c.Description,
+		&c.Visibility, &c.ShareToken, &c.CreatedAt, &c.UpdatedAt, &c.EntryCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: collection does not exist\n")
+			return nil, ErrCollectionNotExist
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (r *Repository) GetAll(ctx context.Context, q *Query) ([]*Collection, error) {
+	const op = "collection.Repository.GetAll"
+
+	qb := tools.NewSelectBuilder(`
+		SELECT ` + collectionColumns + `
+		FROM collection c
+		INNER JOIN users u USING (user_id)`)
+	query, args := ToQueryConditions(q, qb).Build()
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var collections []*Collection
+
+	for rows.Next() {
+		var c Collection
+		err := rows.Scan(&c.ID, &c.UserID, &c.Username, &c.Name, &c.Description,
+			&c.Visibility, &c.ShareToken, &c.CreatedAt, &c.UpdatedAt, &c.EntryCount)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		collections = append(collections, &c)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return collections, nil
+}
+
+func (r *Repository) Count(ctx context.Context, q *Query) (int, error) {
+	const op = "collection.Repository.Count"
+
+	qb := tools.NewSelectBuilder(`SELECT COUNT(*) FROM collection c`)
+	query, args := qb.Where(ToFilterConditions(q)).Build()
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var count int
+	err = stmt.QueryRowContext(ctx, args...).Scan(&count)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *Repository) Add(ctx context.Context, c *Collection) (*Collection, error) {
+	const op = "collection.Repository.Add"
+
+	const query = `
+		INSERT INTO collection(user_id, collection_name, collection_description, visibility, share_token)
+		VALUES ($1, $2, $3, $4, $5) RETURNING collection_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, c.UserID, c.Name, c.Description, c.Visibility, c.ShareToken).Scan(&c.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c, nil
+}
+
+func (r *Repository) Update(ctx context.Context, c *Collection) error {
+	const op = "collection.Repository.Update"
+
+	const query = `
+		UPDATE collection
+		SET collection_name = $1, collection_description = $2, visibility = $3, updated_at = now()
+		WHERE collection_id = $4`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, c.Name, c.Description, c.Visibility, c.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by update\n")
+		return fmt.Errorf("%s: %w", op, ErrCollectionNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	const op = "collection.Repository.Delete"
+
+	const query = `DELETE FROM collection WHERE collection_id = $1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by delete\n")
+		return fmt.Errorf("%s: %w", op, ErrCollectionNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) SetShareToken(ctx context.Context, id int, token string) error {
+	const op = "collection.Repository.SetShareToken"
+
+	const query = `UPDATE collection SET share_token = $1 WHERE collection_id = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, token, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by update\n")
+		return fmt.Errorf("%s: %w", op, ErrCollectionNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetEntries(ctx context.Context, id int) ([]*Entry, error) {
+	const op = "collection.Repository.GetEntries"
+
+	const query = `
+		SELECT m.movie_id, m.movie_name, m.releasedate, ce.note, ce.added_at
+		FROM collection_entry ce
+		INNER JOIN movie m USING (movie_id)
+		WHERE ce.collection_id = $1 AND m.deleted_at IS NULL
+		ORDER BY ce.position`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	entries := make([]*Entry, 0)
+
+	for rows.Next() {
+		var e Entry
+		err := rows.Scan(&e.Film.ID, &e.Film.Name, &e.Film.ReleaseDate, &e.Note, &e.AddedAt)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// Lock locks the collection for the rest of the transaction, so that its
+// entries are changed by one request at a time.
+func (r *Repository) Lock(ctx context.Context, id int) error {
+	const op = "collection.Repository.Lock"
+
+	const query = `SELECT collection_id FROM collection WHERE collection_id = $1 FOR UPDATE`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var locked int
+	err = stmt.QueryRowContext(ctx, id).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: collection with id=%d does not exist\n", id)
+			return fmt.Errorf("%s: %w", op, ErrCollectionNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PutEntry appends the film to the end of the collection or, when it is
+// already there, replaces its note keeping the position.
+func (r *Repository) PutEntry(ctx context.Context, id int, e *Entry) error {
+	const op = "collection.Repository.PutEntry"
+
+	const query = `
+		INSERT INTO collection_entry(collection_id, movie_id, position, note)
+		SELECT $1, m.movie_id, (
+			SELECT COALESCE(MAX(position), 0) + 1 FROM collection_entry WHERE collection_id = $1
+		), $3
+		FROM movie m WHERE m.movie_id = $2 AND m.deleted_at IS NULL
+		ON CONFLICT (collection_id, movie_id) DO UPDATE SET note = EXCLUDED.note`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id, e.Film.ID, e.Note)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: film with id=%d does not exist\n", e.Film.ID)
+		return fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteEntry(ctx context.Context, id, filmID int) error {
+	const op = "collection.Repository.DeleteEntry"
+
+	const query = `DELETE FROM collection_entry WHERE collection_id = $1 AND movie_id = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id, filmID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by delete\n")
+		return fmt.Errorf("%s: %w", op, ErrEntryNotExist)
+	}
+
+	return nil
+}
+
+// ReorderEntries assigns positions to the entries following the order of
+// filmIDs in a single statement, so positions may be swapped freely. The
+// positions are taken after the last one in use, as entries of trashed films
+// are not listed in filmIDs and keep theirs.
+func (r *Repository) ReorderEntries(ctx context.Context, id int, filmIDs []int) error {
+	const op = "collection.Repository.ReorderEntries"
+
+	const query = `
+		UPDATE collection_entry ce SET position = l.position + o.position
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(movie_id, position),
+			(SELECT MAX(position) position FROM collection_entry WHERE collection_id = $1) l
+		WHERE ce.collection_id = $1 AND ce.movie_id = o.movie_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, id, pq.Array(filmIDs))
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,471 @@
+package collection
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/tools"
+)
+
+var (
+	ErrIdInvalid = errors.New("invalid id")
+	ErrForbidden = errors.New("collection belongs to another user")
+)
+
+var _ CollectionService = (*Service)(nil)
+
+type Service struct {
+	repo CollectionRepository
+	tx   db.Transactor
+}
+
+func NewService(cr CollectionRepository, tx db.Transactor) *Service {
+	return &Service{
+		repo: cr,
+		tx:   tx,
+	}
+}
+
+func (s *Service) GetPublic(ctx context.Context, req *GetCollectionsRequest) (*GetCollectionsResponse, error) {
+	const op = "collection.Service.GetPublic"
+
+	vErr := ValidateGetCollectionsRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	q := ToQuery(req)
+	q.Visibility = VisibilityPublic
+
+	res, err := s.getPage(ctx, q, req.TotalQuery == "true", req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) GetOwn(ctx context.Context, req *GetCollectionsRequest) (*GetCollectionsResponse, error) {
+	const op = "collection.Service.GetOwn"
+
+	vErr := ValidateGetCollectionsRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	q := ToQuery(req)
+	q.OwnerID = req.UserID
+
+	res, err := s.getPage(ctx, q, req.TotalQuery == "true", req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) getPage(ctx context.Context, q *Query, total bool, userID int) (*GetCollectionsResponse, error) {
+	// one extra collection is requested to find out whether the next page exists
+	limit := q.Limit
+	q.Limit = limit + 1
+
+	collections, err := s.repo.GetAll(ctx, q)
+	if err != nil {
+		log.Printf("ERROR: failed to get collections from repository\n")
+		return nil, err
+	}
+
+	hasMore := len(collections) > limit
+	if hasMore {
+		collections = collections[:limit]
+	}
+	if q.Backward {
+		slices.Reverse(collections)
+	}
+
+	res := &GetCollectionsResponse{
+		Collections: make([]*CollectionResponse, 0, len(collections)),
+	}
+	for _, v := range collections {
+		res.Collections = append(res.Collections, ToCollectionResponse(v, nil, v.UserID == userID))
+	}
+
+	next, prev := tools.PageLinks(hasMore, q.Backward, q.Cursor != nil)
+	if len(collections) != 0 {
+		if next {
+			res.NextCursor = tools.EncodeCursor(ToCollectionCursor(collections[len(collections)-1]))
+		}
+		if prev {
+			res.PrevCursor = tools.EncodeCursor(ToCollectionCursor(collections[0]))
+		}
+	}
+
+	if total {
+		count, err := s.repo.Count(ctx, q)
+		if err != nil {
+			log.Printf("ERROR: failed to count collections in repository\n")
+			return nil, err
+		}
+		res.Total = &count
+	}
+
+	return res, nil
+}
+
+// Get returns the collection to its owner or, when it is public, to
+// anyone else. Unlisted collections are only reachable by share link.
+func (s *Service) Get(ctx context.Context, req *CollectionIdRequest) (*CollectionResponse, error) {
+	const op = "collection.Service.Get"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	c, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get collection from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	isOwner := c.UserID == req.UserID
+	if !isOwner && c.Visibility != VisibilityPublic {
+		log.Printf("ERROR: collection with id=%d is not visible to user with id=%d\n", c.ID, req.UserID)
+		return nil, fmt.Errorf("%s: %w", op, ErrCollectionNotExist)
+	}
+
+	res, err := s.withEntries(ctx, c, isOwner)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// GetShared returns a read-only view of the collection behind a share
+// link. Private collections are not shared even if the link is known.
+func (s *Service) GetShared(ctx context.Context, req *ShareTokenRequest) (*CollectionResponse, error) {
+	const op = "collection.Service.GetShared"
+
+	c, err := s.repo.GetByShareToken(ctx, req.Token)
+	if err != nil {
+		log.Printf("ERROR: failed to get collection from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if c.Visibility == VisibilityPrivate {
+		log.Printf("ERROR: collection with id=%d is private\n", c.ID)
+		return nil, fmt.Errorf("%s: %w", op, ErrCollectionNotExist)
+	}
+
+	res, err := s.withEntries(ctx, c, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) Add(ctx context.Context, req *AddCollectionRequest) (*CollectionResponse, error) {
+	const op = "collection.Service.Add"
+
+	vErr := ValidateCollectionInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		log.Printf("ERROR: failed to generate share token\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c := ToCollection(&req.Info)
+	c.UserID = req.UserID
+	c.ShareToken = token
+
+	c, err = s.repo.Add(ctx, c)
+	if err != nil {
+		log.Printf("ERROR: failed to add collection\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c, err = s.repo.Get(ctx, c.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to get collection from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToCollectionResponse(c, []*Entry{}, true)
+
+	return res, nil
+}
+
+func (s *Service) Update(ctx context.Context, req *CollectionIdInfoRequest) (*CollectionResponse, error) {
+	const op = "collection.Service.Update"
+
+	c, err := s.getOwned(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	vErr := ValidateCollectionInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	upd := ToCollection(&req.Info)
+	upd.ID = c.ID
+
+	err = s.repo.Update(ctx, upd)
+	if err != nil {
+		log.Printf("ERROR: failed to update collection\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.reload(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) Delete(ctx context.Context, req *CollectionIdRequest) (*CollectionResponse, error) {
+	const op = "collection.Service.Delete"
+
+	c, err := s.getOwned(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repo.Delete(ctx, c.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to delete collection\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToCollectionResponse(c, nil, true)
+
+	return res, nil
+}
+
+// ResetShareLink replaces the share token so that previously handed out
+// links stop working.
+func (s *Service) ResetShareLink(ctx context.Context, req *CollectionIdRequest) (*CollectionResponse, error) {
+	const op = "collection.Service.ResetShareLink"
+
+	c, err := s.getOwned(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		log.Printf("ERROR: failed to generate share token\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repo.SetShareToken(ctx, c.ID, token)
+	if err != nil {
+		log.Printf("ERROR: failed to update share token\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.reload(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) PutEntry(ctx context.Context, req *EntryRequest) (*CollectionResponse, error) {
+	const op = "collection.Service.PutEntry"
+
+	filmID, err := strconv.ParseUint(req.FilmID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed film id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	c, err := s.getOwned(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	vErr := ValidateEntryInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	e := &Entry{
+		Film: FilmShort{
+			ID: int(filmID),
+		},
+		Note: req.Info.Note,
+	}
+	// the entry is appended after the last position, which must not change
+	// until it is added
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Lock(ctx, c.ID); err != nil {
+			log.Printf("ERROR: failed to lock collection\n")
+			return err
+		}
+
+		if err := s.repo.PutEntry(ctx, c.ID, e); err != nil {
+			log.Printf("ERROR: failed to put film into collection\n")
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.reload(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) DeleteEntry(ctx context.Context, req *EntryRequest) (*CollectionResponse, error) {
+	const op = "collection.Service.DeleteEntry"
+
+	filmID, err := strconv.ParseUint(req.FilmID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed film id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	c, err := s.getOwned(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repo.DeleteEntry(ctx, c.ID, int(filmID))
+	if err != nil {
+		log.Printf("ERROR: failed to remove film from collection\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.reload(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) ReorderEntries(ctx context.Context, req *ReorderRequest) (*CollectionResponse, error) {
+	const op = "collection.Service.ReorderEntries"
+
+	c, err := s.getOwned(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// entries are not added or removed between validating the new order
+	// and applying it
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Lock(ctx, c.ID); err != nil {
+			log.Printf("ERROR: failed to lock collection\n")
+			return err
+		}
+
+		entries, err := s.repo.GetEntries(ctx, c.ID)
+		if err != nil {
+			log.Printf("ERROR: failed to get collection entries from repository\n")
+			return err
+		}
+
+		vErr := ValidateReorder(req.Info.FilmIDs, entries)
+		if vErr != nil {
+			log.Printf("ERROR: failed request validation\n")
+			return vErr
+		}
+
+		if err := s.repo.ReorderEntries(ctx, c.ID, req.Info.FilmIDs); err != nil {
+			log.Printf("ERROR: failed to reorder collection entries\n")
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.reload(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// getOwned returns the collection with id given as string, failing with
+// ErrForbidden when it belongs to a user other than userID.
+func (s *Service) getOwned(ctx context.Context, idStr string, userID int) (*Collection, error) {
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, ErrIdInvalid
+	}
+
+	c, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get collection from repository\n")
+		return nil, err
+	}
+
+	if c.UserID != userID {
+		log.Printf("ERROR: collection with id=%d belongs to another user\n", c.ID)
+		return nil, ErrForbidden
+	}
+
+	return c, nil
+}
+
+func (s *Service) reload(ctx context.Context, id int) (*CollectionResponse, error) {
+	c, err := s.repo.Get(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to get collection from repository\n")
+		return nil, err
+	}
+
+	return s.withEntries(ctx, c, true)
+}
+
+func (s *Service) withEntries(ctx context.Context, c *Collection, isOwner bool) (*CollectionResponse, error) {
+	entries, err := s.repo.GetEntries(ctx, c.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to get collection entries from repository\n")
+		return nil, err
+	}
+
+	return ToCollectionResponse(c, entries, isOwner), nil
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
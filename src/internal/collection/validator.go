@@ -0,0 +1,99 @@
+package collection
+
+import (
+	"strings"
+
+	"film-library/src/internal/tools"
+)
+
+var visibilityMap = map[string]struct{}{
+	VisibilityPublic:   {},
+	VisibilityPrivate:  {},
+	VisibilityUnlisted: {},
+}
+
+func ValidateGetCollectionsRequest(req *GetCollectionsRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	tools.ValidatePageQuery(ve, req.LimitQuery, req.AfterQuery, req.BeforeQuery, req.TotalQuery)
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateCollectionInfo(ci *CollectionInfo) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(strings.TrimSpace(ci.Name)) == 0 {
+		ve.AddViolation("name empty")
+	}
+
+	if len(ci.Name) > 100 {
+		ve.AddViolation("name length is more than 100 symbols")
+	}
+
+	if len(ci.Description) > 1000 {
+		ve.AddViolation("description length is more than 1000 symbols")
+	}
+
+	if _, ok := visibilityMap[ci.Visibility]; !ok && len(ci.Visibility) != 0 {
+		ve.AddViolation("incorrect visibility, expected one of [public, private, unlisted]")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateEntryInfo(ei *EntryInfo) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(ei.Note) > 500 {
+		ve.AddViolation("note length is more than 500 symbols")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+// ValidateReorder checks that filmIDs lists every entry of the collection
+// exactly once.
+func ValidateReorder(filmIDs []int, entries []*Entry) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	current := make(map[int]bool, len(entries))
+	for _, v := range entries {
+		current[v.Film.ID] = true
+	}
+
+	seen := make(map[int]bool, len(filmIDs))
+	for _, v := range filmIDs {
+		if !current[v] {
+			ve.AddViolation("film is not in the collection")
+			break
+		}
+		if seen[v] {
+			ve.AddViolation("film listed more than once")
+			break
+		}
+		seen[v] = true
+	}
+
+	if ve.NoViolations() && len(seen) != len(current) {
+		ve.AddViolation("new order must list every film of the collection")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/caarlos0/env/v10"
 	"log"
 	"net"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +14,22 @@ type Config struct {
 	DatabaseURL string `env:"DATABASE_URL" env-required:"true"`
 	DocsHTML    string `env:"DOCS_HTML" env-required:"true"`
 	DocsYAML    string `env:"DOCS_YAML" env-required:"true"`
+	// MediaDir keeps uploaded images and is served under /media/, MediaURL
+	// is the base of public image URLs and may point to another host.
+	MediaDir      string `env:"MEDIA_DIR" envDefault:"media"`
+	MediaURL      string `env:"MEDIA_URL" envDefault:"/media"`
+	MaxUploadSize int64  `env:"MAX_UPLOAD_SIZE" envDefault:"10485760"`
+	// TrashRetention is how long deleted films and actors stay restorable
+	// before a purge removes them.
+	TrashRetention time.Duration `env:"TRASH_RETENTION" envDefault:"720h"`
+	// IdempotencyTTL is how long the response to a request sent with an
+	// Idempotency-Key header is replayed for the same key.
+	IdempotencyTTL time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+	// IdempotencyLease is how long a request sent with an Idempotency-Key
+	// header holds the key while it is in progress.
+	IdempotencyLease time.Duration `env:"IDEMPOTENCY_LEASE" envDefault:"1m"`
+	// IdempotencySweep is how often expired idempotency keys are deleted.
+	IdempotencySweep time.Duration `env:"IDEMPOTENCY_SWEEP" envDefault:"1h"`
 }
 
 func (c *Config) Addr() string {
@@ -35,3 +35,12 @@ func (d *Database) Close() {
 func (d *Database) GetDB() *sql.DB {
 	return d.db
 }
+
+// GetContextDB returns a DBTX joining transactions of TxManager.
+func (d *Database) GetContextDB() *ContextDB {
+	return NewContextDB(d.db)
+}
+
+func (d *Database) GetTxManager() *TxManager {
+	return NewTxManager(d.db)
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS movie_name_id_idx;
+DROP INDEX IF EXISTS movie_rating_id_idx;
+DROP INDEX IF EXISTS movie_releasedate_id_idx;
\ No newline at end of file
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS movie_name_id_idx ON movie(movie_name, movie_id);
+CREATE INDEX IF NOT EXISTS movie_rating_id_idx ON movie(rating, movie_id);
+CREATE INDEX IF NOT EXISTS movie_releasedate_id_idx ON movie(releasedate, movie_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS actor_in_movie_movie_id_idx;
\ No newline at end of file
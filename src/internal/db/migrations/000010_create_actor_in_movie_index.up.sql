@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS actor_in_movie_movie_id_idx ON actor_in_movie(movie_id);
\ No newline at end of file
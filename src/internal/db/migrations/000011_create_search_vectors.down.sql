@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS actor_search_vector_idx;
+ALTER TABLE actor DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS movie_search_vector_idx;
+ALTER TABLE movie DROP COLUMN IF EXISTS search_vector;
\ No newline at end of file
@@ -0,0 +1,12 @@
+ALTER TABLE movie ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', movie_name), 'A') ||
+        setweight(to_tsvector('english', movie_description), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS movie_search_vector_idx ON movie USING GIN (search_vector);
+
+ALTER TABLE actor ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', actor_name)) STORED;
+
+CREATE INDEX IF NOT EXISTS actor_search_vector_idx ON actor USING GIN (search_vector);
\ No newline at end of file
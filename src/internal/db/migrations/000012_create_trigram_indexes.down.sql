@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS actor_name_trgm_idx;
+DROP INDEX IF EXISTS movie_name_trgm_idx;
\ No newline at end of file
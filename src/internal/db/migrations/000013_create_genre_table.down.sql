@@ -0,0 +1 @@
+DROP TABLE IF EXISTS genre;
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS genre(
+    genre_id SERIAL PRIMARY KEY,
+    genre_name VARCHAR NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS genre_name_idx ON genre(LOWER(genre_name));
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS movie_genre;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS movie_genre(
+    movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    genre_id INT NOT NULL REFERENCES genre(genre_id) ON DELETE CASCADE,
+    PRIMARY KEY (movie_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS movie_genre_genre_id_idx ON movie_genre(genre_id);
\ No newline at end of file
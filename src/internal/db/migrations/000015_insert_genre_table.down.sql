@@ -0,0 +1,2 @@
+DELETE FROM genre
+WHERE genre_name IN ('action', 'comedy', 'drama', 'horror', 'thriller');
\ No newline at end of file
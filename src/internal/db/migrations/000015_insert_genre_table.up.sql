@@ -0,0 +1,7 @@
+INSERT INTO genre(genre_name)
+VALUES
+    ('action'),
+    ('comedy'),
+    ('drama'),
+    ('horror'),
+    ('thriller');
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS movie_crew;
+
+COMMENT ON TABLE actor IS NULL;
\ No newline at end of file
@@ -0,0 +1,10 @@
+COMMENT ON TABLE actor IS 'people credited in movies, either as cast or as crew';
+
+CREATE TABLE IF NOT EXISTS movie_crew(
+    person_id INT NOT NULL REFERENCES actor(actor_id) ON DELETE CASCADE,
+    movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    crew_role VARCHAR NOT NULL CHECK (crew_role IN ('director', 'writer', 'producer', 'composer', 'cinematographer')),
+    PRIMARY KEY (person_id, movie_id, crew_role)
+);
+
+CREATE INDEX IF NOT EXISTS movie_crew_movie_id_idx ON movie_crew(movie_id);
\ No newline at end of file
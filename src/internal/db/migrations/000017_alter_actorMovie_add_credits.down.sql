@@ -0,0 +1,3 @@
+ALTER TABLE actor_in_movie
+    DROP COLUMN IF EXISTS billing,
+    DROP COLUMN IF EXISTS characters;
\ No newline at end of file
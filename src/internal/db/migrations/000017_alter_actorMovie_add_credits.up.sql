@@ -0,0 +1,3 @@
+ALTER TABLE actor_in_movie
+    ADD COLUMN IF NOT EXISTS characters VARCHAR[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS billing INT CHECK (billing > 0);
\ No newline at end of file
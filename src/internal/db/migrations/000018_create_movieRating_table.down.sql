@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS movie_rating_stats_trigger ON movie_rating;
+DROP FUNCTION IF EXISTS update_movie_rating_stats();
+DROP TABLE IF EXISTS movie_rating_stats;
+DROP TABLE IF EXISTS movie_rating;
\ No newline at end of file
@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS movie_rating(
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 10),
+    rated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS movie_rating_movie_id_idx ON movie_rating(movie_id);
+
+-- histogram[i] holds the number of ratings equal to i
+CREATE TABLE IF NOT EXISTS movie_rating_stats(
+    movie_id INT PRIMARY KEY REFERENCES movie(movie_id) ON DELETE CASCADE,
+    rating_count INT NOT NULL DEFAULT 0,
+    rating_sum INT NOT NULL DEFAULT 0,
+    histogram INT[] NOT NULL DEFAULT array_fill(0, ARRAY[10]),
+    rating_average NUMERIC(4, 2) GENERATED ALWAYS AS (
+        CASE WHEN rating_count = 0 THEN 0 ELSE rating_sum::NUMERIC / rating_count END
+    ) STORED
+);
+
+CREATE INDEX IF NOT EXISTS movie_rating_stats_average_idx ON movie_rating_stats(rating_average, movie_id);
+
+CREATE OR REPLACE FUNCTION update_movie_rating_stats() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE movie_rating_stats SET
+            rating_count = rating_count - 1,
+            rating_sum = rating_sum - OLD.rating,
+            histogram[OLD.rating] = histogram[OLD.rating] - 1
+        WHERE movie_id = OLD.movie_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO movie_rating_stats(movie_id) VALUES (NEW.movie_id)
+        ON CONFLICT (movie_id) DO NOTHING;
+
+        UPDATE movie_rating_stats SET
+            rating_count = rating_count + 1,
+            rating_sum = rating_sum + NEW.rating,
+            histogram[NEW.rating] = histogram[NEW.rating] + 1
+        WHERE movie_id = NEW.movie_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER movie_rating_stats_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON movie_rating
+    FOR EACH ROW EXECUTE FUNCTION update_movie_rating_stats();
\ No newline at end of file
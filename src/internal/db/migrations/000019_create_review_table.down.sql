@@ -0,0 +1 @@
+DROP TABLE IF EXISTS review;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS review(
+    review_id SERIAL PRIMARY KEY,
+    movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    title VARCHAR(150) NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    moderated_by INT REFERENCES users(user_id) ON DELETE SET NULL,
+    moderated_at TIMESTAMPTZ,
+    UNIQUE (user_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS review_movie_id_status_idx ON review(movie_id, status, review_id);
+CREATE INDEX IF NOT EXISTS review_status_idx ON review(status, review_id);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS diary_entry;
+DROP TABLE IF EXISTS watchlist;
\ No newline at end of file
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS watchlist(
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS watchlist_added_at_idx ON watchlist(user_id, added_at, movie_id);
+
+CREATE TABLE IF NOT EXISTS diary_entry(
+    entry_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    watched_on DATE NOT NULL,
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 10),
+    rewatch BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS diary_entry_watched_on_idx ON diary_entry(user_id, watched_on, entry_id);
+CREATE INDEX IF NOT EXISTS diary_entry_movie_id_idx ON diary_entry(user_id, movie_id);
\ No newline at end of file
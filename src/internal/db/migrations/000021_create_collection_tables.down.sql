@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS collection_entry;
+DROP TABLE IF EXISTS collection;
\ No newline at end of file
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS collection(
+    collection_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    collection_name VARCHAR(100) NOT NULL,
+    collection_description VARCHAR(1000) NOT NULL DEFAULT '',
+    visibility VARCHAR NOT NULL DEFAULT 'private' CHECK (visibility IN ('public', 'private', 'unlisted')),
+    share_token VARCHAR NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS collection_user_id_idx ON collection(user_id, collection_id);
+CREATE INDEX IF NOT EXISTS collection_visibility_idx ON collection(visibility, collection_id);
+
+-- positions are only compared with each other, so removing an entry leaves
+-- a gap; the unique constraint is deferrable to let reordering swap them
+CREATE TABLE IF NOT EXISTS collection_entry(
+    collection_id INT NOT NULL REFERENCES collection(collection_id) ON DELETE CASCADE,
+    movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (collection_id, movie_id),
+    UNIQUE (collection_id, position) DEFERRABLE
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS movie_relation;
+DROP TABLE IF EXISTS franchise_movie;
+DROP TABLE IF EXISTS franchise;
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS franchise(
+    franchise_id SERIAL PRIMARY KEY,
+    franchise_name VARCHAR(150) NOT NULL,
+    franchise_description VARCHAR(1000) NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS franchise_name_idx ON franchise(LOWER(franchise_name));
+
+-- release_order is required, chronological_order is NULL for films whose
+-- place in the in-universe timeline is not configured
+CREATE TABLE IF NOT EXISTS franchise_movie(
+    franchise_id INT NOT NULL REFERENCES franchise(franchise_id) ON DELETE CASCADE,
+    movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    release_order INT NOT NULL CHECK (release_order > 0),
+    chronological_order INT CHECK (chronological_order > 0),
+    PRIMARY KEY (franchise_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS franchise_movie_movie_id_idx ON franchise_movie(movie_id);
+
+-- a row reads as "movie_id is a <relation> related_movie_id"
+CREATE TABLE IF NOT EXISTS movie_relation(
+    movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    related_movie_id INT NOT NULL REFERENCES movie(movie_id) ON DELETE CASCADE,
+    relation VARCHAR NOT NULL CHECK (relation IN ('sequel_of', 'remake_of', 'spin_off_of')),
+    PRIMARY KEY (movie_id, related_movie_id, relation),
+    CHECK (movie_id <> related_movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS movie_relation_related_movie_id_idx ON movie_relation(related_movie_id);
\ No newline at end of file
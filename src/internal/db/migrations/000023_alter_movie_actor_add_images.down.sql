@@ -0,0 +1,7 @@
+ALTER TABLE actor
+    DROP COLUMN IF EXISTS headshot_urls,
+    DROP COLUMN IF EXISTS headshot_key;
+
+ALTER TABLE movie
+    DROP COLUMN IF EXISTS poster_urls,
+    DROP COLUMN IF EXISTS poster_key;
\ No newline at end of file
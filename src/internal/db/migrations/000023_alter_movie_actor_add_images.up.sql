@@ -0,0 +1,9 @@
+-- *_key is the storage prefix of the uploaded image and its thumbnails,
+-- *_urls maps size names (original, small, medium, large) to public URLs
+ALTER TABLE movie
+    ADD COLUMN IF NOT EXISTS poster_key VARCHAR,
+    ADD COLUMN IF NOT EXISTS poster_urls JSONB;
+
+ALTER TABLE actor
+    ADD COLUMN IF NOT EXISTS headshot_key VARCHAR,
+    ADD COLUMN IF NOT EXISTS headshot_urls JSONB;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE actor
+    ALTER COLUMN sex DROP DEFAULT,
+    ALTER COLUMN birthday SET NOT NULL;
\ No newline at end of file
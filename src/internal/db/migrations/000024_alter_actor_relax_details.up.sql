@@ -0,0 +1,3 @@
+ALTER TABLE actor
+    ALTER COLUMN sex SET DEFAULT '',
+    ALTER COLUMN birthday DROP NOT NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE actor DROP COLUMN IF EXISTS imdb_id;
+
+ALTER TABLE movie DROP COLUMN IF EXISTS imdb_id;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- imdb_id keeps tconst of films and nconst of actors loaded from IMDb
+-- datasets, it is NULL for records added otherwise
+ALTER TABLE movie ADD COLUMN IF NOT EXISTS imdb_id VARCHAR UNIQUE;
+
+ALTER TABLE actor ADD COLUMN IF NOT EXISTS imdb_id VARCHAR UNIQUE;
\ No newline at end of file
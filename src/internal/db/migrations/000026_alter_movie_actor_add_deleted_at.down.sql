@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS actor_deleted_at_idx;
+
+DROP INDEX IF EXISTS movie_deleted_at_idx;
+
+ALTER TABLE actor DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE movie DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- deleted_at is set for films and actors moved to the trash, such rows
+-- are hidden from every listing until restored or purged
+ALTER TABLE movie ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE actor ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS movie_deleted_at_idx ON movie(deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS actor_deleted_at_idx ON actor(deleted_at) WHERE deleted_at IS NOT NULL;
\ No newline at end of file
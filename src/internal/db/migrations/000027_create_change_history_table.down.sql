@@ -0,0 +1 @@
+DROP TABLE IF EXISTS change_history;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- every change of a film or actor keeps a snapshot of the record as it
+-- was right after the change, film snapshots include credits and genres
+CREATE TABLE IF NOT EXISTS change_history(
+    change_id BIGSERIAL PRIMARY KEY,
+    entity VARCHAR NOT NULL CHECK (entity IN ('film', 'actor')),
+    entity_id INT NOT NULL,
+    action VARCHAR NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'credits', 'revert')),
+    user_id INT REFERENCES users(user_id) ON DELETE SET NULL,
+    snapshot JSONB NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS change_history_entity_idx ON change_history(entity, entity_id, changed_at);
\ No newline at end of file
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS movie_crew_version_trigger ON movie_crew;
+DROP TRIGGER IF EXISTS movie_genre_version_trigger ON movie_genre;
+DROP TRIGGER IF EXISTS actor_in_movie_actor_version_trigger ON actor_in_movie;
+DROP TRIGGER IF EXISTS actor_in_movie_movie_version_trigger ON actor_in_movie;
+DROP FUNCTION IF EXISTS bump_actor_version();
+DROP FUNCTION IF EXISTS bump_movie_version();
+DROP TRIGGER IF EXISTS actor_version_trigger ON actor;
+DROP TRIGGER IF EXISTS movie_version_trigger ON movie;
+DROP FUNCTION IF EXISTS bump_version();
+ALTER TABLE actor DROP COLUMN IF EXISTS version;
+ALTER TABLE movie DROP COLUMN IF EXISTS version;
\ No newline at end of file
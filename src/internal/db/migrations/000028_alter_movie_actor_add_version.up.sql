@@ -0,0 +1,65 @@
+-- version is bumped on every change of a film or an actor, credits, genres
+-- and crew included, and serves as their entity tag
+ALTER TABLE movie ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE actor ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER movie_version_trigger
+    BEFORE UPDATE ON movie
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+CREATE TRIGGER actor_version_trigger
+    BEFORE UPDATE ON actor
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+CREATE OR REPLACE FUNCTION bump_movie_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE movie SET version = version + 1 WHERE movie_id = OLD.movie_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE movie SET version = version + 1 WHERE movie_id = NEW.movie_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION bump_actor_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE actor SET version = version + 1 WHERE actor_id = OLD.actor_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE actor SET version = version + 1 WHERE actor_id = NEW.actor_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER actor_in_movie_movie_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON actor_in_movie
+    FOR EACH ROW EXECUTE FUNCTION bump_movie_version();
+
+CREATE TRIGGER actor_in_movie_actor_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON actor_in_movie
+    FOR EACH ROW EXECUTE FUNCTION bump_actor_version();
+
+CREATE TRIGGER movie_genre_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON movie_genre
+    FOR EACH ROW EXECUTE FUNCTION bump_movie_version();
+
+CREATE TRIGGER movie_crew_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON movie_crew
+    FOR EACH ROW EXECUTE FUNCTION bump_movie_version();
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- idempotency_key keeps responses of mutating requests made with an
+-- Idempotency-Key header, keys are scoped to the signed in user or to 0 for
+-- anonymous requests, status_code is NULL while the request is in progress
+-- and locked_until bounds how long it may take before the key is taken over
+CREATE TABLE IF NOT EXISTS idempotency_key(
+    user_id INT NOT NULL,
+    idem_key VARCHAR(255) NOT NULL,
+    fingerprint BYTEA NOT NULL,
+    status_code SMALLINT,
+    header JSONB,
+    body BYTEA,
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, idem_key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_key_expires_at_idx ON idempotency_key(expires_at);
\ No newline at end of file
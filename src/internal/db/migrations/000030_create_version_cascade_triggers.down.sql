@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS movie_references_version_trigger ON movie;
+DROP FUNCTION IF EXISTS bump_movie_references_version();
+DROP TRIGGER IF EXISTS actor_movies_version_trigger ON actor;
+DROP FUNCTION IF EXISTS bump_actor_movies_version();
+DROP TRIGGER IF EXISTS genre_version_trigger ON genre;
+DROP FUNCTION IF EXISTS bump_genre_movies_version();
+DROP TRIGGER IF EXISTS franchise_version_trigger ON franchise;
+DROP FUNCTION IF EXISTS bump_franchise_movies_version();
+DROP TRIGGER IF EXISTS movie_relation_version_trigger ON movie_relation;
+DROP FUNCTION IF EXISTS bump_related_movie_version();
+DROP TRIGGER IF EXISTS franchise_movie_version_trigger ON franchise_movie;
\ No newline at end of file
@@ -0,0 +1,96 @@
+-- version of a film or an actor is also bumped when editable data shown
+-- along with it changes: relations, franchises and names of the actors,
+-- genres and films it refers to. User ratings and reviews are left out as
+-- they are not edited through the film, ETags of films cover them instead
+CREATE TRIGGER franchise_movie_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON franchise_movie
+    FOR EACH ROW EXECUTE FUNCTION bump_movie_version();
+
+CREATE OR REPLACE FUNCTION bump_related_movie_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE movie SET version = version + 1
+        WHERE movie_id IN (OLD.movie_id, OLD.related_movie_id);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE movie SET version = version + 1
+        WHERE movie_id IN (NEW.movie_id, NEW.related_movie_id);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER movie_relation_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON movie_relation
+    FOR EACH ROW EXECUTE FUNCTION bump_related_movie_version();
+
+CREATE OR REPLACE FUNCTION bump_franchise_movies_version() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE movie SET version = version + 1
+    WHERE movie_id IN (SELECT movie_id FROM franchise_movie WHERE franchise_id = NEW.franchise_id);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER franchise_version_trigger
+    AFTER UPDATE OF franchise_name ON franchise
+    FOR EACH ROW WHEN (OLD.franchise_name IS DISTINCT FROM NEW.franchise_name)
+    EXECUTE FUNCTION bump_franchise_movies_version();
+
+CREATE OR REPLACE FUNCTION bump_genre_movies_version() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE movie SET version = version + 1
+    WHERE movie_id IN (SELECT movie_id FROM movie_genre WHERE genre_id = NEW.genre_id);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER genre_version_trigger
+    AFTER UPDATE OF genre_name ON genre
+    FOR EACH ROW WHEN (OLD.genre_name IS DISTINCT FROM NEW.genre_name)
+    EXECUTE FUNCTION bump_genre_movies_version();
+
+-- the triggers below fire on changes of names only, so the version updates
+-- they make do not fire them again
+CREATE OR REPLACE FUNCTION bump_actor_movies_version() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE movie SET version = version + 1
+    WHERE movie_id IN (SELECT movie_id FROM actor_in_movie WHERE actor_id = NEW.actor_id);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER actor_movies_version_trigger
+    AFTER UPDATE OF actor_name, deleted_at ON actor
+    FOR EACH ROW WHEN (OLD.actor_name IS DISTINCT FROM NEW.actor_name OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at)
+    EXECUTE FUNCTION bump_actor_movies_version();
+
+CREATE OR REPLACE FUNCTION bump_movie_references_version() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE actor SET version = version + 1
+    WHERE actor_id IN (SELECT actor_id FROM actor_in_movie WHERE movie_id = NEW.movie_id);
+
+    UPDATE movie SET version = version + 1
+    WHERE movie_id IN (
+        SELECT related_movie_id FROM movie_relation WHERE movie_id = NEW.movie_id
+        UNION
+        SELECT movie_id FROM movie_relation WHERE related_movie_id = NEW.movie_id
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER movie_references_version_trigger
+    AFTER UPDATE OF movie_name, releasedate, deleted_at ON movie
+    FOR EACH ROW WHEN (
+        OLD.movie_name IS DISTINCT FROM NEW.movie_name
+        OR OLD.releasedate IS DISTINCT FROM NEW.releasedate
+        OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at
+    )
+    EXECUTE FUNCTION bump_movie_references_version();
\ No newline at end of file
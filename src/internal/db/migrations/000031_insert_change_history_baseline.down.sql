@@ -0,0 +1,3 @@
+-- baseline versions can not be told apart from the ones recorded later and
+-- are kept
+SELECT 1;
\ No newline at end of file
@@ -0,0 +1,50 @@
+-- films and actors added before the history was kept get their current state
+-- as the first version, so that they have a history to read and revert to
+INSERT INTO change_history(entity, entity_id, action, user_id, snapshot)
+SELECT 'film', m.movie_id, 'create', NULL, jsonb_build_object(
+    'name', m.movie_name,
+    'description', m.movie_description,
+    'releaseDate', m.releasedate,
+    'rating', m.rating,
+    'deleted', m.deleted_at IS NOT NULL,
+    'actors', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', a.actor_id, 'name', a.actor_name,
+            'characters', am.characters, 'billing', COALESCE(am.billing, 0))
+            ORDER BY am.billing NULLS LAST, a.actor_id)
+        FROM actor_in_movie am
+        INNER JOIN actor a USING (actor_id)
+        WHERE am.movie_id = m.movie_id
+    ), '[]'),
+    'crew', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', a.actor_id, 'name', a.actor_name, 'role', mc.crew_role)
+            ORDER BY mc.crew_role, a.actor_id)
+        FROM movie_crew mc
+        INNER JOIN actor a ON a.actor_id = mc.person_id
+        WHERE mc.movie_id = m.movie_id
+    ), '[]'),
+    'genres', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', g.genre_id, 'name', g.genre_name)
+            ORDER BY g.genre_name)
+        FROM movie_genre mg
+        INNER JOIN genre g USING (genre_id)
+        WHERE mg.movie_id = m.movie_id
+    ), '[]')
+)
+FROM movie m
+WHERE NOT EXISTS (
+    SELECT 1 FROM change_history ch WHERE ch.entity = 'film' AND ch.entity_id = m.movie_id
+)
+ORDER BY m.movie_id;
+
+INSERT INTO change_history(entity, entity_id, action, user_id, snapshot)
+SELECT 'actor', a.actor_id, 'create', NULL, jsonb_build_object(
+    'name', a.actor_name,
+    'sex', a.sex,
+    'birthday', a.birthday,
+    'deleted', a.deleted_at IS NOT NULL
+)
+FROM actor a
+WHERE NOT EXISTS (
+    SELECT 1 FROM change_history ch WHERE ch.entity = 'actor' AND ch.entity_id = a.actor_id
+)
+ORDER BY a.actor_id;
\ No newline at end of file
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+type txKey struct{}
+
+// Transactor runs fn in a single transaction, which is committed when fn
+// returns nil and rolled back otherwise. Repositories built on a ContextDB
+// run their statements in that transaction when given the context passed
+// to fn.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+var _ Transactor = (*TxManager)(nil)
+
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{
+		db: db,
+	}
+}
+
+// WithinTx joins the transaction of ctx when there is one already, so that
+// operations made of other transactional operations stay atomic.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	const op = "db.TxManager.WithinTx"
+
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("ERROR: failed to begin transaction\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: failed to commit transaction\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+var _ DBTX = (*ContextDB)(nil)
+
+// ContextDB is a DBTX running statements in the transaction started by
+// TxManager.WithinTx for the context, or on the database outside of one.
+type ContextDB struct {
+	db *sql.DB
+}
+
+func NewContextDB(db *sql.DB) *ContextDB {
+	return &ContextDB{
+		db: db,
+	}
+}
+
+func (c *ContextDB) conn(ctx context.Context) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return c.db
+}
+
+func (c *ContextDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.conn(ctx).ExecContext(ctx, query, args...)
+}
+
+func (c *ContextDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.conn(ctx).PrepareContext(ctx, query)
+}
+
+func (c *ContextDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.conn(ctx).QueryContext(ctx, query, args...)
+}
+
+func (c *ContextDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.conn(ctx).QueryRowContext(ctx, query, args...)
+}
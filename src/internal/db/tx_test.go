@@ -0,0 +1,331 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+var errCommitFailed = errors.New("commit failed")
+
+// fakeStore is the state of a fake database: statements executed outside of
+// a transaction or in a committed one are kept, the others are discarded.
+type fakeStore struct {
+	mu         sync.Mutex
+	committed  []string
+	begins     int
+	commits    int
+	rollbacks  int
+	failCommit bool
+}
+
+func (s *fakeStore) state() (committed []string, begins, commits, rollbacks int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.committed...), s.begins, s.commits, s.rollbacks
+}
+
+type fakeConnector struct {
+	store *fakeStore
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{store: c.store}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake driver is opened through its connector")
+}
+
+type fakeConn struct {
+	store   *fakeStore
+	pending []string
+	inTx    bool
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	c.store.begins++
+	c.inTx = true
+	c.pending = nil
+
+	return &fakeTx{conn: c}, nil
+}
+
+func (c *fakeConn) exec(query string) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if c.inTx {
+		c.pending = append(c.pending, query)
+		return
+	}
+	c.store.committed = append(c.store.committed, query)
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (tx *fakeTx) Commit() error {
+	s := tx.conn.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx.conn.inTx = false
+	pending := tx.conn.pending
+	tx.conn.pending = nil
+
+	if s.failCommit {
+		s.rollbacks++
+		return errCommitFailed
+	}
+
+	s.commits++
+	s.committed = append(s.committed, pending...)
+
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	s := tx.conn.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rollbacks++
+	tx.conn.inTx = false
+	tx.conn.pending = nil
+
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	s.conn.exec(s.query)
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string {
+	return nil
+}
+
+func (fakeRows) Close() error {
+	return nil
+}
+
+func (fakeRows) Next([]driver.Value) error {
+	return io.EOF
+}
+
+func newFakeDB(t *testing.T) (*fakeStore, *TxManager, *ContextDB) {
+	t.Helper()
+
+	store := &fakeStore{}
+	conn := sql.OpenDB(&fakeConnector{store: store})
+	t.Cleanup(func() { conn.Close() })
+
+	return store, NewTxManager(conn), NewContextDB(conn)
+}
+
+func execStmt(ctx context.Context, db DBTX, query string) error {
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx)
+
+	return err
+}
+
+func TestWithinTxCommits(t *testing.T) {
+	store, txm, cdb := newFakeDB(t)
+	ctx := context.Background()
+
+	err := txm.WithinTx(ctx, func(ctx context.Context) error {
+		if err := execStmt(ctx, cdb, "INSERT movie"); err != nil {
+			return err
+		}
+
+		return execStmt(ctx, cdb, "INSERT change_history")
+	})
+	if err != nil {
+		t.Fatalf("WithinTx() error = %v", err)
+	}
+
+	committed, begins, commits, _ := store.state()
+	if begins != 1 || commits != 1 {
+		t.Fatalf("begins = %d, commits = %d, want 1 and 1", begins, commits)
+	}
+	if len(committed) != 2 {
+		t.Fatalf("committed = %v, want both statements", committed)
+	}
+}
+
+func TestWithinTxRollsBackOnError(t *testing.T) {
+	store, txm, cdb := newFakeDB(t)
+	ctx := context.Background()
+	errFailed := errors.New("recording history failed")
+
+	err := txm.WithinTx(ctx, func(ctx context.Context) error {
+		if err := execStmt(ctx, cdb, "INSERT movie"); err != nil {
+			return err
+		}
+
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("WithinTx() error = %v, want %v", err, errFailed)
+	}
+
+	committed, _, commits, rollbacks := store.state()
+	if len(committed) != 0 || commits != 0 {
+		t.Fatalf("committed = %v after %d commits, want nothing", committed, commits)
+	}
+	if rollbacks != 1 {
+		t.Fatalf("rollbacks = %d, want 1", rollbacks)
+	}
+}
+
+func TestWithinTxRollsBackOnPanic(t *testing.T) {
+	store, txm, cdb := newFakeDB(t)
+	ctx := context.Background()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("WithinTx() did not pass the panic on")
+			}
+		}()
+
+		txm.WithinTx(ctx, func(ctx context.Context) error {
+			execStmt(ctx, cdb, "INSERT movie")
+			panic("failed mid operation")
+		})
+	}()
+
+	committed, _, _, rollbacks := store.state()
+	if len(committed) != 0 || rollbacks != 1 {
+		t.Fatalf("committed = %v, rollbacks = %d, want nothing committed and 1 rollback", committed, rollbacks)
+	}
+}
+
+func TestWithinTxNestedJoinsOuter(t *testing.T) {
+	tests := []struct {
+		name     string
+		innerErr error
+		outerErr error
+	}{
+		{"inner fails", errors.New("inner failed"), nil},
+		{"outer fails after inner", nil, errors.New("outer failed")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, txm, cdb := newFakeDB(t)
+			ctx := context.Background()
+
+			err := txm.WithinTx(ctx, func(ctx context.Context) error {
+				if err := execStmt(ctx, cdb, "INSERT movie"); err != nil {
+					return err
+				}
+
+				err := txm.WithinTx(ctx, func(ctx context.Context) error {
+					if err := execStmt(ctx, cdb, "INSERT change_history"); err != nil {
+						return err
+					}
+
+					return tt.innerErr
+				})
+				if err != nil {
+					return err
+				}
+
+				return tt.outerErr
+			})
+			if err == nil {
+				t.Fatal("WithinTx() error = nil, want failure")
+			}
+
+			committed, begins, commits, _ := store.state()
+			if begins != 1 {
+				t.Fatalf("begins = %d, nested call must join the outer transaction", begins)
+			}
+			if len(committed) != 0 || commits != 0 {
+				t.Fatalf("committed = %v, want nothing", committed)
+			}
+		})
+	}
+}
+
+func TestWithinTxReportsFailedCommit(t *testing.T) {
+	store, txm, cdb := newFakeDB(t)
+	store.failCommit = true
+	ctx := context.Background()
+
+	err := txm.WithinTx(ctx, func(ctx context.Context) error {
+		return execStmt(ctx, cdb, "INSERT movie")
+	})
+	if !errors.Is(err, errCommitFailed) {
+		t.Fatalf("WithinTx() error = %v, want %v", err, errCommitFailed)
+	}
+
+	committed, _, _, _ := store.state()
+	if len(committed) != 0 {
+		t.Fatalf("committed = %v, want nothing", committed)
+	}
+}
+
+func TestContextDBOutsideTx(t *testing.T) {
+	store, _, cdb := newFakeDB(t)
+
+	if err := execStmt(context.Background(), cdb, "INSERT movie"); err != nil {
+		t.Fatalf("exec error = %v", err)
+	}
+
+	committed, begins, _, _ := store.state()
+	if begins != 0 || len(committed) != 1 {
+		t.Fatalf("committed = %v after %d begins, want the statement outside a transaction", committed, begins)
+	}
+}
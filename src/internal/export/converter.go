@@ -0,0 +1,25 @@
+package export
+
+import (
+	"film-library/src/internal/film"
+)
+
+// ToFormat falls back to CSV when no format is requested.
+func ToFormat(format string) string {
+	if len(format) == 0 {
+		return FormatCSV
+	}
+
+	return format
+}
+
+// ToFilmQuery selects every film matching the filters of req in the
+// requested order.
+func ToFilmQuery(req *ExportFilmsRequest) *film.Query {
+	q := film.ToQuery(&req.Filter)
+	q.Limit = 0
+	q.Cursor = nil
+	q.Backward = false
+
+	return q
+}
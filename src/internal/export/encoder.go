@@ -0,0 +1,92 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// utf8BOM prefixes Excel flavoured CSV.
+const utf8BOM = "\ufeff"
+
+// formulaPrefixes start cells that spreadsheet applications evaluate as
+// formulas.
+const formulaPrefixes = "=+-@\t\r"
+
+// formatMeta maps formats to content types and file extensions.
+var formatMeta = map[string]struct {
+	ContentType string
+	Ext         string
+}{
+	FormatCSV:    {"text/csv; charset=utf-8", ".csv"},
+	FormatExcel:  {"text/csv; charset=utf-8", ".csv"},
+	FormatNDJSON: {"application/x-ndjson", ".ndjson"},
+}
+
+// Encoder writes records of a single kind, Flush must be called once all
+// records are encoded.
+type Encoder interface {
+	Encode(r Record) error
+	Flush() error
+}
+
+// NewEncoder returns an encoder of the format, CSV output starts with the
+// header of columns.
+func NewEncoder(format string, w io.Writer, columns []string) (Encoder, error) {
+	if format == FormatNDJSON {
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	}
+
+	if format == FormatExcel {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return nil, err
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = format == FormatExcel
+	if err := cw.Write(columns); err != nil {
+		return nil, err
+	}
+
+	return &csvEncoder{w: cw, escape: format == FormatExcel}, nil
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+	// escape prefixes formula-like cells with "'" for them to be shown as
+	// text rather than evaluated.
+	escape bool
+}
+
+func (e *csvEncoder) Encode(r Record) error {
+	values := r.Values()
+	if e.escape {
+		for i, v := range values {
+			if len(v) != 0 && strings.ContainsRune(formulaPrefixes, rune(v[0])) {
+				values[i] = "'" + v
+			}
+		}
+	}
+
+	return e.w.Write(values)
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(r Record) error {
+	return e.enc.Encode(r)
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return nil
+}
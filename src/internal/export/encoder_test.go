@@ -0,0 +1,50 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExcelEncoderEscapesFormulas(t *testing.T) {
+	var sb strings.Builder
+	enc, err := NewEncoder(FormatExcel, &sb, actorColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"=HYPERLINK(\"x\")", "+1", "-1", "@SUM(A1)", "\tx", "Tom Hanks"} {
+		if err := enc.Encode(&ActorRecord{ID: 1, Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\r\n"), "\r\n")
+	want := []string{`"'=HYPERLINK(""x"")"`, "'+1", "'-1", "'@SUM(A1)", "'\tx", "Tom Hanks"}
+	for i, w := range want {
+		if got := strings.Split(lines[i+1], ",")[2]; got != w {
+			t.Errorf("name cell = %q, want %q", got, w)
+		}
+	}
+}
+
+func TestCSVEncoderKeepsCells(t *testing.T) {
+	var sb strings.Builder
+	enc, err := NewEncoder(FormatCSV, &sb, actorColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := enc.Encode(&ActorRecord{ID: 1, Name: "=1+1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(sb.String(), "\n1,,=1+1,") {
+		t.Errorf("csv = %q, want cells as is", sb.String())
+	}
+}
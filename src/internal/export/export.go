@@ -0,0 +1,139 @@
+package export
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"film-library/src/internal/film"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	// FormatExcel is CSV with a byte order mark and CRLF line breaks, which
+	// spreadsheet applications open as UTF-8 without an import dialog. It is
+	// not XLSX, cells that would be evaluated as formulas are prefixed with
+	// "'".
+	FormatExcel = "excel"
+)
+
+// listSeparator joins list values within a single CSV field.
+const listSeparator = ";"
+
+var (
+	filmColumns   = []string{"id", "imdb_id", "name", "description", "releasedate", "rating", "user_rating", "rating_count", "review_count", "genres", "actors"}
+	actorColumns  = []string{"id", "imdb_id", "name", "sex", "birthday"}
+	creditColumns = []string{"film_id", "person_id", "role", "characters", "billing"}
+)
+
+// Record is an exported row, it is marshalled as is into NDJSON while CSV
+// takes its Values in column order.
+type Record interface {
+	Values() []string
+}
+
+type FilmRecord struct {
+	ID          int      `json:"id"`
+	IMDbID      string   `json:"imdbId,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	ReleaseDate string   `json:"releasedate"`
+	Rating      int      `json:"rating"`
+	UserRating  float64  `json:"userRating"`
+	RatingCount int      `json:"ratingCount"`
+	ReviewCount int      `json:"reviewCount"`
+	Genres      []string `json:"genres"`
+	Actors      []string `json:"actors"`
+}
+
+func (r *FilmRecord) Values() []string {
+	return []string{
+		strconv.Itoa(r.ID),
+		r.IMDbID,
+		r.Name,
+		r.Description,
+		r.ReleaseDate,
+		strconv.Itoa(r.Rating),
+		strconv.FormatFloat(r.UserRating, 'f', 2, 64),
+		strconv.Itoa(r.RatingCount),
+		strconv.Itoa(r.ReviewCount),
+		strings.Join(r.Genres, listSeparator),
+		strings.Join(r.Actors, listSeparator),
+	}
+}
+
+type ActorRecord struct {
+	ID       int    `json:"id"`
+	IMDbID   string `json:"imdbId,omitempty"`
+	Name     string `json:"name"`
+	Sex      string `json:"sex"`
+	Birthday string `json:"birthday"`
+}
+
+func (r *ActorRecord) Values() []string {
+	return []string{
+		strconv.Itoa(r.ID),
+		r.IMDbID,
+		r.Name,
+		r.Sex,
+		r.Birthday,
+	}
+}
+
+// CreditRecord binds a person to a film, Role is "actor" for cast credits
+// and the crew role otherwise, Billing of zero means not billed.
+type CreditRecord struct {
+	FilmID     int      `json:"filmId"`
+	PersonID   int      `json:"personId"`
+	Role       string   `json:"role"`
+	Characters []string `json:"characters,omitempty"`
+	Billing    int      `json:"billing,omitempty"`
+}
+
+func (r *CreditRecord) Values() []string {
+	billing := ""
+	if r.Billing != 0 {
+		billing = strconv.Itoa(r.Billing)
+	}
+
+	return []string{
+		strconv.Itoa(r.FilmID),
+		strconv.Itoa(r.PersonID),
+		r.Role,
+		strings.Join(r.Characters, listSeparator),
+		billing,
+	}
+}
+
+// ExportRepository streams rows to fn one at a time, iteration stops at
+// the first error returned by fn.
+type ExportRepository interface {
+	GetFilms(ctx context.Context, q *film.Query, fn func(*FilmRecord) error) error
+	GetActors(ctx context.Context, fn func(*ActorRecord) error) error
+	GetCredits(ctx context.Context, fn func(*CreditRecord) error) error
+}
+
+type ExportService interface {
+	ExportFilms(ctx context.Context, req *ExportFilmsRequest, w io.Writer) error
+	ExportActors(ctx context.Context, req *ExportRequest, w io.Writer) error
+	WriteArchive(ctx context.Context, req *ExportRequest, w io.Writer) error
+}
+
+type ExportHandler interface {
+	ExportFilms(w http.ResponseWriter, r *http.Request)
+	ExportActors(w http.ResponseWriter, r *http.Request)
+}
+
+type ExportRequest struct {
+	FormatQuery string
+}
+
+// ExportFilmsRequest takes the same filters and sorting as listing films,
+// pagination does not apply.
+type ExportFilmsRequest struct {
+	FormatQuery string
+	Filter      film.GetFilmsRequest
+}
@@ -0,0 +1,117 @@
+package export
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/film"
+	"film-library/src/internal/tools"
+)
+
+var _ ExportHandler = (*Handler)(nil)
+
+type Handler struct {
+	service ExportService
+}
+
+func NewHandler(es ExportService) *Handler {
+	return &Handler{
+		service: es,
+	}
+}
+
+func (h *Handler) ExportFilms(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := ExportFilmsRequest{
+		FormatQuery: query.Get("format"),
+		Filter: film.GetFilmsRequest{
+			SortQuery:           query.Get("sort"),
+			FilmQuery:           query.Get("film"),
+			ActorQuery:          query.Get("actor"),
+			CaseSensitiveQuery:  query.Get("caseSensitive"),
+			FuzzyQuery:          query.Get("fuzzy"),
+			RatingMinQuery:      query.Get("ratingMin"),
+			RatingMaxQuery:      query.Get("ratingMax"),
+			ReleasedAfterQuery:  query.Get("releasedAfter"),
+			ReleasedBeforeQuery: query.Get("releasedBefore"),
+			ActorIDQuery:        query["actorId"],
+			ActorMatchQuery:     query.Get("actorMatch"),
+			GenreQuery:          query["genre"],
+		},
+	}
+
+	aw := newAttachmentWriter(w, "films", req.FormatQuery)
+	err := h.service.ExportFilms(r.Context(), &req, aw)
+	h.finish(aw, r, err, "films")
+}
+
+func (h *Handler) ExportActors(w http.ResponseWriter, r *http.Request) {
+	req := ExportRequest{
+		FormatQuery: r.URL.Query().Get("format"),
+	}
+
+	aw := newAttachmentWriter(w, "actors", req.FormatQuery)
+	err := h.service.ExportActors(r.Context(), &req, aw)
+	h.finish(aw, r, err, "actors")
+}
+
+// finish reports err unless the export has already started streaming, in
+// which case the truncated response is all the client gets.
+func (h *Handler) finish(aw *attachmentWriter, r *http.Request, err error, what string) {
+	if err == nil {
+		aw.start()
+		return
+	}
+
+	log.Printf("ERROR: failed to export %s err=%s\n", what, err.Error())
+	if aw.started {
+		return
+	}
+
+	var ve *tools.ValidationError
+	if errors.As(err, &ve) {
+		tools.JSON(aw.w, r, http.StatusBadRequest, &tools.ErrorMessage{
+			ErrorType: tools.ErrorTypeValidation,
+			Body:      ve.Error(),
+		})
+		return
+	}
+
+	tools.InternalServerError(aw.w, r)
+}
+
+// attachmentWriter sends download headers along with the first written
+// bytes, so errors found before any output still get a regular response.
+type attachmentWriter struct {
+	w       http.ResponseWriter
+	name    string
+	format  string
+	started bool
+}
+
+func newAttachmentWriter(w http.ResponseWriter, name, format string) *attachmentWriter {
+	return &attachmentWriter{
+		w:      w,
+		name:   name,
+		format: ToFormat(format),
+	}
+}
+
+func (aw *attachmentWriter) start() {
+	if aw.started {
+		return
+	}
+	aw.started = true
+
+	meta := formatMeta[aw.format]
+	aw.w.Header().Set("content-type", meta.ContentType)
+	aw.w.Header().Set("content-disposition", `attachment; filename="`+aw.name+meta.Ext+`"`)
+	aw.w.WriteHeader(http.StatusOK)
+}
+
+func (aw *attachmentWriter) Write(p []byte) (int, error) {
+	aw.start()
+
+	return aw.w.Write(p)
+}
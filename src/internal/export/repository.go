@@ -0,0 +1,182 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/film"
+	"film-library/src/internal/tools"
+	"github.com/lib/pq"
+)
+
+var _ ExportRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) GetFilms(ctx context.Context, q *film.Query, fn func(*FilmRecord) error) error {
+	const op = "export.Repository.GetFilms"
+
+	qb := tools.NewSelectBuilder(`
+		SELECT m.movie_id, COALESCE(m.imdb_id, ''), m.movie_name, m.movie_description,
+			m.releasedate, m.rating, COALESCE(rs.rating_average, 0), COALESCE(rs.rating_count, 0),
+			(
+				SELECT COUNT(*) FROM review rv
+				WHERE rv.movie_id = m.movie_id AND rv.status = 'approved'
+			),
+			ARRAY (
+				SELECT g.genre_name FROM movie_genre mg
+				INNER JOIN genre g USING (genre_id)
+				WHERE mg.movie_id = m.movie_id
+				ORDER BY g.genre_name
+			),
+			ARRAY (
+				SELECT a.actor_name FROM actor_in_movie am
+				INNER JOIN actor a USING (actor_id)
+				WHERE am.movie_id = m.movie_id AND a.deleted_at IS NULL
+				ORDER BY am.billing NULLS LAST, a.actor_name, a.actor_id
+			)
+		FROM movie m
+		LEFT JOIN movie_rating_stats rs USING (movie_id)`)
+	query, args := film.ToQueryConditions(q, qb).Build()
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var f FilmRecord
+		var releaseDate time.Time
+		err := rows.Scan(&f.ID, &f.IMDbID, &f.Name, &f.Description, &releaseDate, &f.Rating,
+			&f.UserRating, &f.RatingCount, &f.ReviewCount, pq.Array(&f.Genres), pq.Array(&f.Actors))
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		f.ReleaseDate = releaseDate.Format(time.DateOnly)
+
+		if err := fn(&f); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetActors(ctx context.Context, fn func(*ActorRecord) error) error {
+	const op = "export.Repository.GetActors"
+
+	const query = `
+		SELECT actor_id, COALESCE(imdb_id, ''), actor_name, sex, birthday
+		FROM actor
+		WHERE deleted_at IS NULL
+		ORDER BY actor_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a ActorRecord
+		var birthday sql.NullTime
+		if err := rows.Scan(&a.ID, &a.IMDbID, &a.Name, &a.Sex, &birthday); err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if birthday.Valid {
+			a.Birthday = birthday.Time.Format(time.DateOnly)
+		}
+
+		if err := fn(&a); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetCredits(ctx context.Context, fn func(*CreditRecord) error) error {
+	const op = "export.Repository.GetCredits"
+
+	const query = `
+		SELECT am.movie_id, am.actor_id, 'actor', am.characters, COALESCE(am.billing, 0)
+		FROM actor_in_movie am
+		INNER JOIN movie m USING (movie_id)
+		INNER JOIN actor a USING (actor_id)
+		WHERE m.deleted_at IS NULL AND a.deleted_at IS NULL
+		UNION ALL
+		SELECT mc.movie_id, mc.person_id, mc.crew_role, '{}', 0
+		FROM movie_crew mc
+		INNER JOIN movie m USING (movie_id)
+		INNER JOIN actor a ON a.actor_id = mc.person_id
+		WHERE m.deleted_at IS NULL AND a.deleted_at IS NULL
+		ORDER BY 1, 3, 5, 2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c CreditRecord
+		if err := rows.Scan(&c.FilmID, &c.PersonID, &c.Role, pq.Array(&c.Characters), &c.Billing); err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := fn(&c); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"film-library/src/internal/film"
+)
+
+var _ ExportService = (*Service)(nil)
+
+type Service struct {
+	repo ExportRepository
+}
+
+func NewService(er ExportRepository) *Service {
+	return &Service{
+		repo: er,
+	}
+}
+
+// ExportFilms writes every film matching the filters of req to w, nothing
+// is written when the request is invalid.
+func (s *Service) ExportFilms(ctx context.Context, req *ExportFilmsRequest, w io.Writer) error {
+	const op = "export.Service.ExportFilms"
+
+	vErr := ValidateExportFilmsRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	if err := s.writeFilms(ctx, ToFormat(req.FormatQuery), ToFilmQuery(req), w); err != nil {
+		log.Printf("ERROR: failed to export films\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) ExportActors(ctx context.Context, req *ExportRequest, w io.Writer) error {
+	const op = "export.Service.ExportActors"
+
+	vErr := ValidateExportRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	if err := s.writeActors(ctx, ToFormat(req.FormatQuery), w); err != nil {
+		log.Printf("ERROR: failed to export actors\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// WriteArchive writes a zip archive of the whole catalogue with films,
+// actors and credits in separate files of the requested format.
+func (s *Service) WriteArchive(ctx context.Context, req *ExportRequest, w io.Writer) error {
+	const op = "export.Service.WriteArchive"
+
+	vErr := ValidateExportRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return fmt.Errorf("%s: %w", op, vErr)
+	}
+	format := ToFormat(req.FormatQuery)
+	ext := formatMeta[format].Ext
+
+	zw := zip.NewWriter(w)
+	modified := time.Now()
+	for _, v := range []struct {
+		name  string
+		write func(w io.Writer) error
+	}{
+		{"films" + ext, func(w io.Writer) error {
+			return s.writeFilms(ctx, format, ToFilmQuery(&ExportFilmsRequest{}), w)
+		}},
+		{"actors" + ext, func(w io.Writer) error { return s.writeActors(ctx, format, w) }},
+		{"credits" + ext, func(w io.Writer) error { return s.writeCredits(ctx, format, w) }},
+	} {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     v.name,
+			Method:   zip.Deflate,
+			Modified: modified,
+		})
+		if err != nil {
+			log.Printf("ERROR: failed to create archive entry %s\n", v.name)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := v.write(fw); err != nil {
+			log.Printf("ERROR: failed to write archive entry %s\n", v.name)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		log.Printf("ERROR: failed to finish archive\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) writeFilms(ctx context.Context, format string, q *film.Query, w io.Writer) error {
+	enc, err := NewEncoder(format, w, filmColumns)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.GetFilms(ctx, q, func(r *FilmRecord) error {
+		return enc.Encode(r)
+	})
+	if err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+func (s *Service) writeActors(ctx context.Context, format string, w io.Writer) error {
+	enc, err := NewEncoder(format, w, actorColumns)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.GetActors(ctx, func(r *ActorRecord) error {
+		return enc.Encode(r)
+	})
+	if err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+func (s *Service) writeCredits(ctx context.Context, format string, w io.Writer) error {
+	enc, err := NewEncoder(format, w, creditColumns)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.GetCredits(ctx, func(r *CreditRecord) error {
+		return enc.Encode(r)
+	})
+	if err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"film-library/src/internal/film"
+	"film-library/src/internal/tools"
+)
+
+func ValidateExportRequest(req *ExportRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	validateFormat(ve, req.FormatQuery)
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateExportFilmsRequest(req *ExportFilmsRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	validateFormat(ve, req.FormatQuery)
+
+	if vErr := film.ValidateGetFilmsRequest(&req.Filter); vErr != nil {
+		ve.AddViolation(vErr.Error())
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func validateFormat(ve *tools.ValidationError, format string) {
+	if len(format) == 0 {
+		return
+	}
+
+	if _, ok := formatMeta[format]; !ok {
+		ve.AddViolation("incorrect format (expected one of [csv, ndjson, excel])")
+	}
+}
@@ -1,97 +1,234 @@
 package film
 
 import (
+	"database/sql"
 	"fmt"
+	"hash/fnv"
+	"strconv"
 	"strings"
 	"time"
 
 	"film-library/src/internal/tools"
+	"github.com/lib/pq"
 )
 
-func ToQueryableLists(fa *FilmActors, format string) (string, []any) {
-	n := len(fa.ActorIDs)
+func ToQueryableLists(id int, ids []int, format string) (string, []any) {
+	n := len(ids)
 
 	values := make([]any, 0, n+1)
-	values = append(values, fa.ID)
+	values = append(values, id)
 
 	qs := make([]string, 0, n)
 
 	for i := 0; i < n; i++ {
 		qs = append(qs, fmt.Sprintf(format, i+2))
-		values = append(values, fa.ActorIDs[i])
+		values = append(values, ids[i])
 	}
 
 	return strings.Join(qs, ", "), values
 }
 
-func ToQueryableObject(a *Film) *tools.QueryableObject {
-	qo := tools.NewQueryableObject()
+var sortMap = map[string]string{
+	"name":        "m.movie_name",
+	"rating":      "m.rating",
+	"releasedate": "m.releasedate",
+	"userRating":  "COALESCE(rs.rating_average, 0)",
+}
+
+var defaultSort = []string{"rating", "desc"}
 
-	if len(a.Name) != 0 {
-		qo.Add("movie_name", a.Name)
+// ToFilterConditions returns conditions selecting films that match the
+// filters of q, every filter value is bound as a parameter.
+func ToFilterConditions(q *Query) tools.Cond {
+	cons := []tools.Cond{tools.Expr("m.deleted_at IS NULL")}
+
+	like := "ILIKE"
+	if q.CaseSensitive {
+		like = "LIKE"
 	}
 
-	if len(a.Description) != 0 {
-		qo.Add("movie_description", a.Description)
+	if len(q.Film) != 0 {
+		con := tools.Expr(`m.movie_name `+like+` ? ESCAPE '\'`, tools.ContainsPattern(q.Film))
+		if q.Fuzzy {
+			con = tools.Or(con, tools.Expr("? <% m.movie_name", q.Film))
+		}
+		cons = append(cons, con)
 	}
 
-	if !a.ReleaseDate.IsZero() {
-		qo.Add("releasedate", a.ReleaseDate)
+	if len(q.Actor) != 0 {
+		con := tools.Expr(`fa.actor_name `+like+` ? ESCAPE '\'`, tools.ContainsPattern(q.Actor))
+		if q.Fuzzy {
+			con = tools.Or(con, tools.Expr("? <% fa.actor_name", q.Actor))
+		}
+		cons = append(cons, tools.Nest(`
+			EXISTS (
+				SELECT 1 FROM actor_in_movie fam
+				INNER JOIN actor fa USING (actor_id)
+				WHERE fam.movie_id = m.movie_id AND fa.deleted_at IS NULL AND %s
+			)`, con))
 	}
 
-	if a.Rating >= 0 {
-		qo.Add("rating", a.Rating)
+	if q.RatingMin != nil {
+		cons = append(cons, tools.Expr("m.rating >= ?", *q.RatingMin))
 	}
 
-	return qo
-}
+	if q.RatingMax != nil {
+		cons = append(cons, tools.Expr("m.rating <= ?", *q.RatingMax))
+	}
 
-var sortMap = map[string]string{
-	"name":        "movie_name",
-	"rating":      "rating",
-	"releasedate": "releasedate",
-}
+	if !q.ReleasedAfter.IsZero() {
+		cons = append(cons, tools.Expr("m.releasedate >= ?", q.ReleasedAfter))
+	}
 
-func ToQueryConditions(q *Query) [3]string {
-	var conditions [3]string
+	if !q.ReleasedBefore.IsZero() {
+		cons = append(cons, tools.Expr("m.releasedate <= ?", q.ReleasedBefore))
+	}
 
-	var filmCon string
-	if len(q.Film) != 0 {
-		pattern := "'%" + q.Film + "%'"
-		filmCon = "WHERE movie_name LIKE " + pattern
+	if len(q.ActorIDs) != 0 {
+		if q.MatchAllActors {
+			cons = append(cons, tools.Expr(`
+				(
+					SELECT COUNT(*) FROM actor_in_movie iam
+					WHERE iam.movie_id = m.movie_id AND iam.actor_id = ANY (?)
+				) = ?`, pq.Array(q.ActorIDs), len(q.ActorIDs)))
+		} else {
+			cons = append(cons, tools.Expr(`
+				EXISTS (
+					SELECT 1 FROM actor_in_movie iam
+					WHERE iam.movie_id = m.movie_id AND iam.actor_id = ANY (?)
+				)`, pq.Array(q.ActorIDs)))
+		}
 	}
-	conditions[0] = filmCon
 
-	var actorCon string
-	if len(q.Actor) != 0 {
-		pattern := "'%" + q.Actor + "%'"
-		actorCon = "HAVING STRING_AGG (a.actor_name, ';') LIKE " + pattern
+	if len(q.Genres) != 0 {
+		cons = append(cons, tools.Expr(`
+			EXISTS (
+				SELECT 1 FROM movie_genre gmg
+				INNER JOIN genre gg USING (genre_id)
+				WHERE gmg.movie_id = m.movie_id AND LOWER(gg.genre_name) = ANY (?)
+			)`, pq.Array(q.Genres)))
 	}
-	conditions[1] = actorCon
 
-	var sortCon string
+	return tools.And(cons...)
+}
+
+// ToQueryConditions applies filters, keyset position, ordering and limit
+// of q to the films query.
+func ToQueryConditions(q *Query, qb *tools.SelectBuilder) *tools.SelectBuilder {
+	qb.Where(ToFilterConditions(q))
+
 	if q.Sort == nil {
-		sortCon = "ORDER BY rating DESC"
-	} else {
-		sortCon = fmt.Sprintf("ORDER BY %s %s",
-			sortMap[q.Sort[0]], strings.ToUpper(q.Sort[1]))
+		return qb.Limit(q.Limit)
+	}
+
+	col := sortMap[q.Sort[0]]
+	order := q.Sort[1]
+	if q.Backward {
+		order = reverseOrder[order]
 	}
-	conditions[2] = sortCon
 
-	return conditions
+	if q.Cursor != nil {
+		// rows are compared as (sort key, id) pairs so that films sharing
+		// the same sort key are neither skipped nor repeated between pages
+		cmp := ">"
+		if order == "desc" {
+			cmp = "<"
+		}
+		qb.Where(tools.Expr(fmt.Sprintf("(%s, m.movie_id) %s (?, ?)", col, cmp), q.Cursor.Value, q.Cursor.ID))
+	}
+
+	order = strings.ToUpper(order)
+
+	return qb.OrderBy(col+" "+order, "m.movie_id "+order).Limit(q.Limit)
+}
+
+var reverseOrder = map[string]string{
+	"asc":  "desc",
+	"desc": "asc",
 }
 
 func ToQuery(req *GetFilmsRequest) *Query {
-	var sort []string
+	sort := defaultSort
 	if len(req.SortQuery) != 0 {
 		sort = strings.Split(req.SortQuery, ",")
 	}
 
+	var ratingMin, ratingMax *int
+	if len(req.RatingMinQuery) != 0 {
+		v, _ := strconv.Atoi(req.RatingMinQuery)
+		ratingMin = &v
+	}
+	if len(req.RatingMaxQuery) != 0 {
+		v, _ := strconv.Atoi(req.RatingMaxQuery)
+		ratingMax = &v
+	}
+
+	releasedAfter, _ := time.Parse("2006-01-02", req.ReleasedAfterQuery)
+	releasedBefore, _ := time.Parse("2006-01-02", req.ReleasedBeforeQuery)
+
+	actorIDs := make([]int, 0, len(req.ActorIDQuery))
+	for _, v := range req.ActorIDQuery {
+		id, _ := strconv.Atoi(v)
+		actorIDs = append(actorIDs, id)
+	}
+
+	genres := make([]string, 0, len(req.GenreQuery))
+	for _, v := range req.GenreQuery {
+		genres = append(genres, strings.ToLower(strings.TrimSpace(v)))
+	}
+
+	limit, cursor, backward := tools.ToPageQuery(req.LimitQuery, req.AfterQuery, req.BeforeQuery)
+
 	return &Query{
-		Sort:  sort,
-		Film:  req.FilmQuery,
-		Actor: req.ActorQuery,
+		Sort:           sort,
+		Film:           req.FilmQuery,
+		Actor:          req.ActorQuery,
+		CaseSensitive:  req.CaseSensitiveQuery == "true",
+		Fuzzy:          req.FuzzyQuery == "true",
+		RatingMin:      ratingMin,
+		RatingMax:      ratingMax,
+		ReleasedAfter:  releasedAfter,
+		ReleasedBefore: releasedBefore,
+		ActorIDs:       tools.RemoveDuplicateInt(actorIDs),
+		MatchAllActors: req.ActorMatchQuery == "all",
+		Genres:         genres,
+		Limit:          limit,
+		Cursor:         cursor,
+		Backward:       backward,
+	}
+}
+
+func ToFilmCursor(f *Film, sort []string) *tools.Cursor {
+	var value string
+	switch sort[0] {
+	case "name":
+		value = f.Name
+	case "releasedate":
+		value = f.ReleaseDate.Format("2006-01-02")
+	case "userRating":
+		value = strconv.FormatFloat(f.UserRating.Average, 'f', 2, 64)
+	default:
+		value = strconv.Itoa(f.Rating)
+	}
+
+	return &tools.Cursor{
+		Sort:  strings.Join(sort, ","),
+		Value: value,
+		ID:    f.ID,
+	}
+}
+
+func ToSuggestionsResponse(s []*Suggestion) []*SuggestionResponse {
+	res := make([]*SuggestionResponse, 0, len(s))
+	for _, v := range s {
+		res = append(res, &SuggestionResponse{
+			ID:         v.ID,
+			Name:       v.Name,
+			Similarity: v.Similarity,
+		})
 	}
+
+	return res
 }
 
 func ToFilmResponse(f *Film) *FilmResponse {
@@ -103,7 +240,60 @@ func ToFilmResponse(f *Film) *FilmResponse {
 			ReleaseDate: f.ReleaseDate.Format("2006-01-02"),
 			Rating:      int(f.Rating),
 		},
-		Actors: f.Actors,
+		Actors:      ToActorsShortRespose(f.Actors),
+		Genres:      f.Genres,
+		UserRating:  ToRatingStatsResponse(&f.UserRating),
+		ReviewCount: f.ReviewCount,
+		Poster:      f.Poster,
+		Version:     f.Version,
+	}
+}
+
+// ToFilmETag tags the film response in mediaType by its version and a digest
+// of its user ratings and reviews, which change without bumping the version,
+// followed by viewer flags when set and the media type, e.g.
+// "12-6c1d0f2a.w1l0.json" for a watched film not on the watchlist.
+func ToFilmETag(res *FilmResponse, mediaType string) string {
+	h := fnv.New32a()
+	fmt.Fprint(h, res.UserRating.Histogram, res.ReviewCount)
+	variant := fmt.Sprintf("%08x", h.Sum32())
+
+	if res.Watched != nil && res.OnWatchlist != nil {
+		flags := []byte(".w0l0")
+		if *res.Watched {
+			flags[2] = '1'
+		}
+		if *res.OnWatchlist {
+			flags[4] = '1'
+		}
+		variant += string(flags)
+	}
+
+	return tools.VariantETag(res.Version, variant+"."+tools.MediaTypeVariant(mediaType))
+}
+
+// SetViewerFlags fills in the caller specific part of the film response,
+// films missing from flags are neither watched nor on the watchlist.
+func SetViewerFlags(res *FilmResponse, flags map[int]*ViewerFlags) {
+	vf, ok := flags[res.ID]
+	if !ok {
+		vf = &ViewerFlags{}
+	}
+
+	res.Watched = &vf.Watched
+	res.OnWatchlist = &vf.OnWatchlist
+}
+
+// ToRatingStatsResponse always reports a histogram of maxUserRating
+// buckets, even for films nobody has rated yet.
+func ToRatingStatsResponse(rs *RatingStats) RatingStatsResponse {
+	histogram := make([]int64, maxUserRating)
+	copy(histogram, rs.Histogram)
+
+	return RatingStatsResponse{
+		Average:   rs.Average,
+		Count:     rs.Count,
+		Histogram: histogram,
 	}
 }
 
@@ -131,6 +321,73 @@ func ToActorsShortRespose(a []*ActorShort) []*ActorShortResponse {
 	res := make([]*ActorShortResponse, 0, len(a))
 	for _, v := range a {
 		res = append(res, &ActorShortResponse{
+			ID:         v.ID,
+			Name:       v.Name,
+			Characters: v.Characters,
+			Billing:    v.Billing,
+		})
+	}
+
+	return res
+}
+
+// ToQueryableCast works as ToQueryableLists but for cast credits, format
+// receives placeholder numbers of the actor id, characters and billing.
+func ToQueryableCast(fc *FilmCast, format string) (string, []any) {
+	n := len(fc.Credits)
+
+	values := make([]any, 0, 3*n+1)
+	values = append(values, fc.ID)
+
+	qs := make([]string, 0, n)
+
+	for i, v := range fc.Credits {
+		var billing sql.NullInt32
+		if v.Billing > 0 {
+			billing = sql.NullInt32{Int32: int32(v.Billing), Valid: true}
+		}
+
+		qs = append(qs, fmt.Sprintf(format, 3*i+2, 3*i+3, 3*i+4))
+		values = append(values, v.ActorID, pq.Array(v.Characters), billing)
+	}
+
+	return strings.Join(qs, ", "), values
+}
+
+func ToCastCredits(c []*CastCreditRequest) []*CastCredit {
+	credits := make([]*CastCredit, 0, len(c))
+	for _, v := range c {
+		characters := make([]string, 0, len(v.Characters))
+		for _, ch := range v.Characters {
+			characters = append(characters, strings.TrimSpace(ch))
+		}
+
+		credits = append(credits, &CastCredit{
+			ActorID:    v.ActorID,
+			Characters: characters,
+			Billing:    v.Billing,
+		})
+	}
+
+	return credits
+}
+
+func ToUncreditedCast(ids []int) []*CastCredit {
+	credits := make([]*CastCredit, 0, len(ids))
+	for _, v := range ids {
+		credits = append(credits, &CastCredit{
+			ActorID:    v,
+			Characters: []string{},
+		})
+	}
+
+	return credits
+}
+
+func ToGenresShortResponse(g []*GenreShort) []*GenreShortResponse {
+	res := make([]*GenreShortResponse, 0, len(g))
+	for _, v := range g {
+		res = append(res, &GenreShortResponse{
 			ID:   v.ID,
 			Name: v.Name,
 		})
@@ -138,3 +395,169 @@ func ToActorsShortRespose(a []*ActorShort) []*ActorShortResponse {
 
 	return res
 }
+
+// ToQueryableCredits works as ToQueryableLists but for (person, role) pairs,
+// format receives placeholder numbers of the person id and the role.
+func ToQueryableCredits(fc *FilmCrew, format string) (string, []any) {
+	n := len(fc.Credits)
+
+	values := make([]any, 0, 2*n+1)
+	values = append(values, fc.ID)
+
+	qs := make([]string, 0, n)
+
+	for i, v := range fc.Credits {
+		qs = append(qs, fmt.Sprintf(format, 2*i+2, 2*i+3))
+		values = append(values, v.PersonID, v.Role)
+	}
+
+	return strings.Join(qs, ", "), values
+}
+
+func ToCrewCredits(c []*CrewCreditRequest) []*CrewCredit {
+	credits := make([]*CrewCredit, 0, len(c))
+	seen := make(map[CrewCredit]bool)
+	for _, v := range c {
+		cc := CrewCredit{
+			PersonID: v.PersonID,
+			Role:     v.Role,
+		}
+		if seen[cc] {
+			continue
+		}
+		seen[cc] = true
+
+		credits = append(credits, &cc)
+	}
+
+	return credits
+}
+
+func ToCrewResponse(c []*CrewMember) []*CrewMemberResponse {
+	res := make([]*CrewMemberResponse, 0, len(c))
+	for _, v := range c {
+		res = append(res, &CrewMemberResponse{
+			ID:   v.ID,
+			Name: v.Name,
+			Role: v.Role,
+		})
+	}
+
+	return res
+}
+
+// inverseRelations names relations as seen from the other film.
+var inverseRelations = map[string]string{
+	"sequel_of":   "prequel_of",
+	"remake_of":   "remade_as",
+	"spin_off_of": "spun_off_into",
+}
+
+// ToQueryableRelations works as ToQueryableCredits but for (film, type)
+// pairs.
+func ToQueryableRelations(fr *FilmRelations, format string) (string, []any) {
+	n := len(fr.Relations)
+
+	values := make([]any, 0, 2*n+1)
+	values = append(values, fr.ID)
+
+	qs := make([]string, 0, n)
+
+	for i, v := range fr.Relations {
+		qs = append(qs, fmt.Sprintf(format, 2*i+2, 2*i+3))
+		values = append(values, v.FilmID, v.Type)
+	}
+
+	return strings.Join(qs, ", "), values
+}
+
+func ToRelations(rel []*RelationRequest) []*Relation {
+	relations := make([]*Relation, 0, len(rel))
+	seen := make(map[Relation]bool)
+	for _, v := range rel {
+		r := Relation{
+			FilmID: v.FilmID,
+			Type:   v.Type,
+		}
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+
+		relations = append(relations, &r)
+	}
+
+	return relations
+}
+
+func ToRelatedFilmsResponse(rf []*RelatedFilm) []*RelatedFilmResponse {
+	res := make([]*RelatedFilmResponse, 0, len(rf))
+	for _, v := range rf {
+		relation := v.Relation
+		if v.Inverse {
+			relation = inverseRelations[v.Relation]
+		}
+
+		res = append(res, &RelatedFilmResponse{
+			ID:          v.ID,
+			Name:        v.Name,
+			ReleaseDate: v.ReleaseDate.Format(time.DateOnly),
+			Relation:    relation,
+		})
+	}
+
+	return res
+}
+
+func ToFilmFranchisesResponse(ff []*FilmFranchise) []*FilmFranchiseResponse {
+	res := make([]*FilmFranchiseResponse, 0, len(ff))
+	for _, v := range ff {
+		res = append(res, &FilmFranchiseResponse{
+			ID:                 v.ID,
+			Name:               v.Name,
+			ReleaseOrder:       v.ReleaseOrder,
+			ChronologicalOrder: v.ChronologicalOrder,
+		})
+	}
+
+	return res
+}
+
+var filmCSVHeader = []string{"id", "name", "description", "releasedate", "rating", "genres", "actors", "user_rating", "rating_count", "review_count"}
+
+// MarshalCSV renders the film as a single record, genres and actor names
+// are separated by ";".
+func (res *FilmResponse) MarshalCSV() ([]string, [][]string) {
+	return filmCSVHeader, [][]string{ToFilmCSVRecord(res)}
+}
+
+// MarshalCSV renders a record per film, cursors are sent in the Link header
+// instead.
+func (res *GetFilmsResponse) MarshalCSV() ([]string, [][]string) {
+	records := make([][]string, 0, len(res.Films))
+	for _, v := range res.Films {
+		records = append(records, ToFilmCSVRecord(v))
+	}
+
+	return filmCSVHeader, records
+}
+
+func ToFilmCSVRecord(res *FilmResponse) []string {
+	actors := make([]string, 0, len(res.Actors))
+	for _, v := range res.Actors {
+		actors = append(actors, v.Name)
+	}
+
+	return []string{
+		strconv.Itoa(res.ID),
+		res.Info.Name,
+		res.Info.Description,
+		res.Info.ReleaseDate,
+		strconv.Itoa(res.Info.Rating),
+		strings.Join(res.Genres, ";"),
+		strings.Join(actors, ";"),
+		strconv.FormatFloat(res.UserRating.Average, 'f', 2, 64),
+		strconv.Itoa(res.UserRating.Count),
+		strconv.Itoa(res.ReviewCount),
+	}
+}
@@ -0,0 +1,197 @@
+package film
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"film-library/src/internal/tools"
+)
+
+var hostileInputs = []string{
+	`'`,
+	`"`,
+	`%`,
+	`_`,
+	`\`,
+	`?`,
+	`;--`,
+	`$1`,
+	`' OR '1'='1`,
+	`'; DROP TABLE movie; --`,
+	`100%_off\`,
+	`what?? $2 ?`,
+	`) OR EXISTS (SELECT 1) --`,
+	"tab\tnewline\nnul\x00",
+}
+
+var placeholderRe = regexp.MustCompile(`\$(\d+)`)
+
+func buildFilmsQuery(t *testing.T, req *GetFilmsRequest) (string, []any) {
+	t.Helper()
+
+	if ve := ValidateGetFilmsRequest(req); ve != nil {
+		t.Fatalf("request %+v failed validation: %s", req, ve.Error())
+	}
+
+	qb := tools.NewSelectBuilder("SELECT m.movie_id FROM movie m")
+
+	return ToQueryConditions(ToQuery(req), qb).Build()
+}
+
+// benignFilmsRequest replaces every user supplied text of req with a plain
+// value, the statement built from it must be the same as from req.
+func benignFilmsRequest(req *GetFilmsRequest) *GetFilmsRequest {
+	benign := *req
+	if len(benign.FilmQuery) != 0 {
+		benign.FilmQuery = "x"
+	}
+	if len(benign.ActorQuery) != 0 {
+		benign.ActorQuery = "x"
+	}
+	benign.GenreQuery = nil
+	for range req.GenreQuery {
+		benign.GenreQuery = append(benign.GenreQuery, "x")
+	}
+	if c, err := tools.DecodeCursor(req.AfterQuery); err == nil {
+		benign.AfterQuery = tools.EncodeCursor(&tools.Cursor{Sort: c.Sort, Value: "x", ID: 1})
+	}
+
+	return &benign
+}
+
+func checkFilmsQuery(t *testing.T, req *GetFilmsRequest) (string, []any) {
+	t.Helper()
+
+	query, args := buildFilmsQuery(t, req)
+	want, _ := buildFilmsQuery(t, benignFilmsRequest(req))
+	if query != want {
+		t.Fatalf("request %+v changed the statement:\n%s\nwant:\n%s", req, query, want)
+	}
+
+	matches := placeholderRe.FindAllStringSubmatch(query, -1)
+	if len(matches) != len(args) {
+		t.Fatalf("%d placeholders for %d arguments in %q", len(matches), len(args), query)
+	}
+	for i, m := range matches {
+		if m[1] != strconv.Itoa(i+1) {
+			t.Fatalf("placeholder $%s found at position %d in %q", m[1], i+1, query)
+		}
+	}
+
+	return query, args
+}
+
+func containsArg(args []any, v any) bool {
+	for _, a := range args {
+		if reflect.DeepEqual(a, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestToQueryConditionsHostileInput(t *testing.T) {
+	for _, s := range hostileInputs {
+		req := &GetFilmsRequest{
+			SortQuery:    "name,asc",
+			FilmQuery:    s,
+			ActorQuery:   s,
+			FuzzyQuery:   "true",
+			GenreQuery:   []string{"drama", s},
+			ActorIDQuery: []string{"1", "2"},
+			AfterQuery:   tools.EncodeCursor(&tools.Cursor{Sort: "name,asc", Value: s, ID: 7}),
+		}
+		if len(strings.TrimSpace(s)) == 0 {
+			req.GenreQuery = []string{"drama"}
+		}
+
+		query, args := checkFilmsQuery(t, req)
+
+		// single symbols like '_' are part of the statement itself, longer
+		// inputs must not show up in it at all
+		if len(s) > 2 && strings.Contains(query, s) {
+			t.Errorf("input %q found in statement %q", s, query)
+		}
+
+		for _, v := range []any{tools.ContainsPattern(s), s} {
+			if !containsArg(args, v) {
+				t.Errorf("argument %q not bound for input %q", v, s)
+			}
+		}
+	}
+}
+
+func FuzzToQueryConditions(f *testing.F) {
+	for _, v := range hostileInputs {
+		f.Add(v, v, v, v, false, true)
+	}
+	f.Add("", "", "", "", true, false)
+
+	f.Fuzz(func(t *testing.T, film, actor, genre, cursor string, caseSensitive, fuzzy bool) {
+		req := &GetFilmsRequest{
+			SortQuery:          "name,desc",
+			FilmQuery:          film,
+			ActorQuery:         actor,
+			CaseSensitiveQuery: strconv.FormatBool(caseSensitive),
+			FuzzyQuery:         strconv.FormatBool(fuzzy),
+		}
+		if len(strings.TrimSpace(genre)) != 0 && len(genre) <= 50 {
+			req.GenreQuery = []string{genre}
+		}
+		if len(cursor) != 0 {
+			req.AfterQuery = tools.EncodeCursor(&tools.Cursor{Sort: "name,desc", Value: cursor, ID: 1})
+		}
+
+		_, args := checkFilmsQuery(t, req)
+
+		if len(film) != 0 && !containsArg(args, tools.ContainsPattern(film)) {
+			t.Fatalf("film filter %q not bound as an argument", film)
+		}
+		// cursors are JSON, invalid UTF-8 is replaced when encoding them
+		if len(cursor) != 0 && utf8.ValidString(cursor) && !containsArg(args, cursor) {
+			t.Fatalf("cursor value %q not bound as an argument", cursor)
+		}
+	})
+}
+
+func TestToFilmETag(t *testing.T) {
+	base := func() *FilmResponse {
+		return &FilmResponse{
+			Version:     3,
+			UserRating:  RatingStatsResponse{Histogram: []int64{0, 1, 0}},
+			ReviewCount: 1,
+		}
+	}
+	etag := ToFilmETag(base(), tools.MediaTypeJSON)
+
+	if !tools.MatchVersion(etag, 3) {
+		t.Fatalf("ToFilmETag() = %s, want a tag of version 3", etag)
+	}
+
+	rated := base()
+	rated.UserRating.Histogram[2]++
+	reviewed := base()
+	reviewed.ReviewCount++
+	watched := base()
+	watched.Watched, watched.OnWatchlist = new(bool), new(bool)
+	*watched.Watched = true
+
+	for name, res := range map[string]*FilmResponse{"rated": rated, "reviewed": reviewed, "watched": watched} {
+		if other := ToFilmETag(res, tools.MediaTypeJSON); other == etag || !tools.MatchVersion(other, 3) {
+			t.Errorf("ToFilmETag() of %s film = %s, want another tag of version 3 than %s", name, other, etag)
+		}
+	}
+
+	if etag := ToFilmETag(watched, tools.MediaTypeJSON); !strings.HasSuffix(etag, `.w1l0.json"`) {
+		t.Errorf("ToFilmETag() = %s, want viewer flags w1l0 of the JSON representation", etag)
+	}
+
+	if xml := ToFilmETag(base(), tools.MediaTypeXML); xml == etag || !tools.MatchVersion(xml, 3) {
+		t.Errorf("ToFilmETag() of XML = %s, want another tag of version 3 than JSON %s", xml, etag)
+	}
+}
@@ -2,56 +2,169 @@ package film
 
 import (
 	"context"
+	"encoding/xml"
 	"net/http"
 	"time"
+
+	"film-library/src/internal/tools"
 )
 
 type Film struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	ReleaseDate time.Time `json:"releasedate"`
-	Rating      int       `json:"rating"`
-	Actors      []string  `json:"actors"`
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	ReleaseDate time.Time         `json:"releasedate"`
+	Rating      int               `json:"rating"`
+	Actors      []*ActorShort     `json:"actors"`
+	Genres      []string          `json:"genres"`
+	UserRating  RatingStats       `json:"userRating"`
+	ReviewCount int               `json:"reviewCount"`
+	Poster      map[string]string `json:"poster"`
+	Version     int               `json:"version"`
+}
+
+// RatingStats summarizes ratings given to a film by users, Histogram[i]
+// holds the number of ratings equal to i+1.
+type RatingStats struct {
+	Average   float64
+	Count     int
+	Histogram []int64
+}
+
+// ViewerFlags tell how a film relates to the user requesting it.
+type ViewerFlags struct {
+	Watched     bool
+	OnWatchlist bool
+}
+
+type FilmRelations struct {
+	ID        int
+	Relations []*Relation
+}
+
+// Relation reads as "the film is a Type of the film FilmID", e.g. a
+// sequel_of.
+type Relation struct {
+	FilmID int
+	Type   string
+}
+
+// RelatedFilm is a film linked to another one, Inverse is set when the
+// relation points from the related film, e.g. it is a sequel of ours.
+type RelatedFilm struct {
+	ID          int
+	Name        string
+	ReleaseDate time.Time
+	Relation    string
+	Inverse     bool
+}
+
+type FilmFranchise struct {
+	ID                 int
+	Name               string
+	ReleaseOrder       int
+	ChronologicalOrder int
+}
+
+type UserRating struct {
+	UserID int
+	FilmID int
+	Rating int
 }
 
 type FilmRepository interface {
 	GetFilm(ctx context.Context, id int) (*Film, error)
+	// GetFilmVersion locks the film until the end of the transaction.
+	GetFilmVersion(ctx context.Context, id int) (int, error)
 	AddFilm(ctx context.Context, f *Film) (*Film, error)
 	DeleteFilm(ctx context.Context, id int) error
 	UpdateFilm(ctx context.Context, f *Film) error
 	GetFilms(ctx context.Context, q *Query) ([]*Film, error)
+	CountFilms(ctx context.Context, q *Query) (int, error)
+	SuggestFilms(ctx context.Context, text string, limit int) ([]*Suggestion, error)
 	GetFilmActors(ctx context.Context, id int) ([]*ActorShort, error)
-	AddFilmActors(ctx context.Context, fa *FilmActors) error
+	AddFilmActors(ctx context.Context, fc *FilmCast) error
 	DeleteFilmActors(ctx context.Context, fa *FilmActors) error
+	GetFilmGenres(ctx context.Context, id int) ([]*GenreShort, error)
+	AddFilmGenres(ctx context.Context, fg *FilmGenres) error
+	DeleteFilmGenres(ctx context.Context, fg *FilmGenres) error
+	GetFilmCrew(ctx context.Context, id int) ([]*CrewMember, error)
+	AddFilmCrew(ctx context.Context, fc *FilmCrew) error
+	DeleteFilmCrew(ctx context.Context, fc *FilmCrew) error
+	SetUserRating(ctx context.Context, ur *UserRating) error
+	DeleteUserRating(ctx context.Context, ur *UserRating) error
+	GetRatingStats(ctx context.Context, id int) (*RatingStats, error)
+	GetViewerFlags(ctx context.Context, userID int, ids []int) (map[int]*ViewerFlags, error)
+	GetFilmRelations(ctx context.Context, id int) ([]*RelatedFilm, error)
+	AddFilmRelations(ctx context.Context, fr *FilmRelations) error
+	DeleteFilmRelations(ctx context.Context, fr *FilmRelations) error
+	GetFilmFranchises(ctx context.Context, id int) ([]*FilmFranchise, error)
 }
 
 type FilmService interface {
-	GetFilms(ctx context.Context, req *GetFilmsRequest) ([]*FilmResponse, error)
+	GetFilms(ctx context.Context, req *GetFilmsRequest) (*GetFilmsResponse, error)
+	SuggestFilms(ctx context.Context, req *SuggestRequest) ([]*SuggestionResponse, error)
 	AddFilm(ctx context.Context, req *AddFilmRequest) (*FilmResponse, error)
 	GetFilm(ctx context.Context, req *FilmIdRequest) (*FilmResponse, error)
 	UpdateFilm(ctx context.Context, req *FilmIdInfoRequest) (*FilmResponse, error)
+	PatchFilm(ctx context.Context, req *FilmPatchRequest) (*FilmResponse, error)
 	DeleteFilm(ctx context.Context, req *FilmIdRequest) (*FilmResponse, error)
 	GetFilmActors(ctx context.Context, req *FilmIdRequest) ([]*ActorShortResponse, error)
-	AddFilmActors(ctx context.Context, req *FilmActorsRequest) ([]*ActorShortResponse, error)
+	AddFilmActors(ctx context.Context, req *FilmCastRequest) ([]*ActorShortResponse, error)
 	DeleteFilmActors(ctx context.Context, req *FilmActorsRequest) ([]*ActorShortResponse, error)
+	GetFilmGenres(ctx context.Context, req *FilmIdRequest) ([]*GenreShortResponse, error)
+	AddFilmGenres(ctx context.Context, req *FilmGenresRequest) ([]*GenreShortResponse, error)
+	DeleteFilmGenres(ctx context.Context, req *FilmGenresRequest) ([]*GenreShortResponse, error)
+	GetFilmCrew(ctx context.Context, req *FilmIdRequest) ([]*CrewMemberResponse, error)
+	AddFilmCrew(ctx context.Context, req *FilmCrewRequest) ([]*CrewMemberResponse, error)
+	DeleteFilmCrew(ctx context.Context, req *FilmCrewRequest) ([]*CrewMemberResponse, error)
+	SetUserRating(ctx context.Context, req *UserRatingRequest) (*UserRatingResponse, error)
+	DeleteUserRating(ctx context.Context, req *UserRatingRequest) (*UserRatingResponse, error)
+	GetFilmRelations(ctx context.Context, req *FilmIdRequest) ([]*RelatedFilmResponse, error)
+	AddFilmRelations(ctx context.Context, req *FilmRelationsRequest) ([]*RelatedFilmResponse, error)
+	DeleteFilmRelations(ctx context.Context, req *FilmRelationsRequest) ([]*RelatedFilmResponse, error)
 }
 
 type FilmHandler interface {
 	GetFilms(w http.ResponseWriter, r *http.Request)
+	SuggestFilms(w http.ResponseWriter, r *http.Request)
 	AddFilm(w http.ResponseWriter, r *http.Request)
 	GetFilm(w http.ResponseWriter, r *http.Request)
 	UpdateFilm(w http.ResponseWriter, r *http.Request)
+	PatchFilm(w http.ResponseWriter, r *http.Request)
 	DeleteFilm(w http.ResponseWriter, r *http.Request)
 	GetFilmActors(w http.ResponseWriter, r *http.Request)
 	AddFilmActors(w http.ResponseWriter, r *http.Request)
 	DeleteFilmActors(w http.ResponseWriter, r *http.Request)
+	GetFilmGenres(w http.ResponseWriter, r *http.Request)
+	AddFilmGenres(w http.ResponseWriter, r *http.Request)
+	DeleteFilmGenres(w http.ResponseWriter, r *http.Request)
+	GetFilmCrew(w http.ResponseWriter, r *http.Request)
+	AddFilmCrew(w http.ResponseWriter, r *http.Request)
+	DeleteFilmCrew(w http.ResponseWriter, r *http.Request)
+	SetUserRating(w http.ResponseWriter, r *http.Request)
+	DeleteUserRating(w http.ResponseWriter, r *http.Request)
+	GetFilmRelations(w http.ResponseWriter, r *http.Request)
+	AddFilmRelations(w http.ResponseWriter, r *http.Request)
+	DeleteFilmRelations(w http.ResponseWriter, r *http.Request)
 }
 
 type Query struct {
-	Sort  []string
-	Actor string
-	Film  string
+	Sort           []string
+	Actor          string
+	Film           string
+	CaseSensitive  bool
+	Fuzzy          bool
+	RatingMin      *int
+	RatingMax      *int
+	ReleasedAfter  time.Time
+	ReleasedBefore time.Time
+	ActorIDs       []int
+	MatchAllActors bool
+	Genres         []string
+	Limit          int
+	Cursor         *tools.Cursor
+	Backward       bool
 }
 
 type FilmActors struct {
@@ -59,15 +172,95 @@ type FilmActors struct {
 	ActorIDs []int
 }
 
+type FilmCast struct {
+	ID      int
+	Credits []*CastCredit
+}
+
+// CastCredit binds an actor to a film, Billing of zero means the actor
+// is not billed.
+type CastCredit struct {
+	ActorID    int
+	Characters []string
+	Billing    int
+}
+
 type ActorShort struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Characters []string `json:"characters"`
+	Billing    int      `json:"billing"`
+}
+
+type FilmGenres struct {
+	ID       int
+	GenreIDs []int
+}
+
+type GenreShort struct {
+	ID   int
+	Name string
+}
+
+type FilmCrew struct {
+	ID      int
+	Credits []*CrewCredit
+}
+
+type CrewCredit struct {
+	PersonID int
+	Role     string
+}
+
+type CrewMember struct {
 	ID   int
 	Name string
+	Role string
 }
 
 type GetFilmsRequest struct {
-	SortQuery  string
-	FilmQuery  string
-	ActorQuery string
+	SortQuery           string
+	FilmQuery           string
+	ActorQuery          string
+	CaseSensitiveQuery  string
+	FuzzyQuery          string
+	RatingMinQuery      string
+	RatingMaxQuery      string
+	ReleasedAfterQuery  string
+	ReleasedBeforeQuery string
+	ActorIDQuery        []string
+	ActorMatchQuery     string
+	GenreQuery          []string
+	LimitQuery          string
+	AfterQuery          string
+	BeforeQuery         string
+	TotalQuery          string
+	ViewerID            int
+}
+
+type GetFilmsResponse struct {
+	XMLName    xml.Name        `json:"-" xml:"films"`
+	Films      []*FilmResponse `json:"films" xml:"film"`
+	NextCursor string          `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+	PrevCursor string          `json:"prevCursor,omitempty" xml:"prevCursor,omitempty"`
+	Total      *int            `json:"total,omitempty" xml:"total,omitempty"`
+}
+
+type Suggestion struct {
+	ID         int
+	Name       string
+	Similarity float64
+}
+
+type SuggestRequest struct {
+	TextQuery  string
+	LimitQuery string
+}
+
+type SuggestionResponse struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	Similarity float64 `json:"similarity"`
 }
 
 type AddFilmRequest struct {
@@ -76,25 +269,67 @@ type AddFilmRequest struct {
 }
 
 type FilmInfo struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	ReleaseDate string `json:"releasedate"`
-	Rating      int    `json:"rating"`
+	Name        string `json:"name" xml:"name"`
+	Description string `json:"description" xml:"description"`
+	ReleaseDate string `json:"releasedate" xml:"releasedate"`
+	Rating      int    `json:"rating" xml:"rating"`
 }
 
 type FilmResponse struct {
-	ID     int      `json:"id"`
-	Info   FilmInfo `json:"info"`
-	Actors []string `json:"actors,omitempty"`
+	XMLName     xml.Name                 `json:"-" xml:"film"`
+	ID          int                      `json:"id" xml:"id"`
+	Info        FilmInfo                 `json:"info" xml:"info"`
+	Actors      []*ActorShortResponse    `json:"actors,omitempty" xml:"actor,omitempty"`
+	Genres      []string                 `json:"genres,omitempty" xml:"genre,omitempty"`
+	UserRating  RatingStatsResponse      `json:"userRating" xml:"userRating"`
+	ReviewCount int                      `json:"reviewCount" xml:"reviewCount"`
+	Poster      tools.StringMap          `json:"poster,omitempty" xml:"poster,omitempty"`
+	Watched     *bool                    `json:"watched,omitempty" xml:"watched,omitempty"`
+	OnWatchlist *bool                    `json:"onWatchlist,omitempty" xml:"onWatchlist,omitempty"`
+	Relations   []*RelatedFilmResponse   `json:"relations,omitempty" xml:"relation,omitempty"`
+	Franchises  []*FilmFranchiseResponse `json:"franchises,omitempty" xml:"franchise,omitempty"`
+	Version     int                      `json:"-" xml:"-"`
+}
+
+type RatingStatsResponse struct {
+	Average   float64 `json:"average" xml:"average"`
+	Count     int     `json:"count" xml:"count"`
+	Histogram []int64 `json:"histogram" xml:"histogram>count"`
 }
 
+type UserRatingRequest struct {
+	ID     string
+	UserID int
+	Rating int `json:"rating"`
+}
+
+type UserRatingResponse struct {
+	Rating     int                 `json:"rating,omitempty"`
+	UserRating RatingStatsResponse `json:"userRating"`
+}
+
+// FilmIdRequest identifies a film, ViewerID is set only where the
+// response depends on the authenticated user and IfMatch only where the
+// film is changed.
 type FilmIdRequest struct {
-	ID string
+	ID       string
+	ViewerID int
+	IfMatch  string
 }
 
 type FilmIdInfoRequest struct {
-	ID   string
-	Info FilmInfo
+	ID      string
+	Info    FilmInfo
+	IfMatch string
+}
+
+// FilmPatchRequest holds a patch document of MediaType, one of
+// tools.MediaTypeMergePatch and tools.MediaTypeJSONPatch.
+type FilmPatchRequest struct {
+	ID        string
+	MediaType string
+	Patch     []byte
+	IfMatch   string
 }
 
 type FilmActorsRequest struct {
@@ -102,7 +337,70 @@ type FilmActorsRequest struct {
 	ActorIDs []int
 }
 
+type FilmCastRequest struct {
+	ID      string
+	Credits []*CastCreditRequest
+}
+
+type CastCreditRequest struct {
+	ActorID    int      `json:"actorId"`
+	Characters []string `json:"characters"`
+	Billing    int      `json:"billing"`
+}
+
 type ActorShortResponse struct {
+	ID         int      `json:"id" xml:"id"`
+	Name       string   `json:"name" xml:"name"`
+	Characters []string `json:"characters,omitempty" xml:"character,omitempty"`
+	Billing    int      `json:"billing,omitempty" xml:"billing,omitempty"`
+}
+
+type FilmGenresRequest struct {
+	ID       string
+	GenreIDs []int
+}
+
+type GenreShortResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type FilmCrewRequest struct {
+	ID      string
+	Credits []*CrewCreditRequest
+}
+
+type CrewCreditRequest struct {
+	PersonID int    `json:"personId"`
+	Role     string `json:"role"`
+}
+
+type CrewMemberResponse struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type FilmRelationsRequest struct {
+	ID        string
+	Relations []*RelationRequest
+}
+
+type RelationRequest struct {
+	FilmID int    `json:"filmId"`
+	Type   string `json:"type"`
+}
+
+type RelatedFilmResponse struct {
+	ID          int    `json:"id" xml:"id"`
+	Name        string `json:"name" xml:"name"`
+	ReleaseDate string `json:"releasedate" xml:"releasedate"`
+	Relation    string `json:"relation" xml:"relation"`
+}
+
+type FilmFranchiseResponse struct {
+	ID                 int    `json:"id" xml:"id"`
+	Name               string `json:"name" xml:"name"`
+	ReleaseOrder       int    `json:"releaseOrder" xml:"releaseOrder"`
+	ChronologicalOrder int    `json:"chronologicalOrder,omitempty" xml:"chronologicalOrder,omitempty"`
 }
@@ -21,10 +21,35 @@ func NewHandler(fs FilmService) *Handler {
 }
 
 func (h *Handler) GetFilms(w http.ResponseWriter, r *http.Request) {
+	mediaType := tools.Accept(w, r, tools.TabularOffers)
+	if len(mediaType) == 0 {
+		return
+	}
+
+	var viewerID int
+	if uc, ok := tools.UserClaimsFromContext(r.Context()); ok {
+		viewerID = uc.ID
+	}
+
+	query := r.URL.Query()
 	res, err := h.service.GetFilms(r.Context(), &GetFilmsRequest{
-		SortQuery:  r.URL.Query().Get("sort"),
-		FilmQuery:  r.URL.Query().Get("film"),
-		ActorQuery: r.URL.Query().Get("actor"),
+		SortQuery:           query.Get("sort"),
+		FilmQuery:           query.Get("film"),
+		ActorQuery:          query.Get("actor"),
+		CaseSensitiveQuery:  query.Get("caseSensitive"),
+		FuzzyQuery:          query.Get("fuzzy"),
+		RatingMinQuery:      query.Get("ratingMin"),
+		RatingMaxQuery:      query.Get("ratingMax"),
+		ReleasedAfterQuery:  query.Get("releasedAfter"),
+		ReleasedBeforeQuery: query.Get("releasedBefore"),
+		ActorIDQuery:        query["actorId"],
+		ActorMatchQuery:     query.Get("actorMatch"),
+		GenreQuery:          query["genre"],
+		LimitQuery:          query.Get("limit"),
+		AfterQuery:          query.Get("after"),
+		BeforeQuery:         query.Get("before"),
+		TotalQuery:          query.Get("total"),
+		ViewerID:            viewerID,
 	})
 	if err != nil {
 		log.Printf("ERROR: failed to get films err=%s\n", err.Error())
@@ -42,6 +67,31 @@ func (h *Handler) GetFilms(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tools.SetPageLinks(w, r, res.NextCursor, res.PrevCursor)
+	tools.Render(w, r, mediaType, http.StatusOK, res)
+}
+
+func (h *Handler) SuggestFilms(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.SuggestFilms(r.Context(), &SuggestRequest{
+		TextQuery:  r.URL.Query().Get("q"),
+		LimitQuery: r.URL.Query().Get("limit"),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to suggest films err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
 	tools.JSON(w, r, http.StatusOK, res)
 }
 
@@ -88,9 +138,17 @@ func (h *Handler) AddFilm(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetFilm(w http.ResponseWriter, r *http.Request) {
+	mediaType := tools.Accept(w, r, tools.TabularOffers)
+	if len(mediaType) == 0 {
+		return
+	}
+
 	req := FilmIdRequest{
 		ID: r.PathValue("id"),
 	}
+	if uc, ok := tools.UserClaimsFromContext(r.Context()); ok {
+		req.ViewerID = uc.ID
+	}
 
 	res, err := h.service.GetFilm(r.Context(), &req)
 	if err != nil {
@@ -104,7 +162,13 @@ func (h *Handler) GetFilm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tools.JSON(w, r, http.StatusOK, res)
+	// the body is negotiated and carries viewer flags of the caller
+	w.Header().Set("vary", "Accept, Cookie")
+	if tools.NotModified(w, r, ToFilmETag(res, mediaType)) {
+		return
+	}
+
+	tools.Render(w, r, mediaType, http.StatusOK, res)
 }
 
 func (h *Handler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
@@ -113,6 +177,7 @@ func (h *Handler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	req.ID = r.PathValue("id")
+	req.IfMatch = r.Header.Get("if-match")
 
 	res, err := h.service.UpdateFilm(r.Context(), &req)
 	if err != nil {
@@ -123,6 +188,11 @@ func (h *Handler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, ErrVersionMismatch) {
+			tools.PreconditionFailed(w, r)
+			return
+		}
+
 		var ve *tools.ValidationError
 		if errors.As(err, &ve) {
 			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
@@ -132,10 +202,60 @@ func (h *Handler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if errors.Is(err, ErrEmptyUpdate) {
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	w.Header().Set("etag", tools.ETag(res.Version))
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) PatchFilm(w http.ResponseWriter, r *http.Request) {
+	mediaType, patch, ok := tools.BindPatch(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.PatchFilm(r.Context(), &FilmPatchRequest{
+		ID:        r.PathValue("id"),
+		MediaType: mediaType,
+		Patch:     patch,
+		IfMatch:   r.Header.Get("if-match"),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to patch film err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrVersionMismatch) {
+			tools.PreconditionFailed(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
 			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
 				ErrorType: tools.ErrorTypeValidation,
-				Body:      "empty update",
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, tools.ErrPatchInvalid) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "invalid patch document",
+			})
+			return
+		}
+
+		if errors.Is(err, tools.ErrPatchConflict) {
+			tools.JSON(w, r, http.StatusConflict, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "patch does not apply to the film",
 			})
 			return
 		}
@@ -144,12 +264,14 @@ func (h *Handler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("etag", tools.ETag(res.Version))
 	tools.JSON(w, r, http.StatusOK, res)
 }
 
 func (h *Handler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
 	req := FilmIdRequest{
-		ID: r.PathValue("id"),
+		ID:      r.PathValue("id"),
+		IfMatch: r.Header.Get("if-match"),
 	}
 
 	res, err := h.service.DeleteFilm(r.Context(), &req)
@@ -161,6 +283,11 @@ func (h *Handler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, ErrVersionMismatch) {
+			tools.PreconditionFailed(w, r)
+			return
+		}
+
 		tools.InternalServerError(w, r)
 		return
 	}
@@ -190,8 +317,8 @@ func (h *Handler) GetFilmActors(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) AddFilmActors(w http.ResponseWriter, r *http.Request) {
-	var req FilmActorsRequest
-	if ok := tools.BindJSON(w, r, &req.ActorIDs); !ok {
+	var req FilmCastRequest
+	if ok := tools.BindJSON(w, r, &req.Credits); !ok {
 		return
 	}
 	req.ID = r.PathValue("id")
@@ -205,6 +332,15 @@ func (h *Handler) AddFilmActors(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
 		if errors.Is(err, ErrEmptyUpdate) {
 			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
 				ErrorType: tools.ErrorTypeValidation,
@@ -266,3 +402,405 @@ func (h *Handler) DeleteFilmActors(w http.ResponseWriter, r *http.Request) {
 
 	tools.JSON(w, r, http.StatusOK, res)
 }
+
+func (h *Handler) GetFilmGenres(w http.ResponseWriter, r *http.Request) {
+	req := FilmIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.GetFilmGenres(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get film related genres err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrZeroGenres) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) AddFilmGenres(w http.ResponseWriter, r *http.Request) {
+	var req FilmGenresRequest
+	if ok := tools.BindJSON(w, r, &req.GenreIDs); !ok {
+		return
+	}
+	req.ID = r.PathValue("id")
+
+	res, err := h.service.AddFilmGenres(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to add film related genres err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrEmptyUpdate) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "no genres provided",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrFilmGenreExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "one of the provided genres is already bound to the film",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrGenreNotExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "one of the provided genres is non-existent",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteFilmGenres(w http.ResponseWriter, r *http.Request) {
+	var req FilmGenresRequest
+	if ok := tools.BindJSON(w, r, &req.GenreIDs); !ok {
+		return
+	}
+	req.ID = r.PathValue("id")
+
+	res, err := h.service.DeleteFilmGenres(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete film related genres err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrZeroGenres) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrEmptyUpdate) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "no genres provided",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetFilmCrew(w http.ResponseWriter, r *http.Request) {
+	req := FilmIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.GetFilmCrew(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get film crew err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrZeroCrew) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) AddFilmCrew(w http.ResponseWriter, r *http.Request) {
+	var req FilmCrewRequest
+	if ok := tools.BindJSON(w, r, &req.Credits); !ok {
+		return
+	}
+	req.ID = r.PathValue("id")
+
+	res, err := h.service.AddFilmCrew(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to add film crew err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrEmptyUpdate) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "no credits provided",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrFilmCrewExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "one of the provided people is already credited with given role",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrPersonNotExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "one of the provided people is non-existent",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteFilmCrew(w http.ResponseWriter, r *http.Request) {
+	var req FilmCrewRequest
+	if ok := tools.BindJSON(w, r, &req.Credits); !ok {
+		return
+	}
+	req.ID = r.PathValue("id")
+
+	res, err := h.service.DeleteFilmCrew(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete film crew err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrZeroCrew) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrEmptyUpdate) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "no credits provided",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) SetUserRating(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	var req UserRatingRequest
+	if ok := tools.BindJSON(w, r, &req); !ok {
+		return
+	}
+	req.ID = r.PathValue("id")
+	req.UserID = uc.ID
+
+	res, err := h.service.SetUserRating(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to set user rating err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteUserRating(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := UserRatingRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+
+	res, err := h.service.DeleteUserRating(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete user rating err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrRatingNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetFilmRelations(w http.ResponseWriter, r *http.Request) {
+	req := FilmIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.GetFilmRelations(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get film relations err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrZeroRelations) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) AddFilmRelations(w http.ResponseWriter, r *http.Request) {
+	var req FilmRelationsRequest
+	if ok := tools.BindJSON(w, r, &req.Relations); !ok {
+		return
+	}
+	req.ID = r.PathValue("id")
+
+	res, err := h.service.AddFilmRelations(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to add film relations err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrEmptyUpdate) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "no relations provided",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrRelationExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "one of the provided relations already exists",
+			})
+			return
+		}
+
+		if errors.Is(err, ErrRelatedFilmNotExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "one of the provided films is non-existent",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteFilmRelations(w http.ResponseWriter, r *http.Request) {
+	var req FilmRelationsRequest
+	if ok := tools.BindJSON(w, r, &req.Relations); !ok {
+		return
+	}
+	req.ID = r.PathValue("id")
+
+	res, err := h.service.DeleteFilmRelations(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete film relations err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrZeroRelations) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrEmptyUpdate) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "no relations provided",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
@@ -3,23 +3,68 @@ package film
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
 
 	"film-library/src/internal/db"
+	"film-library/src/internal/tools"
 	"github.com/lib/pq"
 )
 
 var (
-	ErrFilmNotExist   = errors.New("actor does not exist")
-	ErrEmptyUpdate    = errors.New("no updates to apply")
-	ErrFilmActorExist = errors.New("given film and actor are already bound")
-	ErrActorNotExist  = errors.New("actor with given id does not exist")
-	ErrZeroActors     = errors.New("no actors affected")
+	ErrFilmNotExist        = errors.New("actor does not exist")
+	ErrEmptyUpdate         = errors.New("no updates to apply")
+	ErrFilmActorExist      = errors.New("given film and actor are already bound")
+	ErrActorNotExist       = errors.New("actor with given id does not exist")
+	ErrZeroActors          = errors.New("no actors affected")
+	ErrFilmGenreExist      = errors.New("given film and genre are already bound")
+	ErrGenreNotExist       = errors.New("genre with given id does not exist")
+	ErrZeroGenres          = errors.New("no genres affected")
+	ErrFilmCrewExist       = errors.New("given person is already credited with given role")
+	ErrPersonNotExist      = errors.New("person with given id does not exist")
+	ErrZeroCrew            = errors.New("no crew credits affected")
+	ErrRatingNotExist      = errors.New("film is not rated by given user")
+	ErrRelationExist       = errors.New("given films are already related")
+	ErrZeroRelations       = errors.New("no relations affected")
+	ErrRelatedFilmNotExist = errors.New("related film with given id does not exist")
 )
 
+// genreListColumn selects names of the genres of film m as an array.
+const genreListColumn = `
+	ARRAY (
+		SELECT g.genre_name FROM movie_genre mg
+		INNER JOIN genre g USING (genre_id)
+		WHERE mg.movie_id = m.movie_id
+		ORDER BY g.genre_name
+	) genre_list`
+
+// actorListColumn selects actors of film m in billing order as a JSON
+// array of {id, name} objects.
+const actorListColumn = `
+	COALESCE((
+		SELECT json_agg(json_build_object('id', a.actor_id, 'name', a.actor_name)
+			ORDER BY am.billing NULLS LAST, a.actor_name, a.actor_id)
+		FROM actor_in_movie am
+		INNER JOIN actor a USING (actor_id)
+		WHERE am.movie_id = m.movie_id AND a.deleted_at IS NULL
+	), '[]') actor_list`
+
+// ratingStatsColumns selects user rating aggregates of film m, the
+// movie_rating_stats table must be joined as rs.
+const ratingStatsColumns = `
+	COALESCE(rs.rating_average, 0), COALESCE(rs.rating_count, 0),
+	COALESCE(rs.histogram, '{}')`
+
+// reviewCountColumn counts published reviews of film m.
+const reviewCountColumn = `
+	(
+		SELECT COUNT(*) FROM review rv
+		WHERE rv.movie_id = m.movie_id AND rv.status = 'approved'
+	) review_count`
+
 var _ FilmRepository = (*Repository)(nil)
 
 type Repository struct {
@@ -37,12 +82,11 @@ func (r *Repository) GetFilm(ctx context.Context, id int) (*Film, error) {
 
 	const query = `
 		SELECT m.movie_id, m.movie_name, m.movie_description, m.releasedate,
-    		m.rating, STRING_AGG (a.actor_name, ';') movie_list
+			m.rating, ` + actorListColumn + `, ` + genreListColumn + `, ` + ratingStatsColumns + `, ` + reviewCountColumn + `,
+			COALESCE(m.poster_urls, '{}'), m.version
 		FROM movie m
-		LEFT JOIN actor_in_movie am USING (movie_id)
-		LEFT JOIN actor a USING (actor_id)
-		WHERE m.movie_id = $1
-		GROUP BY m.movie_id`
+		LEFT JOIN movie_rating_stats rs USING (movie_id)
+		WHERE m.movie_id = $1 AND m.deleted_at IS NULL`
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -51,8 +95,9 @@ func (r *Repository) GetFilm(ctx context.Context, id int) (*Film, error) {
 	defer stmt.Close()
 
 	var f Film
-	var actorString sql.NullString
-	err = stmt.QueryRowContext(ctx, id).Scan(&f.ID, &f.Name, &f.Description, &f.ReleaseDate, &f.Rating, &actorString)
+	var actorList, posterURLs []byte
+	err = stmt.QueryRowContext(ctx, id).Scan(&f.ID, &f.Name, &f.Description, &f.ReleaseDate, &f.Rating, &actorList, pq.Array(&f.Genres),
+		&f.UserRating.Average, &f.UserRating.Count, pq.Array(&f.UserRating.Histogram), &f.ReviewCount, &posterURLs, &f.Version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Printf("ERROR: actor with id=%d does not exist\n", id)
@@ -62,13 +107,44 @@ func (r *Repository) GetFilm(ctx context.Context, id int) (*Film, error) {
 		log.Printf("ERROR: failed to execute query\n")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	if len(actorString.String) != 0 {
-		f.Actors = strings.Split(actorString.String, ";")
+	if err := json.Unmarshal(actorList, &f.Actors); err != nil {
+		log.Printf("ERROR: failed to decode film actors\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := json.Unmarshal(posterURLs, &f.Poster); err != nil {
+		log.Printf("ERROR: failed to decode film poster\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return &f, nil
 }
 
+func (r *Repository) GetFilmVersion(ctx context.Context, id int) (int, error) {
+	const op = "film.Repository.GetFilmVersion"
+
+	const query = `SELECT version FROM movie WHERE movie_id = $1 AND deleted_at IS NULL FOR UPDATE`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var version int
+	err = stmt.QueryRowContext(ctx, id).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: film with id=%d does not exist\n", id)
+			return 0, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return version, nil
+}
+
 func (r *Repository) AddFilm(ctx context.Context, f *Film) (*Film, error) {
 	const op = "film.Repository.AddFilm"
 
@@ -91,11 +167,11 @@ func (r *Repository) AddFilm(ctx context.Context, f *Film) (*Film, error) {
 	return f, nil
 }
 
-func (r *Repository) AddFilmActors(ctx context.Context, fa *FilmActors) error {
+func (r *Repository) AddFilmActors(ctx context.Context, fc *FilmCast) error {
 	const op = "film.Repository.AddFilmActors"
 
-	args, values := ToQueryableLists(fa, "($%d, $1)")
-	query := `INSERT INTO actor_in_movie(actor_id, movie_id) VALUES ` + args
+	args, values := ToQueryableCast(fc, "($%d, $1, $%d, $%d)")
+	query := `INSERT INTO actor_in_movie(actor_id, movie_id, characters, billing) VALUES ` + args
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -141,7 +217,7 @@ func (r *Repository) AddFilmActors(ctx context.Context, fa *FilmActors) error {
 func (r *Repository) DeleteFilm(ctx context.Context, id int) error {
 	const op = "film.Repository.DeleteFilm"
 
-	const query = `DELETE FROM movie WHERE movie_id = $1`
+	const query = `UPDATE movie SET deleted_at = now() WHERE movie_id = $1 AND deleted_at IS NULL`
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -171,14 +247,9 @@ func (r *Repository) DeleteFilm(ctx context.Context, id int) error {
 func (r *Repository) UpdateFilm(ctx context.Context, f *Film) error {
 	const op = "film.Repository.UpdateFilm"
 
-	qo := ToQueryableObject(f)
-	if qo.IsEmpty() {
-		log.Print("ERROR: no updates to apply\n")
-		return fmt.Errorf("%s: %w", op, ErrEmptyUpdate)
-	}
-
-	query := `UPDATE movie SET ` + qo.Args(1) +
-		` WHERE movie_id = ` + fmt.Sprintf("$%d", qo.Len()+1)
+	const query = `
+		UPDATE movie SET movie_name = $1, movie_description = $2, releasedate = $3, rating = $4
+		WHERE movie_id = $5 AND deleted_at IS NULL`
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -186,9 +257,7 @@ func (r *Repository) UpdateFilm(ctx context.Context, f *Film) error {
 	}
 	defer stmt.Close()
 
-	values := qo.Values()
-	values = append(values, f.ID)
-	res, err := stmt.ExecContext(ctx, values...)
+	res, err := stmt.ExecContext(ctx, f.Name, f.Description, f.ReleaseDate, f.Rating, f.ID)
 	if err != nil {
 		log.Printf("ERROR: failed to execute query\n")
 		return fmt.Errorf("%s: %w", op, err)
@@ -210,15 +279,13 @@ func (r *Repository) UpdateFilm(ctx context.Context, f *Film) error {
 func (r *Repository) GetFilms(ctx context.Context, q *Query) ([]*Film, error) {
 	const op = "film.Repository.GetFilms"
 
-	cons := ToQueryConditions(q)
-	query := `
+	qb := tools.NewSelectBuilder(`
 		SELECT m.movie_id, m.movie_name, m.movie_description, m.releasedate,
-			m.rating, STRING_AGG (a.actor_name, ';') movie_list
-		FROM movie m 
-		LEFT JOIN actor_in_movie am USING (movie_id)
-		LEFT JOIN actor a USING (actor_id) ` +
-		cons[0] + " GROUP BY m.movie_id " +
-		cons[1] + " " + cons[2]
+			m.rating, ` + actorListColumn + `, ` + genreListColumn + `, ` + ratingStatsColumns + `, ` + reviewCountColumn + `,
+			COALESCE(m.poster_urls, '{}')
+		FROM movie m
+		LEFT JOIN movie_rating_stats rs USING (movie_id)`)
+	query, args := ToQueryConditions(q, qb).Build()
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -226,24 +293,31 @@ func (r *Repository) GetFilms(ctx context.Context, q *Query) ([]*Film, error) {
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.QueryContext(ctx)
+	rows, err := stmt.QueryContext(ctx, args...)
 	if err != nil {
 		log.Printf("ERROR: failed to execute query\n")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
 	var films []*Film
 
 	for rows.Next() {
 		var f Film
-		var actorString sql.NullString
-		err := rows.Scan(&f.ID, &f.Name, &f.Description, &f.ReleaseDate, &f.Rating, &actorString)
+		var actorList, posterURLs []byte
+		err := rows.Scan(&f.ID, &f.Name, &f.Description, &f.ReleaseDate, &f.Rating, &actorList, pq.Array(&f.Genres),
+			&f.UserRating.Average, &f.UserRating.Count, pq.Array(&f.UserRating.Histogram), &f.ReviewCount, &posterURLs)
 		if err != nil {
 			log.Printf("ERROR: failed to execute query\n")
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		if len(actorString.String) != 0 {
-			f.Actors = strings.Split(actorString.String, ";")
+		if err := json.Unmarshal(actorList, &f.Actors); err != nil {
+			log.Printf("ERROR: failed to decode film actors\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := json.Unmarshal(posterURLs, &f.Poster); err != nil {
+			log.Printf("ERROR: failed to decode film poster\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
 		films = append(films, &f)
@@ -256,14 +330,81 @@ func (r *Repository) GetFilms(ctx context.Context, q *Query) ([]*Film, error) {
 	return films, nil
 }
 
+func (r *Repository) CountFilms(ctx context.Context, q *Query) (int, error) {
+	const op = "film.Repository.CountFilms"
+
+	qb := tools.NewSelectBuilder(`SELECT COUNT(*) FROM movie m`)
+	query, args := qb.Where(ToFilterConditions(q)).Build()
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var count int
+	err = stmt.QueryRowContext(ctx, args...).Scan(&count)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *Repository) SuggestFilms(ctx context.Context, text string, limit int) ([]*Suggestion, error) {
+	const op = "film.Repository.SuggestFilms"
+
+	// prefix matches keep suggestions useful while the first few letters
+	// are typed, trigram word similarity tolerates typos later on
+	const query = `
+		SELECT movie_id, movie_name, word_similarity($1, movie_name) AS similarity
+		FROM movie
+		WHERE ($1 <% movie_name OR movie_name ILIKE $2 ESCAPE '\') AND deleted_at IS NULL
+		ORDER BY similarity DESC, movie_name, movie_id
+		LIMIT $3`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, text, tools.EscapeLike(text)+"%", limit)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var suggestions []*Suggestion
+	for rows.Next() {
+		var sg Suggestion
+		err := rows.Scan(&sg.ID, &sg.Name, &sg.Similarity)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		suggestions = append(suggestions, &sg)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return suggestions, nil
+}
+
 func (r *Repository) GetFilmActors(ctx context.Context, id int) ([]*ActorShort, error) {
 	const op = "film.Repository.GetFilmActors"
 
 	const query = `
-		SELECT a.actor_id, a.actor_name
+		SELECT a.actor_id, a.actor_name, am.characters, COALESCE(am.billing, 0)
 		FROM actor a
 		INNER JOIN actor_in_movie am USING (actor_id)
-		WHERE movie_id = $1`
+		WHERE movie_id = $1 AND a.deleted_at IS NULL
+		ORDER BY am.billing NULLS LAST, a.actor_name, a.actor_id`
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -276,11 +417,12 @@ func (r *Repository) GetFilmActors(ctx context.Context, id int) ([]*ActorShort,
 		log.Printf("ERROR: failed to execute query\n")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
 	var actors []*ActorShort
 	for rows.Next() {
 		var as ActorShort
-		err := rows.Scan(&as.ID, &as.Name)
+		err := rows.Scan(&as.ID, &as.Name, pq.Array(&as.Characters), &as.Billing)
 		if err != nil {
 			log.Printf("ERROR: failed to execute query\n")
 			return nil, fmt.Errorf("%s: %w", op, err)
@@ -299,7 +441,7 @@ func (r *Repository) GetFilmActors(ctx context.Context, id int) ([]*ActorShort,
 func (r *Repository) DeleteFilmActors(ctx context.Context, fa *FilmActors) error {
 	const op = "film.Repository.DeleteDilmActors"
 
-	args, values := ToQueryableLists(fa, "$%d")
+	args, values := ToQueryableLists(fa.ID, fa.ActorIDs, "$%d")
 	var query = "DELETE FROM actor_in_movie WHERE movie_id = $1 AND actor_id IN (" + args + ")"
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
@@ -326,3 +468,555 @@ func (r *Repository) DeleteFilmActors(ctx context.Context, fa *FilmActors) error
 
 	return nil
 }
+
+func (r *Repository) GetFilmGenres(ctx context.Context, id int) ([]*GenreShort, error) {
+	const op = "film.Repository.GetFilmGenres"
+
+	const query = `
+		SELECT g.genre_id, g.genre_name
+		FROM genre g
+		INNER JOIN movie_genre mg USING (genre_id)
+		WHERE mg.movie_id = $1
+		ORDER BY g.genre_name`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var genres []*GenreShort
+	for rows.Next() {
+		var gs GenreShort
+		err := rows.Scan(&gs.ID, &gs.Name)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		genres = append(genres, &gs)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return genres, nil
+}
+
+func (r *Repository) AddFilmGenres(ctx context.Context, fg *FilmGenres) error {
+	const op = "film.Repository.AddFilmGenres"
+
+	args, values := ToQueryableLists(fg.ID, fg.GenreIDs, "($1, $%d)")
+	query := `INSERT INTO movie_genre(movie_id, genre_id) VALUES ` + args
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, values...)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "unique_violation" {
+				log.Printf("ERROR: one of the film-genre pairs already exists\n")
+				return fmt.Errorf("%s: %w", op, ErrFilmGenreExist)
+			}
+
+			if pgErr.Code.Name() == "foreign_key_violation" {
+				if strings.Contains(pgErr.Detail, "movie_id") {
+					log.Printf("ERROR: film does not exist\n")
+					return fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+				}
+				log.Printf("ERROR: one of the genres does not exist\n")
+				return fmt.Errorf("%s: %w", op, ErrGenreNotExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Printf("INFO: %d rows inserted\n", count)
+
+	return nil
+}
+
+func (r *Repository) DeleteFilmGenres(ctx context.Context, fg *FilmGenres) error {
+	const op = "film.Repository.DeleteFilmGenres"
+
+	args, values := ToQueryableLists(fg.ID, fg.GenreIDs, "$%d")
+	query := "DELETE FROM movie_genre WHERE movie_id = $1 AND genre_id IN (" + args + ")"
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, values...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by deletion\n")
+		return fmt.Errorf("%s: %w", op, ErrZeroGenres)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetFilmCrew(ctx context.Context, id int) ([]*CrewMember, error) {
+	const op = "film.Repository.GetFilmCrew"
+
+	const query = `
+		SELECT a.actor_id, a.actor_name, mc.crew_role
+		FROM actor a
+		INNER JOIN movie_crew mc ON mc.person_id = a.actor_id
+		WHERE mc.movie_id = $1 AND a.deleted_at IS NULL
+		ORDER BY mc.crew_role, a.actor_name`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var crew []*CrewMember
+	for rows.Next() {
+		var cm CrewMember
+		err := rows.Scan(&cm.ID, &cm.Name, &cm.Role)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		crew = append(crew, &cm)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return crew, nil
+}
+
+func (r *Repository) AddFilmCrew(ctx context.Context, fc *FilmCrew) error {
+	const op = "film.Repository.AddFilmCrew"
+
+	args, values := ToQueryableCredits(fc, "($%d, $1, $%d)")
+	query := `INSERT INTO movie_crew(person_id, movie_id, crew_role) VALUES ` + args
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, values...)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "unique_violation" {
+				log.Printf("ERROR: one of the crew credits already exists\n")
+				return fmt.Errorf("%s: %w", op, ErrFilmCrewExist)
+			}
+
+			if pgErr.Code.Name() == "foreign_key_violation" {
+				if strings.Contains(pgErr.Detail, "movie_id") {
+					log.Printf("ERROR: film does not exist\n")
+					return fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+				}
+				log.Printf("ERROR: one of the people does not exist\n")
+				return fmt.Errorf("%s: %w", op, ErrPersonNotExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Printf("INFO: %d rows inserted\n", count)
+
+	return nil
+}
+
+func (r *Repository) DeleteFilmCrew(ctx context.Context, fc *FilmCrew) error {
+	const op = "film.Repository.DeleteFilmCrew"
+
+	args, values := ToQueryableCredits(fc, "($%d, $%d)")
+	query := "DELETE FROM movie_crew WHERE movie_id = $1 AND (person_id, crew_role) IN (" + args + ")"
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, values...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by deletion\n")
+		return fmt.Errorf("%s: %w", op, ErrZeroCrew)
+	}
+
+	return nil
+}
+
+func (r *Repository) SetUserRating(ctx context.Context, ur *UserRating) error {
+	const op = "film.Repository.SetUserRating"
+
+	// trashed films are not rated, the film row is not locked so that
+	// ratings do not queue up behind each other
+	const query = `
+		WITH m AS (
+			SELECT movie_id FROM movie WHERE movie_id = $2 AND deleted_at IS NULL
+		), r AS (
+			INSERT INTO movie_rating(user_id, movie_id, rating)
+			SELECT $1, m.movie_id, $3 FROM m
+			ON CONFLICT (user_id, movie_id) DO UPDATE
+			SET rating = EXCLUDED.rating, rated_at = now()
+			WHERE movie_rating.rating <> EXCLUDED.rating
+		)
+		SELECT count(*) FROM m`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var found int
+	err = stmt.QueryRowContext(ctx, ur.UserID, ur.FilmID, ur.Rating).Scan(&found)
+	if err == nil && found == 0 {
+		log.Printf("ERROR: film with id=%d does not exist\n", ur.FilmID)
+		return fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+	}
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "foreign_key_violation" && strings.Contains(pgErr.Detail, "movie_id") {
+				log.Printf("ERROR: film with id=%d does not exist\n", ur.FilmID)
+				return fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteUserRating(ctx context.Context, ur *UserRating) error {
+	const op = "film.Repository.DeleteUserRating"
+
+	const query = `DELETE FROM movie_rating WHERE user_id = $1 AND movie_id = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, ur.UserID, ur.FilmID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by delete\n")
+		return fmt.Errorf("%s: %w", op, ErrRatingNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetRatingStats(ctx context.Context, id int) (*RatingStats, error) {
+	const op = "film.Repository.GetRatingStats"
+
+	const query = `
+		SELECT ` + ratingStatsColumns + `
+		FROM movie m
+		LEFT JOIN movie_rating_stats rs USING (movie_id)
+		WHERE m.movie_id = $1 AND m.deleted_at IS NULL`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var rs RatingStats
+	err = stmt.QueryRowContext(ctx, id).Scan(&rs.Average, &rs.Count, pq.Array(&rs.Histogram))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: film with id=%d does not exist\n", id)
+			return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &rs, nil
+}
+
+func (r *Repository) GetViewerFlags(ctx context.Context, userID int, ids []int) (map[int]*ViewerFlags, error) {
+	const op = "film.Repository.GetViewerFlags"
+
+	const query = `
+		SELECT m.movie_id,
+			EXISTS (SELECT 1 FROM diary_entry d WHERE d.user_id = $1 AND d.movie_id = m.movie_id),
+			EXISTS (SELECT 1 FROM watchlist w WHERE w.user_id = $1 AND w.movie_id = m.movie_id)
+		FROM movie m
+		WHERE m.movie_id = ANY ($2)`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, userID, pq.Array(ids))
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	flags := make(map[int]*ViewerFlags, len(ids))
+
+	for rows.Next() {
+		var id int
+		var vf ViewerFlags
+		err := rows.Scan(&id, &vf.Watched, &vf.OnWatchlist)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		flags[id] = &vf
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return flags, nil
+}
+
+// GetFilmRelations returns relations of the film in both directions, the
+// ones pointing at the film are marked as inverse.
+func (r *Repository) GetFilmRelations(ctx context.Context, id int) ([]*RelatedFilm, error) {
+	const op = "film.Repository.GetFilmRelations"
+
+	const query = `
+		SELECT m.movie_id, m.movie_name, m.releasedate, mr.relation, false
+		FROM movie_relation mr
+		INNER JOIN movie m ON m.movie_id = mr.related_movie_id
+		WHERE mr.movie_id = $1 AND m.deleted_at IS NULL
+		UNION ALL
+		SELECT m.movie_id, m.movie_name, m.releasedate, mr.relation, true
+		FROM movie_relation mr
+		INNER JOIN movie m ON m.movie_id = mr.movie_id
+		WHERE mr.related_movie_id = $1 AND m.deleted_at IS NULL
+		ORDER BY 3, 1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var related []*RelatedFilm
+	for rows.Next() {
+		var rf RelatedFilm
+		err := rows.Scan(&rf.ID, &rf.Name, &rf.ReleaseDate, &rf.Relation, &rf.Inverse)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		related = append(related, &rf)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return related, nil
+}
+
+func (r *Repository) AddFilmRelations(ctx context.Context, fr *FilmRelations) error {
+	const op = "film.Repository.AddFilmRelations"
+
+	args, values := ToQueryableRelations(fr, "($1, $%d, $%d)")
+	query := `INSERT INTO movie_relation(movie_id, related_movie_id, relation) VALUES ` + args
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, values...)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "unique_violation" {
+				log.Printf("ERROR: one of the relations already exists\n")
+				return fmt.Errorf("%s: %w", op, ErrRelationExist)
+			}
+
+			if pgErr.Code.Name() == "foreign_key_violation" {
+				if strings.Contains(pgErr.Detail, "related_movie_id") {
+					log.Printf("ERROR: one of the related films does not exist\n")
+					return fmt.Errorf("%s: %w", op, ErrRelatedFilmNotExist)
+				}
+				log.Printf("ERROR: film does not exist\n")
+				return fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Printf("INFO: %d rows inserted\n", count)
+
+	return nil
+}
+
+func (r *Repository) DeleteFilmRelations(ctx context.Context, fr *FilmRelations) error {
+	const op = "film.Repository.DeleteFilmRelations"
+
+	args, values := ToQueryableRelations(fr, "($%d, $%d)")
+	query := "DELETE FROM movie_relation WHERE movie_id = $1 AND (related_movie_id, relation) IN (" + args + ")"
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, values...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by deletion\n")
+		return fmt.Errorf("%s: %w", op, ErrZeroRelations)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetFilmFranchises(ctx context.Context, id int) ([]*FilmFranchise, error) {
+	const op = "film.Repository.GetFilmFranchises"
+
+	const query = `
+		SELECT f.franchise_id, f.franchise_name, fm.release_order, COALESCE(fm.chronological_order, 0)
+		FROM franchise_movie fm
+		INNER JOIN franchise f USING (franchise_id)
+		WHERE fm.movie_id = $1
+		ORDER BY f.franchise_name`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var franchises []*FilmFranchise
+	for rows.Next() {
+		var ff FilmFranchise
+		err := rows.Scan(&ff.ID, &ff.Name, &ff.ReleaseOrder, &ff.ChronologicalOrder)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		franchises = append(franchises, &ff)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return franchises, nil
+}
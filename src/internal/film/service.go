@@ -5,26 +5,35 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
+	"strings"
 
+	"film-library/src/internal/db"
+	"film-library/src/internal/history"
 	"film-library/src/internal/tools"
 )
 
 var (
-	ErrIdInvalid = errors.New("invalid id")
+	ErrIdInvalid       = errors.New("invalid id")
+	ErrVersionMismatch = errors.New("film version does not match")
 )
 
 type Service struct {
-	repo FilmRepository
+	repo    FilmRepository
+	tx      db.Transactor
+	history history.Recorder
 }
 
-func NewService(fr FilmRepository) *Service {
+func NewService(fr FilmRepository, tx db.Transactor, hr history.Recorder) *Service {
 	return &Service{
-		repo: fr,
+		repo:    fr,
+		tx:      tx,
+		history: hr,
 	}
 }
 
-func (s *Service) GetFilms(ctx context.Context, req *GetFilmsRequest) ([]*FilmResponse, error) {
+func (s *Service) GetFilms(ctx context.Context, req *GetFilmsRequest) (*GetFilmsResponse, error) {
 	const op = "film.Service.GetFilms"
 
 	vErr := ValidateGetFilmsRequest(req)
@@ -34,17 +43,91 @@ func (s *Service) GetFilms(ctx context.Context, req *GetFilmsRequest) ([]*FilmRe
 	}
 	q := ToQuery(req)
 
+	// one extra film is requested to find out whether the next page exists
+	limit := q.Limit
+	q.Limit = limit + 1
+
 	films, err := s.repo.GetFilms(ctx, q)
 	if err != nil {
 		log.Printf("ERROR: failed to get films")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res := make([]*FilmResponse, 0, len(films))
+	hasMore := len(films) > limit
+	if hasMore {
+		films = films[:limit]
+	}
+	if q.Backward {
+		slices.Reverse(films)
+	}
+
+	res := &GetFilmsResponse{
+		Films: make([]*FilmResponse, 0, len(films)),
+	}
 	for _, v := range films {
-		res = append(res, ToFilmResponse(v))
+		res.Films = append(res.Films, ToFilmResponse(v))
+	}
+
+	if req.ViewerID != 0 && len(films) != 0 {
+		ids := make([]int, 0, len(films))
+		for _, v := range films {
+			ids = append(ids, v.ID)
+		}
+
+		flags, err := s.repo.GetViewerFlags(ctx, req.ViewerID, ids)
+		if err != nil {
+			log.Printf("ERROR: failed to get viewer flags of films")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, v := range res.Films {
+			SetViewerFlags(v, flags)
+		}
+	}
+
+	next, prev := tools.PageLinks(hasMore, q.Backward, q.Cursor != nil)
+	if len(films) != 0 {
+		if next {
+			res.NextCursor = tools.EncodeCursor(ToFilmCursor(films[len(films)-1], q.Sort))
+		}
+		if prev {
+			res.PrevCursor = tools.EncodeCursor(ToFilmCursor(films[0], q.Sort))
+		}
+	}
+
+	if req.TotalQuery == "true" {
+		total, err := s.repo.CountFilms(ctx, q)
+		if err != nil {
+			log.Printf("ERROR: failed to count films")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		res.Total = &total
+	}
+
+	return res, nil
+}
+
+func (s *Service) SuggestFilms(ctx context.Context, req *SuggestRequest) ([]*SuggestionResponse, error) {
+	const op = "film.Service.SuggestFilms"
+
+	vErr := ValidateSuggestRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	limit := defaultSuggestLimit
+	if len(req.LimitQuery) != 0 {
+		limit, _ = strconv.Atoi(req.LimitQuery)
 	}
 
+	suggestions, err := s.repo.SuggestFilms(ctx, strings.TrimSpace(req.TextQuery), limit)
+	if err != nil {
+		log.Printf("ERROR: failed to get film suggestions from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToSuggestionsResponse(suggestions)
+
 	return res, nil
 }
 
@@ -56,7 +139,7 @@ func (s *Service) AddFilm(ctx context.Context, req *AddFilmRequest) (*FilmRespon
 		log.Printf("ERROR: failed request empty validation\n")
 		return nil, fmt.Errorf("%s: %w", op, vErr)
 	}
-	vErr = ValidateFormatFilmInfo(&req.Info, false)
+	vErr = ValidateFormatFilmInfo(&req.Info)
 	if vErr != nil {
 		log.Printf("ERROR: failed request format validation\n")
 		return nil, fmt.Errorf("%s: %w", op, vErr)
@@ -70,20 +153,27 @@ func (s *Service) AddFilm(ctx context.Context, req *AddFilmRequest) (*FilmRespon
 	}
 	film := ToFilm(&req.Info)
 
-	film, err := s.repo.AddFilm(ctx, film)
-	if err != nil {
-		log.Printf("ERROR: failed to add film information\n")
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
-
-	fa := &FilmActors{
-		ID:       film.ID,
-		ActorIDs: ToActorIDs(tools.RemoveDuplicateInt(req.ActorIDs)),
-	}
-	err = s.repo.AddFilmActors(ctx, fa)
+	// the film, its actors and its first version are added together
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		film, err = s.repo.AddFilm(ctx, film)
+		if err != nil {
+			log.Printf("ERROR: failed to add film information\n")
+			return err
+		}
+
+		fc := &FilmCast{
+			ID:      film.ID,
+			Credits: ToUncreditedCast(tools.RemoveDuplicateInt(req.ActorIDs)),
+		}
+		if err := s.repo.AddFilmActors(ctx, fc); err != nil {
+			log.Printf("ERROR: failed to bind provided actors and film\n")
+			return err
+		}
+
+		return s.record(ctx, film.ID, history.ActionCreate)
+	})
 	if err != nil {
-		log.Printf("ERROR: failed to bind provided actors and film\n")
-		s.repo.DeleteFilm(ctx, film.ID)
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -115,6 +205,29 @@ func (s *Service) GetFilm(ctx context.Context, req *FilmIdRequest) (*FilmRespons
 
 	res := ToFilmResponse(actor)
 
+	related, err := s.repo.GetFilmRelations(ctx, res.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to get film relations from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	res.Relations = ToRelatedFilmsResponse(related)
+
+	franchises, err := s.repo.GetFilmFranchises(ctx, res.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to get film franchises from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	res.Franchises = ToFilmFranchisesResponse(franchises)
+
+	if req.ViewerID != 0 {
+		flags, err := s.repo.GetViewerFlags(ctx, req.ViewerID, []int{res.ID})
+		if err != nil {
+			log.Printf("ERROR: failed to get viewer flags of film\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		SetViewerFlags(res, flags)
+	}
+
 	return res, nil
 }
 
@@ -127,7 +240,12 @@ func (s *Service) UpdateFilm(ctx context.Context, req *FilmIdInfoRequest) (*Film
 		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
 	}
 
-	vErr := ValidateFormatFilmInfo(&req.Info, true)
+	vErr := ValidateEmptyFilmInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request empty validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	vErr = ValidateFormatFilmInfo(&req.Info)
 	if vErr != nil {
 		log.Printf("ERROR: failed request format validation\n")
 		return nil, fmt.Errorf("%s: %w", op, vErr)
@@ -136,9 +254,19 @@ func (s *Service) UpdateFilm(ctx context.Context, req *FilmIdInfoRequest) (*Film
 	film := ToFilm(&req.Info)
 	film.ID = int(id)
 
-	err = s.repo.UpdateFilm(ctx, film)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkVersion(ctx, film.ID, req.IfMatch); err != nil {
+			return err
+		}
+
+		if err := s.repo.UpdateFilm(ctx, film); err != nil {
+			log.Printf("ERROR: failed to update film record in repository")
+			return err
+		}
+
+		return s.record(ctx, film.ID, history.ActionUpdate)
+	})
 	if err != nil {
-		log.Printf("ERROR: failed to update film record in repository")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -153,6 +281,67 @@ func (s *Service) UpdateFilm(ctx context.Context, req *FilmIdInfoRequest) (*Film
 	return res, nil
 }
 
+// PatchFilm applies a JSON merge patch or JSON patch to the info of the
+// film, the patched info replaces the current one as with UpdateFilm.
+func (s *Service) PatchFilm(ctx context.Context, req *FilmPatchRequest) (*FilmResponse, error) {
+	const op = "film.Service.PatchFilm"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkVersion(ctx, int(id), req.IfMatch); err != nil {
+			return err
+		}
+
+		film, err := s.repo.GetFilm(ctx, int(id))
+		if err != nil {
+			log.Printf("ERROR: failed to get film record from repository\n")
+			return err
+		}
+
+		var info FilmInfo
+		if err := tools.ApplyPatch(req.MediaType, &ToFilmResponse(film).Info, req.Patch, &info); err != nil {
+			log.Printf("ERROR: failed to apply patch to film\n")
+			return err
+		}
+
+		if vErr := ValidateEmptyFilmInfo(&info); vErr != nil {
+			log.Printf("ERROR: failed patched film empty validation\n")
+			return vErr
+		}
+		if vErr := ValidateFormatFilmInfo(&info); vErr != nil {
+			log.Printf("ERROR: failed patched film format validation\n")
+			return vErr
+		}
+
+		film = ToFilm(&info)
+		film.ID = int(id)
+		if err := s.repo.UpdateFilm(ctx, film); err != nil {
+			log.Printf("ERROR: failed to update film record in repository")
+			return err
+		}
+
+		return s.record(ctx, film.ID, history.ActionUpdate)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	film, err := s.repo.GetFilm(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToFilmResponse(film)
+
+	return res, nil
+}
+
 func (s *Service) DeleteFilm(ctx context.Context, req *FilmIdRequest) (*FilmResponse, error) {
 	const op = "film.Service.DeleteFilm"
 
@@ -168,9 +357,19 @@ func (s *Service) DeleteFilm(ctx context.Context, req *FilmIdRequest) (*FilmResp
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = s.repo.DeleteFilm(ctx, int(id))
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkVersion(ctx, int(id), req.IfMatch); err != nil {
+			return err
+		}
+
+		if err := s.repo.DeleteFilm(ctx, int(id)); err != nil {
+			log.Printf("ERROR: failed to delete film record in repository")
+			return err
+		}
+
+		return s.record(ctx, int(id), history.ActionDelete)
+	})
 	if err != nil {
-		log.Printf("ERROR: failed to delete film record in repository")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -203,7 +402,7 @@ func (s *Service) GetFilmActors(ctx context.Context, req *FilmIdRequest) ([]*Act
 	return res, nil
 }
 
-func (s *Service) AddFilmActors(ctx context.Context, req *FilmActorsRequest) ([]*ActorShortResponse, error) {
+func (s *Service) AddFilmActors(ctx context.Context, req *FilmCastRequest) ([]*ActorShortResponse, error) {
 	const op = "film.Service.AddFilmActors"
 
 	id, err := strconv.ParseUint(req.ID, 10, 32)
@@ -212,18 +411,35 @@ func (s *Service) AddFilmActors(ctx context.Context, req *FilmActorsRequest) ([]
 		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
 	}
 
-	if req.ActorIDs == nil || len(req.ActorIDs) == 0 {
+	if len(req.Credits) == 0 {
 		log.Printf("ERROR: empty update\n")
 		return nil, fmt.Errorf("%s: %w", op, ErrEmptyUpdate)
 	}
 
-	fa := &FilmActors{
-		ID:       int(id),
-		ActorIDs: ToActorIDs(tools.RemoveDuplicateInt(req.ActorIDs)),
+	vErr := ValidateCastCredits(req.Credits)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	fc := &FilmCast{
+		ID:      int(id),
+		Credits: ToCastCredits(req.Credits),
 	}
-	err = s.repo.AddFilmActors(ctx, fa)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// trashed films are not credited
+		if err := s.checkVersion(ctx, int(id), ""); err != nil {
+			return err
+		}
+
+		if err := s.repo.AddFilmActors(ctx, fc); err != nil {
+			log.Printf("ERROR: failed to bind provided actors and film\n")
+			return err
+		}
+
+		return s.record(ctx, int(id), history.ActionCredits)
+	})
 	if err != nil {
-		log.Printf("ERROR: failed to bind provided actors and film\n")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -256,9 +472,15 @@ func (s *Service) DeleteFilmActors(ctx context.Context, req *FilmActorsRequest)
 		ID:       int(id),
 		ActorIDs: ToActorIDs(tools.RemoveDuplicateInt(req.ActorIDs)),
 	}
-	err = s.repo.DeleteFilmActors(ctx, fa)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteFilmActors(ctx, fa); err != nil {
+			log.Printf("ERROR: failed to bind provided actors and film\n")
+			return err
+		}
+
+		return s.record(ctx, int(id), history.ActionCredits)
+	})
 	if err != nil {
-		log.Printf("ERROR: failed to bind provided actors and film\n")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -272,3 +494,443 @@ func (s *Service) DeleteFilmActors(ctx context.Context, req *FilmActorsRequest)
 
 	return res, nil
 }
+
+func (s *Service) GetFilmGenres(ctx context.Context, req *FilmIdRequest) ([]*GenreShortResponse, error) {
+	const op = "film.Service.GetFilmGenres"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	genres, err := s.repo.GetFilmGenres(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film genres from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(genres) == 0 {
+		log.Printf("ERROR: no genres found")
+		return nil, fmt.Errorf("%s: %w", op, ErrZeroGenres)
+	}
+
+	res := ToGenresShortResponse(genres)
+
+	return res, nil
+}
+
+func (s *Service) AddFilmGenres(ctx context.Context, req *FilmGenresRequest) ([]*GenreShortResponse, error) {
+	const op = "film.Service.AddFilmGenres"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	if len(req.GenreIDs) == 0 {
+		log.Printf("ERROR: empty update\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyUpdate)
+	}
+
+	fg := &FilmGenres{
+		ID:       int(id),
+		GenreIDs: tools.RemoveDuplicateInt(req.GenreIDs),
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// trashed films are not credited
+		if err := s.checkVersion(ctx, int(id), ""); err != nil {
+			return err
+		}
+
+		if err := s.repo.AddFilmGenres(ctx, fg); err != nil {
+			log.Printf("ERROR: failed to bind provided genres and film\n")
+			return err
+		}
+
+		return s.record(ctx, int(id), history.ActionUpdate)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	genres, err := s.repo.GetFilmGenres(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film genres from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToGenresShortResponse(genres)
+
+	return res, nil
+}
+
+func (s *Service) DeleteFilmGenres(ctx context.Context, req *FilmGenresRequest) ([]*GenreShortResponse, error) {
+	const op = "film.Service.DeleteFilmGenres"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	if len(req.GenreIDs) == 0 {
+		log.Printf("ERROR: empty update\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyUpdate)
+	}
+
+	fg := &FilmGenres{
+		ID:       int(id),
+		GenreIDs: tools.RemoveDuplicateInt(req.GenreIDs),
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteFilmGenres(ctx, fg); err != nil {
+			log.Printf("ERROR: failed to unbind provided genres and film\n")
+			return err
+		}
+
+		return s.record(ctx, int(id), history.ActionUpdate)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	genres, err := s.repo.GetFilmGenres(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film genres from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToGenresShortResponse(genres)
+
+	return res, nil
+}
+
+func (s *Service) GetFilmCrew(ctx context.Context, req *FilmIdRequest) ([]*CrewMemberResponse, error) {
+	const op = "film.Service.GetFilmCrew"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	crew, err := s.repo.GetFilmCrew(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film crew from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(crew) == 0 {
+		log.Printf("ERROR: no crew found")
+		return nil, fmt.Errorf("%s: %w", op, ErrZeroCrew)
+	}
+
+	res := ToCrewResponse(crew)
+
+	return res, nil
+}
+
+func (s *Service) AddFilmCrew(ctx context.Context, req *FilmCrewRequest) ([]*CrewMemberResponse, error) {
+	const op = "film.Service.AddFilmCrew"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	if len(req.Credits) == 0 {
+		log.Printf("ERROR: empty update\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyUpdate)
+	}
+
+	vErr := ValidateCrewCredits(req.Credits)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	fc := &FilmCrew{
+		ID:      int(id),
+		Credits: ToCrewCredits(req.Credits),
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// trashed films are not credited
+		if err := s.checkVersion(ctx, int(id), ""); err != nil {
+			return err
+		}
+
+		if err := s.repo.AddFilmCrew(ctx, fc); err != nil {
+			log.Printf("ERROR: failed to credit provided people in film\n")
+			return err
+		}
+
+		return s.record(ctx, int(id), history.ActionCredits)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	crew, err := s.repo.GetFilmCrew(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film crew from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToCrewResponse(crew)
+
+	return res, nil
+}
+
+func (s *Service) DeleteFilmCrew(ctx context.Context, req *FilmCrewRequest) ([]*CrewMemberResponse, error) {
+	const op = "film.Service.DeleteFilmCrew"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	if len(req.Credits) == 0 {
+		log.Printf("ERROR: empty update\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyUpdate)
+	}
+
+	vErr := ValidateCrewCredits(req.Credits)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	fc := &FilmCrew{
+		ID:      int(id),
+		Credits: ToCrewCredits(req.Credits),
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteFilmCrew(ctx, fc); err != nil {
+			log.Printf("ERROR: failed to remove provided crew credits of film\n")
+			return err
+		}
+
+		return s.record(ctx, int(id), history.ActionCredits)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	crew, err := s.repo.GetFilmCrew(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film crew from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToCrewResponse(crew)
+
+	return res, nil
+}
+
+func (s *Service) SetUserRating(ctx context.Context, req *UserRatingRequest) (*UserRatingResponse, error) {
+	const op = "film.Service.SetUserRating"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateUserRating(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	ur := &UserRating{
+		UserID: req.UserID,
+		FilmID: int(id),
+		Rating: req.Rating,
+	}
+	if err := s.repo.SetUserRating(ctx, ur); err != nil {
+		log.Printf("ERROR: failed to set user rating of film\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rs, err := s.repo.GetRatingStats(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film rating stats from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := &UserRatingResponse{
+		Rating:     ur.Rating,
+		UserRating: ToRatingStatsResponse(rs),
+	}
+
+	return res, nil
+}
+
+func (s *Service) DeleteUserRating(ctx context.Context, req *UserRatingRequest) (*UserRatingResponse, error) {
+	const op = "film.Service.DeleteUserRating"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	ur := &UserRating{
+		UserID: req.UserID,
+		FilmID: int(id),
+	}
+	err = s.repo.DeleteUserRating(ctx, ur)
+	if err != nil {
+		log.Printf("ERROR: failed to delete user rating of film\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rs, err := s.repo.GetRatingStats(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film rating stats from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := &UserRatingResponse{
+		UserRating: ToRatingStatsResponse(rs),
+	}
+
+	return res, nil
+}
+
+func (s *Service) GetFilmRelations(ctx context.Context, req *FilmIdRequest) ([]*RelatedFilmResponse, error) {
+	const op = "film.Service.GetFilmRelations"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	related, err := s.repo.GetFilmRelations(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film relations from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(related) == 0 {
+		log.Printf("ERROR: no relations found")
+		return nil, fmt.Errorf("%s: %w", op, ErrZeroRelations)
+	}
+
+	res := ToRelatedFilmsResponse(related)
+
+	return res, nil
+}
+
+func (s *Service) AddFilmRelations(ctx context.Context, req *FilmRelationsRequest) ([]*RelatedFilmResponse, error) {
+	const op = "film.Service.AddFilmRelations"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	if len(req.Relations) == 0 {
+		log.Printf("ERROR: empty update\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyUpdate)
+	}
+
+	vErr := ValidateRelations(int(id), req.Relations)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	fr := &FilmRelations{
+		ID:        int(id),
+		Relations: ToRelations(req.Relations),
+	}
+	err = s.repo.AddFilmRelations(ctx, fr)
+	if err != nil {
+		log.Printf("ERROR: failed to relate provided films to film\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	related, err := s.repo.GetFilmRelations(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film relations from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToRelatedFilmsResponse(related)
+
+	return res, nil
+}
+
+func (s *Service) DeleteFilmRelations(ctx context.Context, req *FilmRelationsRequest) ([]*RelatedFilmResponse, error) {
+	const op = "film.Service.DeleteFilmRelations"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	if len(req.Relations) == 0 {
+		log.Printf("ERROR: empty update\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyUpdate)
+	}
+
+	vErr := ValidateRelations(int(id), req.Relations)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	fr := &FilmRelations{
+		ID:        int(id),
+		Relations: ToRelations(req.Relations),
+	}
+	err = s.repo.DeleteFilmRelations(ctx, fr)
+	if err != nil {
+		log.Printf("ERROR: failed to remove provided relations of film\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	related, err := s.repo.GetFilmRelations(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get film relations from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToRelatedFilmsResponse(related)
+
+	return res, nil
+}
+
+// checkVersion locks the film for the rest of the transaction and fails
+// unless its version matches the If-Match header, any version matching an
+// empty header.
+func (s *Service) checkVersion(ctx context.Context, id int, ifMatch string) error {
+	version, err := s.repo.GetFilmVersion(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to get film version from repository\n")
+		return err
+	}
+
+	if ifMatch != "" && !tools.MatchVersion(ifMatch, version) {
+		log.Printf("ERROR: film version %d does not match %s\n", version, ifMatch)
+		return ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// record keeps a version of the film after a change, it is meant to run
+// in the transaction of the change so that both are kept or neither is.
+func (s *Service) record(ctx context.Context, id int, action string) error {
+	if err := s.history.Record(ctx, history.EntityFilm, id, action); err != nil {
+		log.Printf("ERROR: failed to record film history\n")
+		return err
+	}
+
+	return nil
+}
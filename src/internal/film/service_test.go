@@ -0,0 +1,276 @@
+package film
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"film-library/src/internal/tools"
+)
+
+var (
+	errInjected     = errors.New("injected failure")
+	errCommitFailed = errors.New("commit failed")
+)
+
+// fakeStore holds the films and history rows of a fake database, writes made
+// within fakeTx are kept only when the transaction commits.
+type fakeStore struct {
+	films      map[int]*Film
+	history    []int
+	nextID     int
+	failCommit bool
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		films: make(map[int]*Film),
+	}
+}
+
+type fakeTxKey struct{}
+
+type fakeTxWrites struct {
+	films   []*Film
+	history []int
+}
+
+type fakeTx struct {
+	store *fakeStore
+}
+
+func (tx *fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(fakeTxKey{}).(*fakeTxWrites); ok {
+		return fn(ctx)
+	}
+
+	writes := &fakeTxWrites{}
+	if err := fn(context.WithValue(ctx, fakeTxKey{}, writes)); err != nil {
+		return err
+	}
+	if tx.store.failCommit {
+		return errCommitFailed
+	}
+
+	for _, f := range writes.films {
+		tx.store.films[f.ID] = f
+	}
+	tx.store.history = append(tx.store.history, writes.history...)
+
+	return nil
+}
+
+// fakeRepository stores films in fakeStore and fails the step named by
+// failOn, methods not used by the tests are left to the nil interface.
+type fakeRepository struct {
+	FilmRepository
+	store   *fakeStore
+	failOn  string
+	version int
+	calls   []string
+}
+
+// called notes a call of the repository, marking those made within fakeTx.
+func (r *fakeRepository) called(ctx context.Context, method string) {
+	if _, ok := ctx.Value(fakeTxKey{}).(*fakeTxWrites); ok {
+		method += " in tx"
+	}
+	r.calls = append(r.calls, method)
+}
+
+func (r *fakeRepository) AddFilm(ctx context.Context, f *Film) (*Film, error) {
+	if r.failOn == "AddFilm" {
+		return nil, errInjected
+	}
+
+	r.store.nextID++
+	added := *f
+	added.ID = r.store.nextID
+
+	if writes, ok := ctx.Value(fakeTxKey{}).(*fakeTxWrites); ok {
+		writes.films = append(writes.films, &added)
+	} else {
+		r.store.films[added.ID] = &added
+	}
+
+	return &added, nil
+}
+
+func (r *fakeRepository) AddFilmActors(ctx context.Context, fc *FilmCast) error {
+	if r.failOn == "AddFilmActors" {
+		return errInjected
+	}
+
+	return nil
+}
+
+func (r *fakeRepository) GetFilm(ctx context.Context, id int) (*Film, error) {
+	r.called(ctx, "GetFilm")
+
+	f, ok := r.store.films[id]
+	if !ok {
+		return nil, ErrFilmNotExist
+	}
+
+	return f, nil
+}
+
+func (r *fakeRepository) GetFilmVersion(ctx context.Context, id int) (int, error) {
+	r.called(ctx, "GetFilmVersion")
+
+	if _, ok := r.store.films[id]; !ok {
+		return 0, ErrFilmNotExist
+	}
+
+	return r.version, nil
+}
+
+func (r *fakeRepository) UpdateFilm(ctx context.Context, f *Film) error {
+	r.called(ctx, "UpdateFilm")
+
+	r.store.films[f.ID] = f
+
+	return nil
+}
+
+type fakeRecorder struct {
+	store *fakeStore
+	fail  bool
+}
+
+func (r *fakeRecorder) Record(ctx context.Context, entity string, id int, action string) error {
+	if r.fail {
+		return errInjected
+	}
+
+	if writes, ok := ctx.Value(fakeTxKey{}).(*fakeTxWrites); ok {
+		writes.history = append(writes.history, id)
+	} else {
+		r.store.history = append(r.store.history, id)
+	}
+
+	return nil
+}
+
+func newAddFilmRequest() *AddFilmRequest {
+	return &AddFilmRequest{
+		Info: FilmInfo{
+			Name:        "Solaris",
+			Description: "A psychologist is sent to a station orbiting a distant planet.",
+			ReleaseDate: "1972-03-20",
+			Rating:      8,
+		},
+		ActorIDs: []int{1, 2},
+	}
+}
+
+func TestAddFilmFailureLeavesNothing(t *testing.T) {
+	tests := []struct {
+		name       string
+		failOn     string
+		failRecord bool
+		failCommit bool
+		wantErr    error
+	}{
+		{"adding actors fails", "AddFilmActors", false, false, errInjected},
+		{"recording history fails", "", true, false, errInjected},
+		{"commit fails", "", false, true, errCommitFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeStore()
+			store.failCommit = tt.failCommit
+			s := NewService(
+				&fakeRepository{store: store, failOn: tt.failOn},
+				&fakeTx{store: store},
+				&fakeRecorder{store: store, fail: tt.failRecord},
+			)
+
+			_, err := s.AddFilm(context.Background(), newAddFilmRequest())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddFilm() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(store.films) != 0 {
+				t.Errorf("films = %v, want no film left after the failure", store.films)
+			}
+			if len(store.history) != 0 {
+				t.Errorf("history = %v, want no history left after the failure", store.history)
+			}
+		})
+	}
+}
+
+func TestAddFilmCommitsFilmWithHistory(t *testing.T) {
+	store := newFakeStore()
+	s := NewService(&fakeRepository{store: store}, &fakeTx{store: store}, &fakeRecorder{store: store})
+
+	res, err := s.AddFilm(context.Background(), newAddFilmRequest())
+	if err != nil {
+		t.Fatalf("AddFilm() error = %v", err)
+	}
+
+	if _, ok := store.films[res.ID]; !ok || len(store.films) != 1 {
+		t.Errorf("films = %v, want film %d", store.films, res.ID)
+	}
+	if len(store.history) != 1 || store.history[0] != res.ID {
+		t.Errorf("history = %v, want a single version of film %d", store.history, res.ID)
+	}
+}
+
+func newPatchFilmService(version int) (*Service, *fakeRepository) {
+	store := newFakeStore()
+	store.films[1] = &Film{
+		ID:          1,
+		Name:        "Solaris",
+		Description: "A psychologist is sent to a station orbiting a distant planet.",
+		ReleaseDate: time.Date(1972, 3, 20, 0, 0, 0, 0, time.UTC),
+		Rating:      8,
+	}
+	repo := &fakeRepository{store: store, version: version}
+
+	return NewService(repo, &fakeTx{store: store}, &fakeRecorder{store: store}), repo
+}
+
+func TestPatchFilmLocksWithoutIfMatch(t *testing.T) {
+	s, repo := newPatchFilmService(2)
+
+	_, err := s.PatchFilm(context.Background(), &FilmPatchRequest{
+		ID:        "1",
+		MediaType: tools.MediaTypeMergePatch,
+		Patch:     []byte(`{"rating": 9}`),
+	})
+	if err != nil {
+		t.Fatalf("PatchFilm() error = %v", err)
+	}
+
+	// the film is locked before it is read, so that concurrent patches of
+	// other fields are applied one after another
+	want := []string{"GetFilmVersion in tx", "GetFilm in tx", "UpdateFilm in tx"}
+	if len(repo.calls) < len(want) || !slices.Equal(repo.calls[:len(want)], want) {
+		t.Fatalf("calls = %v, want %v first", repo.calls, want)
+	}
+	if got := repo.store.films[1].Rating; got != 9 {
+		t.Errorf("rating = %d, want 9", got)
+	}
+}
+
+func TestPatchFilmVersionMismatch(t *testing.T) {
+	s, repo := newPatchFilmService(2)
+
+	_, err := s.PatchFilm(context.Background(), &FilmPatchRequest{
+		ID:        "1",
+		MediaType: tools.MediaTypeMergePatch,
+		Patch:     []byte(`{"rating": 9}`),
+		IfMatch:   tools.ETag(1),
+	})
+	if !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("PatchFilm() error = %v, want %v", err, ErrVersionMismatch)
+	}
+	if slices.Contains(repo.calls, "UpdateFilm in tx") {
+		t.Errorf("calls = %v, want no update", repo.calls)
+	}
+}
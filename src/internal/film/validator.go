@@ -2,18 +2,125 @@ package film
 
 import (
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"film-library/src/internal/tools"
 )
 
-var validSortQuery = regexp.MustCompile("^(name|rating|releasedate),(asc|desc)$")
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 25
+	minUserRating       = 1
+	maxUserRating       = 10
+)
+
+var crewRoles = map[string]struct{}{
+	"director":        {},
+	"writer":          {},
+	"producer":        {},
+	"composer":        {},
+	"cinematographer": {},
+}
+
+var relationTypes = map[string]struct{}{
+	"sequel_of":   {},
+	"remake_of":   {},
+	"spin_off_of": {},
+}
+
+var validSortQuery = regexp.MustCompile("^(name|rating|releasedate|userRating),(asc|desc)$")
 
 func ValidateGetFilmsRequest(req *GetFilmsRequest) *tools.ValidationError {
 	ve := &tools.ValidationError{}
 
 	if len(req.SortQuery) != 0 && !validSortQuery.MatchString(req.SortQuery) {
-		ve.AddViolation("incorrect sort query, expect value of pattern: '^(name|rating|releasedate),(asc|desc)$'")
+		ve.AddViolation("incorrect sort query, expect value of pattern: '^(name|rating|releasedate|userRating),(asc|desc)$'")
+	}
+
+	var ratingMin, ratingMax int
+	for _, v := range []struct {
+		query string
+		value *int
+	}{
+		{req.RatingMinQuery, &ratingMin},
+		{req.RatingMaxQuery, &ratingMax},
+	} {
+		if len(v.query) == 0 {
+			continue
+		}
+
+		n, err := strconv.Atoi(v.query)
+		if err != nil || n < 0 || n > 10 {
+			ve.AddViolation("incorrect rating bound, expected: 0 <= rating <= 10")
+			continue
+		}
+		*v.value = n
+	}
+	if len(req.RatingMinQuery) != 0 && len(req.RatingMaxQuery) != 0 && ratingMin > ratingMax {
+		ve.AddViolation("ratingMin is greater than ratingMax")
+	}
+
+	var releasedAfter, releasedBefore time.Time
+	for _, v := range []struct {
+		query string
+		value *time.Time
+	}{
+		{req.ReleasedAfterQuery, &releasedAfter},
+		{req.ReleasedBeforeQuery, &releasedBefore},
+	} {
+		if len(v.query) == 0 {
+			continue
+		}
+
+		t, err := time.Parse("2006-01-02", v.query)
+		if err != nil {
+			ve.AddViolation("incorrect release date bound format (expected format: 2006-01-02)")
+			continue
+		}
+		*v.value = t
+	}
+	if !releasedAfter.IsZero() && !releasedBefore.IsZero() && releasedAfter.After(releasedBefore) {
+		ve.AddViolation("releasedAfter is later than releasedBefore")
+	}
+
+	for _, v := range req.ActorIDQuery {
+		if id, err := strconv.Atoi(v); err != nil || id <= 0 {
+			ve.AddViolation("incorrect actorId, expected positive integer")
+			break
+		}
+	}
+
+	for _, v := range req.GenreQuery {
+		if len(strings.TrimSpace(v)) == 0 || len(v) > 50 {
+			ve.AddViolation("incorrect genre, expected non-empty name of at most 50 symbols")
+			break
+		}
+	}
+
+	if len(req.ActorMatchQuery) != 0 && req.ActorMatchQuery != "any" && req.ActorMatchQuery != "all" {
+		ve.AddViolation("incorrect actorMatch query, expected one of [any, all]")
+	}
+
+	if len(req.CaseSensitiveQuery) != 0 && req.CaseSensitiveQuery != "true" && req.CaseSensitiveQuery != "false" {
+		ve.AddViolation("incorrect caseSensitive query, expected one of [true, false]")
+	}
+
+	if len(req.FuzzyQuery) != 0 && req.FuzzyQuery != "true" && req.FuzzyQuery != "false" {
+		ve.AddViolation("incorrect fuzzy query, expected one of [true, false]")
+	}
+
+	tools.ValidatePageQuery(ve, req.LimitQuery, req.AfterQuery, req.BeforeQuery, req.TotalQuery)
+
+	sort := req.SortQuery
+	if len(sort) == 0 {
+		sort = strings.Join(defaultSort, ",")
+	}
+	for _, v := range []string{req.AfterQuery, req.BeforeQuery} {
+		if c, err := tools.DecodeCursor(v); err == nil && c.Sort != sort {
+			ve.AddViolation("cursor does not match sort query")
+		}
 	}
 
 	if ve.NoViolations() {
@@ -23,7 +130,32 @@ func ValidateGetFilmsRequest(req *GetFilmsRequest) *tools.ValidationError {
 	return ve
 }
 
-func ValidateFormatFilmInfo(fi *FilmInfo, allowNegativeRating bool) *tools.ValidationError {
+func ValidateSuggestRequest(req *SuggestRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(strings.TrimSpace(req.TextQuery)) == 0 {
+		ve.AddViolation("suggest query empty")
+	}
+
+	if len(req.TextQuery) > 150 {
+		ve.AddViolation("suggest query length is more than 150 symbols")
+	}
+
+	if len(req.LimitQuery) != 0 {
+		n, err := strconv.Atoi(req.LimitQuery)
+		if err != nil || n < 1 || n > maxSuggestLimit {
+			ve.AddViolation("incorrect limit, expected: 1 <= limit <= " + strconv.Itoa(maxSuggestLimit))
+		}
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateFormatFilmInfo(fi *FilmInfo) *tools.ValidationError {
 	ve := &tools.ValidationError{}
 
 	if len(fi.Name) > 150 {
@@ -38,7 +170,7 @@ func ValidateFormatFilmInfo(fi *FilmInfo, allowNegativeRating bool) *tools.Valid
 		ve.AddViolation("incorrect date format (expected format: 2006-01-02)")
 	}
 
-	if (fi.Rating < 0 && !allowNegativeRating) || fi.Rating > 10 {
+	if fi.Rating < 0 || fi.Rating > 10 {
 		ve.AddViolation("incorrect rating, expected: 0 <= rating <= 10")
 	}
 
@@ -66,3 +198,135 @@ func ValidateEmptyFilmInfo(fi *FilmInfo) *tools.ValidationError {
 
 	return ve
 }
+
+func ValidateCrewCredits(c []*CrewCreditRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	for _, v := range c {
+		if v == nil || v.PersonID <= 0 {
+			ve.AddViolation("incorrect personId, expected positive integer")
+			break
+		}
+	}
+
+	for _, v := range c {
+		if v == nil {
+			continue
+		}
+		if _, ok := crewRoles[v.Role]; !ok {
+			ve.AddViolation("incorrect role (expected one of [director, writer, producer, composer, cinematographer])")
+			break
+		}
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateCastCredits(c []*CastCreditRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	seen := make(map[int]bool)
+	for _, v := range c {
+		if v == nil || v.ActorID <= 0 {
+			ve.AddViolation("incorrect actorId, expected positive integer")
+			break
+		}
+
+		if seen[v.ActorID] {
+			ve.AddViolation("actor credited more than once")
+			break
+		}
+		seen[v.ActorID] = true
+	}
+
+	for _, v := range c {
+		if v == nil {
+			continue
+		}
+
+		if v.Billing < 0 {
+			ve.AddViolation("incorrect billing, expected positive integer or 0 for not billed")
+			break
+		}
+	}
+
+	for _, v := range c {
+		if v == nil {
+			continue
+		}
+
+		if len(v.Characters) > 10 {
+			ve.AddViolation("more than 10 characters in a single credit")
+			break
+		}
+
+		invalid := false
+		for _, ch := range v.Characters {
+			if len(strings.TrimSpace(ch)) == 0 || len(ch) > 150 {
+				invalid = true
+			}
+		}
+		if invalid {
+			ve.AddViolation("incorrect character name, expected non-empty name of at most 150 symbols")
+			break
+		}
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateUserRating(req *UserRatingRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if req.Rating < minUserRating || req.Rating > maxUserRating {
+		ve.AddViolation("incorrect rating, expected: " + strconv.Itoa(minUserRating) + " <= rating <= " + strconv.Itoa(maxUserRating))
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateRelations(id int, rel []*RelationRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	for _, v := range rel {
+		if v == nil || v.FilmID <= 0 {
+			ve.AddViolation("incorrect filmId, expected positive integer")
+			break
+		}
+	}
+
+	for _, v := range rel {
+		if v != nil && v.FilmID == id {
+			ve.AddViolation("film can not be related to itself")
+			break
+		}
+	}
+
+	for _, v := range rel {
+		if v == nil {
+			continue
+		}
+		if _, ok := relationTypes[v.Type]; !ok {
+			ve.AddViolation("incorrect type (expected one of [sequel_of, remake_of, spin_off_of])")
+			break
+		}
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
@@ -0,0 +1,40 @@
+package franchise
+
+import (
+	"strings"
+	"time"
+)
+
+func ToFranchiseResponse(f *Franchise, films []*Film) *FranchiseResponse {
+	res := &FranchiseResponse{
+		ID: f.ID,
+		Info: FranchiseInfo{
+			Name:        f.Name,
+			Description: f.Description,
+		},
+		FilmCount: f.FilmCount,
+	}
+
+	if films != nil {
+		res.FilmCount = len(films)
+		res.Films = make([]*FilmResponse, 0, len(films))
+		for _, v := range films {
+			res.Films = append(res.Films, &FilmResponse{
+				ID:                 v.ID,
+				Name:               v.Name,
+				ReleaseDate:        v.ReleaseDate.Format(time.DateOnly),
+				ReleaseOrder:       v.ReleaseOrder,
+				ChronologicalOrder: v.ChronologicalOrder,
+			})
+		}
+	}
+
+	return res
+}
+
+func ToFranchise(fi *FranchiseInfo) *Franchise {
+	return &Franchise{
+		Name:        strings.TrimSpace(fi.Name),
+		Description: strings.TrimSpace(fi.Description),
+	}
+}
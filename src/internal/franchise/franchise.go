@@ -0,0 +1,107 @@
+package franchise
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	OrderRelease       = "release"
+	OrderChronological = "chronological"
+)
+
+type Franchise struct {
+	ID          int
+	Name        string
+	Description string
+	FilmCount   int
+}
+
+// Film is a film of a franchise, ChronologicalOrder of zero means its
+// place in the in-universe timeline is not configured.
+type Film struct {
+	ID                 int
+	Name               string
+	ReleaseDate        time.Time
+	ReleaseOrder       int
+	ChronologicalOrder int
+}
+
+type FranchiseRepository interface {
+	Get(ctx context.Context, id int) (*Franchise, error)
+	GetAll(ctx context.Context) ([]*Franchise, error)
+	Add(ctx context.Context, f *Franchise) (*Franchise, error)
+	Update(ctx context.Context, f *Franchise) error
+	Delete(ctx context.Context, id int) error
+	GetFilms(ctx context.Context, id int, order string) ([]*Film, error)
+	PutFilm(ctx context.Context, id int, f *Film) error
+	DeleteFilm(ctx context.Context, id, filmID int) error
+}
+
+type FranchiseService interface {
+	GetAll(ctx context.Context) ([]*FranchiseResponse, error)
+	Add(ctx context.Context, req *FranchiseInfo) (*FranchiseResponse, error)
+	Get(ctx context.Context, req *GetFranchiseRequest) (*FranchiseResponse, error)
+	Update(ctx context.Context, req *FranchiseIdInfoRequest) (*FranchiseResponse, error)
+	Delete(ctx context.Context, req *FranchiseIdRequest) (*FranchiseResponse, error)
+	PutFilm(ctx context.Context, req *FranchiseFilmRequest) (*FranchiseResponse, error)
+	DeleteFilm(ctx context.Context, req *FranchiseFilmRequest) (*FranchiseResponse, error)
+}
+
+type FranchiseHandler interface {
+	GetAll(w http.ResponseWriter, r *http.Request)
+	Add(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	PutFilm(w http.ResponseWriter, r *http.Request)
+	DeleteFilm(w http.ResponseWriter, r *http.Request)
+}
+
+type FranchiseInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type FilmOrderInfo struct {
+	ReleaseOrder       int `json:"releaseOrder"`
+	ChronologicalOrder int `json:"chronologicalOrder"`
+}
+
+type FilmResponse struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	ReleaseDate        string `json:"releasedate"`
+	ReleaseOrder       int    `json:"releaseOrder"`
+	ChronologicalOrder int    `json:"chronologicalOrder,omitempty"`
+}
+
+// FranchiseResponse carries Films only when a single franchise is
+// requested.
+type FranchiseResponse struct {
+	ID        int             `json:"id"`
+	Info      FranchiseInfo   `json:"info"`
+	FilmCount int             `json:"filmCount"`
+	Films     []*FilmResponse `json:"films,omitempty"`
+}
+
+type FranchiseIdRequest struct {
+	ID string
+}
+
+type GetFranchiseRequest struct {
+	ID         string
+	OrderQuery string
+}
+
+type FranchiseIdInfoRequest struct {
+	ID   string
+	Info FranchiseInfo
+}
+
+type FranchiseFilmRequest struct {
+	ID     string
+	FilmID string
+	Info   FilmOrderInfo
+}
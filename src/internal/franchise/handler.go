@@ -0,0 +1,223 @@
+package franchise
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+var _ FranchiseHandler = (*Handler)(nil)
+
+type Handler struct {
+	service FranchiseService
+}
+
+func NewHandler(fs FranchiseService) *Handler {
+	return &Handler{
+		service: fs,
+	}
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetAll(r.Context())
+	if err != nil {
+		log.Printf("ERROR: failed to get franchises err=%s\n", err.Error())
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	var req FranchiseInfo
+	if ok := tools.BindJSON(w, r, &req); !ok {
+		return
+	}
+
+	res, err := h.service.Add(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to add franchise err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrFranchiseExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "franchise with given name already exists",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	req := GetFranchiseRequest{
+		ID:         r.PathValue("id"),
+		OrderQuery: r.URL.Query().Get("order"),
+	}
+
+	res, err := h.service.Get(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get franchise err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFranchiseNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	req := FranchiseIdInfoRequest{
+		ID: r.PathValue("id"),
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.Update(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to update franchise err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFranchiseNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrFranchiseExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "franchise with given name already exists",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	req := FranchiseIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.Delete(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete franchise err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFranchiseNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) PutFilm(w http.ResponseWriter, r *http.Request) {
+	req := FranchiseFilmRequest{
+		ID:     r.PathValue("id"),
+		FilmID: r.PathValue("filmId"),
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.PutFilm(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to put film into franchise err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFranchiseNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrFilmNotExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "film with given id does not exist",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
+	req := FranchiseFilmRequest{
+		ID:     r.PathValue("id"),
+		FilmID: r.PathValue("filmId"),
+	}
+
+	res, err := h.service.DeleteFilm(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to remove film from franchise err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFranchiseNotExist) || errors.Is(err, ErrEntryNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
@@ -0,0 +1,327 @@
+package franchise
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"film-library/src/internal/db"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrFranchiseNotExist = errors.New("franchise does not exist")
+	ErrFranchiseExist    = errors.New("franchise already exists")
+	ErrFilmNotExist      = errors.New("film with given id does not exist")
+	ErrEntryNotExist     = errors.New("film is not in the franchise")
+)
+
+// filmCountColumn counts films of franchise f.
+const filmCountColumn = `
+	(SELECT COUNT(*) FROM franchise_movie fm WHERE fm.franchise_id = f.franchise_id) film_count`
+
+// filmOrders maps order of franchise films to the ORDER BY clause, films
+// without chronological position come last in chronological order.
+var filmOrders = map[string]string{
+	OrderRelease:       "fm.release_order, m.releasedate, m.movie_id",
+	OrderChronological: "fm.chronological_order NULLS LAST, fm.release_order, m.movie_id",
+}
+
+var _ FranchiseRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) Get(ctx context.Context, id int) (*Franchise, error) {
+	const op = "franchise.Repository.Get"
+
+	const query = `
+		SELECT f.franchise_id, f.franchise_name, f.franchise_description, ` + filmCountColumn + `
+		FROM franchise f
+		WHERE f.franchise_id = $1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var f Franchise
+	err = stmt.QueryRowContext(ctx, id).Scan(&f.ID, &f.Name, &f.Description, &f.FilmCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: franchise with id=%d does not exist\n", id)
+			return nil, fmt.Errorf("%s: %w", op, ErrFranchiseNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &f, nil
+}
+
+func (r *Repository) GetAll(ctx context.Context) ([]*Franchise, error) {
+	const op = "franchise.Repository.GetAll"
+
+	const query = `
+		SELECT f.franchise_id, f.franchise_name, f.franchise_description, ` + filmCountColumn + `
+		FROM franchise f
+		ORDER BY f.franchise_name`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var franchises []*Franchise
+	for rows.Next() {
+		var f Franchise
+		err := rows.Scan(&f.ID, &f.Name, &f.Description, &f.FilmCount)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		franchises = append(franchises, &f)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return franchises, nil
+}
+
+func (r *Repository) Add(ctx context.Context, f *Franchise) (*Franchise, error) {
+	const op = "franchise.Repository.Add"
+
+	const query = `
+		INSERT INTO franchise(franchise_name, franchise_description)
+		VALUES ($1, $2) RETURNING franchise_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, f.Name, f.Description).Scan(&f.ID)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "unique_violation" {
+				log.Printf("ERROR: franchise %s already exists\n", f.Name)
+				return nil, fmt.Errorf("%s: %w", op, ErrFranchiseExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return f, nil
+}
+
+func (r *Repository) Update(ctx context.Context, f *Franchise) error {
+	const op = "franchise.Repository.Update"
+
+	const query = `
+		UPDATE franchise SET franchise_name = $1, franchise_description = $2
+		WHERE franchise_id = $3`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, f.Name, f.Description, f.ID)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "unique_violation" {
+				log.Printf("ERROR: franchise %s already exists\n", f.Name)
+				return fmt.Errorf("%s: %w", op, ErrFranchiseExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by update\n")
+		return fmt.Errorf("%s: %w", op, ErrFranchiseNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	const op = "franchise.Repository.Delete"
+
+	const query = `DELETE FROM franchise WHERE franchise_id = $1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by deletion\n")
+		return fmt.Errorf("%s: %w", op, ErrFranchiseNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetFilms(ctx context.Context, id int, order string) ([]*Film, error) {
+	const op = "franchise.Repository.GetFilms"
+
+	query := `
+		SELECT m.movie_id, m.movie_name, m.releasedate, fm.release_order, COALESCE(fm.chronological_order, 0)
+		FROM franchise_movie fm
+		INNER JOIN movie m USING (movie_id)
+		WHERE fm.franchise_id = $1 AND m.deleted_at IS NULL
+		ORDER BY ` + filmOrders[order]
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	films := make([]*Film, 0)
+	for rows.Next() {
+		var f Film
+		err := rows.Scan(&f.ID, &f.Name, &f.ReleaseDate, &f.ReleaseOrder, &f.ChronologicalOrder)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		films = append(films, &f)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return films, nil
+}
+
+// PutFilm adds the film to the franchise or replaces its positions when
+// it is already there.
+func (r *Repository) PutFilm(ctx context.Context, id int, f *Film) error {
+	const op = "franchise.Repository.PutFilm"
+
+	const query = `
+		INSERT INTO franchise_movie(franchise_id, movie_id, release_order, chronological_order)
+		SELECT $1, movie_id, $3, NULLIF($4, 0) FROM movie WHERE movie_id = $2 AND deleted_at IS NULL
+		ON CONFLICT (franchise_id, movie_id) DO UPDATE
+		SET release_order = EXCLUDED.release_order, chronological_order = EXCLUDED.chronological_order`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id, f.ID, f.ReleaseOrder, f.ChronologicalOrder)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "foreign_key_violation" {
+				log.Printf("ERROR: franchise with id=%d does not exist\n", id)
+				return fmt.Errorf("%s: %w", op, ErrFranchiseNotExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: film with id=%d does not exist\n", f.ID)
+		return fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteFilm(ctx context.Context, id, filmID int) error {
+	const op = "franchise.Repository.DeleteFilm"
+
+	const query = `DELETE FROM franchise_movie WHERE franchise_id = $1 AND movie_id = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id, filmID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by deletion\n")
+		return fmt.Errorf("%s: %w", op, ErrEntryNotExist)
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package franchise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+var (
+	ErrIdInvalid = errors.New("invalid id")
+)
+
+var _ FranchiseService = (*Service)(nil)
+
+type Service struct {
+	repo FranchiseRepository
+}
+
+func NewService(fr FranchiseRepository) *Service {
+	return &Service{
+		repo: fr,
+	}
+}
+
+func (s *Service) GetAll(ctx context.Context) ([]*FranchiseResponse, error) {
+	const op = "franchise.Service.GetAll"
+
+	franchises, err := s.repo.GetAll(ctx)
+	if err != nil {
+		log.Printf("ERROR: failed to get franchise records from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := make([]*FranchiseResponse, 0, len(franchises))
+	for _, v := range franchises {
+		res = append(res, ToFranchiseResponse(v, nil))
+	}
+
+	return res, nil
+}
+
+func (s *Service) Add(ctx context.Context, req *FranchiseInfo) (*FranchiseResponse, error) {
+	const op = "franchise.Service.Add"
+
+	vErr := ValidateFranchiseInfo(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	franchise := ToFranchise(req)
+
+	franchise, err := s.repo.Add(ctx, franchise)
+	if err != nil {
+		log.Printf("ERROR: failed to create franchise record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToFranchiseResponse(franchise, []*Film{})
+
+	return res, nil
+}
+
+// Get returns the franchise with its films sorted in release order unless
+// chronological order is requested.
+func (s *Service) Get(ctx context.Context, req *GetFranchiseRequest) (*FranchiseResponse, error) {
+	const op = "franchise.Service.Get"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateOrderQuery(req.OrderQuery)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	order := req.OrderQuery
+	if len(order) == 0 {
+		order = OrderRelease
+	}
+
+	res, err := s.withFilms(ctx, int(id), order)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) Update(ctx context.Context, req *FranchiseIdInfoRequest) (*FranchiseResponse, error) {
+	const op = "franchise.Service.Update"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateFranchiseInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	franchise := ToFranchise(&req.Info)
+	franchise.ID = int(id)
+
+	err = s.repo.Update(ctx, franchise)
+	if err != nil {
+		log.Printf("ERROR: failed to update franchise record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.withFilms(ctx, int(id), OrderRelease)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) Delete(ctx context.Context, req *FranchiseIdRequest) (*FranchiseResponse, error) {
+	const op = "franchise.Service.Delete"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	franchise, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get franchise record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repo.Delete(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to delete franchise record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToFranchiseResponse(franchise, nil)
+
+	return res, nil
+}
+
+func (s *Service) PutFilm(ctx context.Context, req *FranchiseFilmRequest) (*FranchiseResponse, error) {
+	const op = "franchise.Service.PutFilm"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	filmID, err := strconv.ParseUint(req.FilmID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed film id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateFilmOrderInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	f := &Film{
+		ID:                 int(filmID),
+		ReleaseOrder:       req.Info.ReleaseOrder,
+		ChronologicalOrder: req.Info.ChronologicalOrder,
+	}
+	err = s.repo.PutFilm(ctx, int(id), f)
+	if err != nil {
+		log.Printf("ERROR: failed to put film into franchise\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.withFilms(ctx, int(id), OrderRelease)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) DeleteFilm(ctx context.Context, req *FranchiseFilmRequest) (*FranchiseResponse, error) {
+	const op = "franchise.Service.DeleteFilm"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	filmID, err := strconv.ParseUint(req.FilmID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed film id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	err = s.repo.DeleteFilm(ctx, int(id), int(filmID))
+	if err != nil {
+		log.Printf("ERROR: failed to remove film from franchise\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.withFilms(ctx, int(id), OrderRelease)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) withFilms(ctx context.Context, id int, order string) (*FranchiseResponse, error) {
+	franchise, err := s.repo.Get(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to get franchise record from repository\n")
+		return nil, err
+	}
+
+	films, err := s.repo.GetFilms(ctx, id, order)
+	if err != nil {
+		log.Printf("ERROR: failed to get franchise films from repository\n")
+		return nil, err
+	}
+
+	return ToFranchiseResponse(franchise, films), nil
+}
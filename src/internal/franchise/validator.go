@@ -0,0 +1,61 @@
+package franchise
+
+import (
+	"strings"
+
+	"film-library/src/internal/tools"
+)
+
+func ValidateFranchiseInfo(fi *FranchiseInfo) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(strings.TrimSpace(fi.Name)) == 0 {
+		ve.AddViolation("name empty")
+	}
+
+	if len(fi.Name) > 150 {
+		ve.AddViolation("name length is more than 150 symbols")
+	}
+
+	if len(fi.Description) > 1000 {
+		ve.AddViolation("description length is more than 1000 symbols")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateFilmOrderInfo(fi *FilmOrderInfo) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if fi.ReleaseOrder <= 0 {
+		ve.AddViolation("incorrect releaseOrder, expected positive integer")
+	}
+
+	if fi.ChronologicalOrder < 0 {
+		ve.AddViolation("incorrect chronologicalOrder, expected positive integer or 0 for not set")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateOrderQuery(order string) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if order != "" && order != OrderRelease && order != OrderChronological {
+		ve.AddViolation("incorrect order (expected one of [release, chronological])")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
@@ -0,0 +1,18 @@
+package genre
+
+import "strings"
+
+func ToGenreResponse(g *Genre) *GenreResponse {
+	return &GenreResponse{
+		ID: g.ID,
+		Info: GenreInfo{
+			Name: g.Name,
+		},
+	}
+}
+
+func ToGenre(gi *GenreInfo) *Genre {
+	return &Genre{
+		Name: strings.ToLower(strings.TrimSpace(gi.Name)),
+	}
+}
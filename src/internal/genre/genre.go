@@ -0,0 +1,53 @@
+package genre
+
+import (
+	"context"
+	"net/http"
+)
+
+type Genre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type GenreRepository interface {
+	Get(ctx context.Context, id int) (*Genre, error)
+	Add(ctx context.Context, g *Genre) (*Genre, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, g *Genre) error
+	GetAll(ctx context.Context) ([]*Genre, error)
+}
+
+type GenreService interface {
+	GetAll(ctx context.Context) ([]*GenreResponse, error)
+	Add(ctx context.Context, req *GenreInfo) (*GenreResponse, error)
+	Get(ctx context.Context, req *GenreIdRequest) (*GenreResponse, error)
+	Update(ctx context.Context, req *GenreIdInfoRequest) (*GenreResponse, error)
+	Delete(ctx context.Context, req *GenreIdRequest) (*GenreResponse, error)
+}
+
+type GenreHandler interface {
+	GetAll(w http.ResponseWriter, r *http.Request)
+	Add(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+}
+
+type GenreInfo struct {
+	Name string `json:"name"`
+}
+
+type GenreResponse struct {
+	ID   int       `json:"id"`
+	Info GenreInfo `json:"info"`
+}
+
+type GenreIdRequest struct {
+	ID string
+}
+
+type GenreIdInfoRequest struct {
+	ID   string
+	Info GenreInfo
+}
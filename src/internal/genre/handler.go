@@ -0,0 +1,147 @@
+package genre
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+var _ GenreHandler = (*Handler)(nil)
+
+type Handler struct {
+	service GenreService
+}
+
+func NewHandler(gs GenreService) *Handler {
+	return &Handler{
+		service: gs,
+	}
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetAll(r.Context())
+	if err != nil {
+		log.Printf("ERROR: failed to get genres err=%s\n", err.Error())
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	var req GenreInfo
+	if ok := tools.BindJSON(w, r, &req); !ok {
+		return
+	}
+
+	res, err := h.service.Add(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to add genre err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrGenreExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "genre with given name already exists",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	req := GenreIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.Get(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get genre err=%s\n", err.Error())
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrGenreNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	req := GenreIdInfoRequest{
+		ID: r.PathValue("id"),
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.Update(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to update genre err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrGenreNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrGenreExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "genre with given name already exists",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	req := GenreIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.Delete(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete genre err=%s\n", err.Error())
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrGenreNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
@@ -0,0 +1,188 @@
+package genre
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"film-library/src/internal/db"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrGenreNotExist = errors.New("genre does not exist")
+	ErrGenreExist    = errors.New("genre already exists")
+)
+
+var _ GenreRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) Get(ctx context.Context, id int) (*Genre, error) {
+	const op = "genre.Repository.Get"
+
+	const query = `SELECT genre_id, genre_name FROM genre WHERE genre_id = $1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var g Genre
+	err = stmt.QueryRowContext(ctx, id).Scan(&g.ID, &g.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: genre with id=%d does not exist\n", id)
+			return nil, fmt.Errorf("%s: %w", op, ErrGenreNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &g, nil
+}
+
+func (r *Repository) Add(ctx context.Context, g *Genre) (*Genre, error) {
+	const op = "genre.Repository.Add"
+
+	const query = `INSERT INTO genre(genre_name) VALUES ($1) RETURNING genre_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, g.Name).Scan(&g.ID)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "unique_violation" {
+				log.Printf("ERROR: genre %s already exists\n", g.Name)
+				return nil, fmt.Errorf("%s: %w", op, ErrGenreExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return g, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	const op = "genre.Repository.Delete"
+
+	const query = `DELETE FROM genre WHERE genre_id = $1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by deletion\n")
+		return fmt.Errorf("%s: %w", op, ErrGenreNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) Update(ctx context.Context, g *Genre) error {
+	const op = "genre.Repository.Update"
+
+	const query = `UPDATE genre SET genre_name = $1 WHERE genre_id = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, g.Name, g.ID)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "unique_violation" {
+				log.Printf("ERROR: genre %s already exists\n", g.Name)
+				return fmt.Errorf("%s: %w", op, ErrGenreExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by update\n")
+		return fmt.Errorf("%s: %w", op, ErrGenreNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetAll(ctx context.Context) ([]*Genre, error) {
+	const op = "genre.Repository.GetAll"
+
+	const query = `SELECT genre_id, genre_name FROM genre ORDER BY genre_name`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var genres []*Genre
+	for rows.Next() {
+		var g Genre
+		err := rows.Scan(&g.ID, &g.Name)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		genres = append(genres, &g)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return genres, nil
+}
@@ -0,0 +1,138 @@
+package genre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+var (
+	ErrIdInvalid = errors.New("invalid id")
+)
+
+var _ GenreService = (*Service)(nil)
+
+type Service struct {
+	repo GenreRepository
+}
+
+func NewService(gr GenreRepository) *Service {
+	return &Service{
+		repo: gr,
+	}
+}
+
+func (s *Service) GetAll(ctx context.Context) ([]*GenreResponse, error) {
+	const op = "genre.Service.GetAll"
+
+	genres, err := s.repo.GetAll(ctx)
+	if err != nil {
+		log.Printf("ERROR: failed to get genre records from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := make([]*GenreResponse, 0, len(genres))
+	for _, v := range genres {
+		res = append(res, ToGenreResponse(v))
+	}
+
+	return res, nil
+}
+
+func (s *Service) Add(ctx context.Context, req *GenreInfo) (*GenreResponse, error) {
+	const op = "genre.Service.Add"
+
+	vErr := ValidateGenreInfo(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	genre := ToGenre(req)
+
+	genre, err := s.repo.Add(ctx, genre)
+	if err != nil {
+		log.Printf("ERROR: failed to create genre record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToGenreResponse(genre)
+
+	return res, nil
+}
+
+func (s *Service) Get(ctx context.Context, req *GenreIdRequest) (*GenreResponse, error) {
+	const op = "genre.Service.Get"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	genre, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get genre record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToGenreResponse(genre)
+
+	return res, nil
+}
+
+func (s *Service) Update(ctx context.Context, req *GenreIdInfoRequest) (*GenreResponse, error) {
+	const op = "genre.Service.Update"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateGenreInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	genre := ToGenre(&req.Info)
+	genre.ID = int(id)
+
+	err = s.repo.Update(ctx, genre)
+	if err != nil {
+		log.Printf("ERROR: failed to update genre record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToGenreResponse(genre)
+
+	return res, nil
+}
+
+func (s *Service) Delete(ctx context.Context, req *GenreIdRequest) (*GenreResponse, error) {
+	const op = "genre.Service.Delete"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	genre, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get genre record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repo.Delete(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to delete genre record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToGenreResponse(genre)
+
+	return res, nil
+}
@@ -0,0 +1,25 @@
+package genre
+
+import (
+	"strings"
+
+	"film-library/src/internal/tools"
+)
+
+func ValidateGenreInfo(gi *GenreInfo) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(strings.TrimSpace(gi.Name)) == 0 {
+		ve.AddViolation("name empty")
+	}
+
+	if len(gi.Name) > 50 {
+		ve.AddViolation("name length is more than 50 symbols")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
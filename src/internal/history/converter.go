@@ -0,0 +1,93 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+func ToVersionResponse(c *Change) *VersionResponse {
+	return &VersionResponse{
+		ID:        c.EntityID,
+		Version:   c.ID,
+		Action:    c.Action,
+		UserID:    c.UserID,
+		ChangedAt: c.ChangedAt.Format(time.RFC3339),
+		Record:    c.Snapshot,
+	}
+}
+
+// ToChangesResponse turns versions in the order they were made into a
+// history starting from the latest change, each entry lists fields that
+// differ from the version before it.
+func ToChangesResponse(changes []*Change) ([]*ChangeResponse, error) {
+	res := make([]*ChangeResponse, 0, len(changes))
+
+	var prev map[string]json.RawMessage
+	for _, v := range changes {
+		var cur map[string]json.RawMessage
+		if err := json.Unmarshal(v.Snapshot, &cur); err != nil {
+			return nil, err
+		}
+
+		res = append(res, &ChangeResponse{
+			Version:   v.ID,
+			Action:    v.Action,
+			UserID:    v.UserID,
+			ChangedAt: v.ChangedAt.Format(time.RFC3339),
+			Changes:   ToFieldChanges(prev, cur),
+		})
+		prev = cur
+	}
+	slices.Reverse(res)
+
+	return res, nil
+}
+
+// ToFieldChanges compares two snapshots field by field in field name
+// order, snapshots come from jsonb so equal values have equal encodings.
+func ToFieldChanges(from, to map[string]json.RawMessage) []*FieldChange {
+	fields := make([]string, 0, len(to))
+	for k := range to {
+		fields = append(fields, k)
+	}
+	for k := range from {
+		if _, ok := to[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	slices.Sort(fields)
+
+	changes := make([]*FieldChange, 0)
+	for _, k := range fields {
+		if from != nil && bytes.Equal(from[k], to[k]) {
+			continue
+		}
+
+		changes = append(changes, &FieldChange{
+			Field: k,
+			From:  from[k],
+			To:    to[k],
+		})
+	}
+
+	return changes
+}
+
+// ToDeleted tells whether the record was in trash in the given version.
+func ToDeleted(c *Change) bool {
+	var snapshot struct {
+		Deleted bool `json:"deleted"`
+	}
+	json.Unmarshal(c.Snapshot, &snapshot)
+
+	return snapshot.Deleted
+}
+
+// ToAsOf parses a validated asOf query.
+func ToAsOf(query string) time.Time {
+	at, _ := time.Parse(time.RFC3339, query)
+
+	return at
+}
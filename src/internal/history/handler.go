@@ -0,0 +1,144 @@
+package history
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+var _ HistoryHandler = (*Handler)(nil)
+
+type Handler struct {
+	service HistoryService
+}
+
+func NewHandler(hs HistoryService) *Handler {
+	return &Handler{
+		service: hs,
+	}
+}
+
+func (h *Handler) GetFilmHistory(w http.ResponseWriter, r *http.Request) {
+	req := HistoryIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.GetFilmHistory(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get film history err=%s\n", err.Error())
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrRecordNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetActorHistory(w http.ResponseWriter, r *http.Request) {
+	req := HistoryIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.GetActorHistory(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get actor history err=%s\n", err.Error())
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrRecordNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetFilmAsOf(w http.ResponseWriter, r *http.Request) {
+	req := AsOfRequest{
+		ID:        r.PathValue("id"),
+		AsOfQuery: r.URL.Query().Get("asOf"),
+	}
+
+	res, err := h.service.GetFilmAsOf(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get film version err=%s\n", err.Error())
+		h.versionError(w, r, err)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetActorAsOf(w http.ResponseWriter, r *http.Request) {
+	req := AsOfRequest{
+		ID:        r.PathValue("id"),
+		AsOfQuery: r.URL.Query().Get("asOf"),
+	}
+
+	res, err := h.service.GetActorAsOf(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get actor version err=%s\n", err.Error())
+		h.versionError(w, r, err)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) RevertFilm(w http.ResponseWriter, r *http.Request) {
+	req := RevertRequest{
+		ID:        r.PathValue("id"),
+		VersionID: r.PathValue("version"),
+	}
+
+	res, err := h.service.RevertFilm(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to revert film err=%s\n", err.Error())
+		h.versionError(w, r, err)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) RevertActor(w http.ResponseWriter, r *http.Request) {
+	req := RevertRequest{
+		ID:        r.PathValue("id"),
+		VersionID: r.PathValue("version"),
+	}
+
+	res, err := h.service.RevertActor(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to revert actor err=%s\n", err.Error())
+		h.versionError(w, r, err)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+// versionError writes the response for failed version reads and reverts.
+func (h *Handler) versionError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrRecordNotExist) || errors.Is(err, ErrVersionNotExist) {
+		tools.NotFound(w, r)
+		return
+	}
+
+	var ve *tools.ValidationError
+	if errors.As(err, &ve) {
+		tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+			ErrorType: tools.ErrorTypeValidation,
+			Body:      ve.Error(),
+		})
+		return
+	}
+
+	tools.InternalServerError(w, r)
+}
@@ -0,0 +1,115 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	EntityFilm  = "film"
+	EntityActor = "actor"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionCredits = "credits"
+	ActionRevert  = "revert"
+)
+
+// Change is a version of a film or actor, Snapshot holds the record as it
+// was right after the change and UserID is zero for changes made outside
+// of a request.
+type Change struct {
+	ID        int64
+	Entity    string
+	EntityID  int
+	Action    string
+	UserID    int
+	Snapshot  json.RawMessage
+	ChangedAt time.Time
+}
+
+// Recorder keeps a version of a film or actor after it has changed, the
+// acting user is taken from the context.
+type Recorder interface {
+	Record(ctx context.Context, entity string, id int, action string) error
+}
+
+// BatchRecorder keeps versions of many films or actors at once, it is meant
+// for bulk loads changing records by the thousand.
+type BatchRecorder interface {
+	RecordAll(ctx context.Context, entity string, ids []int, action string) error
+}
+
+type HistoryRepository interface {
+	AddChange(ctx context.Context, c *Change) (*Change, error)
+	AddChanges(ctx context.Context, entity string, ids []int, action string, userID int) error
+	GetChanges(ctx context.Context, entity string, id int) ([]*Change, error)
+	GetChangeAsOf(ctx context.Context, entity string, id int, at time.Time) (*Change, error)
+	RevertFilm(ctx context.Context, id int, changeID int64) error
+	RevertActor(ctx context.Context, id int, changeID int64) error
+}
+
+type HistoryService interface {
+	Recorder
+	BatchRecorder
+	GetFilmHistory(ctx context.Context, req *HistoryIdRequest) ([]*ChangeResponse, error)
+	GetActorHistory(ctx context.Context, req *HistoryIdRequest) ([]*ChangeResponse, error)
+	GetFilmAsOf(ctx context.Context, req *AsOfRequest) (*VersionResponse, error)
+	GetActorAsOf(ctx context.Context, req *AsOfRequest) (*VersionResponse, error)
+	RevertFilm(ctx context.Context, req *RevertRequest) (*VersionResponse, error)
+	RevertActor(ctx context.Context, req *RevertRequest) (*VersionResponse, error)
+}
+
+type HistoryHandler interface {
+	GetFilmHistory(w http.ResponseWriter, r *http.Request)
+	GetActorHistory(w http.ResponseWriter, r *http.Request)
+	GetFilmAsOf(w http.ResponseWriter, r *http.Request)
+	GetActorAsOf(w http.ResponseWriter, r *http.Request)
+	RevertFilm(w http.ResponseWriter, r *http.Request)
+	RevertActor(w http.ResponseWriter, r *http.Request)
+}
+
+// FieldChange is a field of a record that differs from the previous
+// version, From is null for the first version.
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+type ChangeResponse struct {
+	Version   int64          `json:"version"`
+	Action    string         `json:"action"`
+	UserID    int            `json:"userId,omitempty"`
+	ChangedAt string         `json:"changedAt"`
+	Changes   []*FieldChange `json:"changes"`
+}
+
+type VersionResponse struct {
+	ID        int             `json:"id"`
+	Version   int64           `json:"version"`
+	Action    string          `json:"action"`
+	UserID    int             `json:"userId,omitempty"`
+	ChangedAt string          `json:"changedAt"`
+	Record    json.RawMessage `json:"record"`
+}
+
+type HistoryIdRequest struct {
+	ID string
+}
+
+type AsOfRequest struct {
+	ID        string
+	AsOfQuery string
+}
+
+type RevertRequest struct {
+	ID        string
+	VersionID string
+}
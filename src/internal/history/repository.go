@@ -0,0 +1,326 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"film-library/src/internal/db"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrRecordNotExist  = errors.New("record does not exist")
+	ErrVersionNotExist = errors.New("version does not exist")
+)
+
+// snapshotQueries build snapshots of records with ids in $1 from their
+// current state, film snapshots carry credits and genres so that reverting
+// restores them.
+var snapshotQueries = map[string]string{
+	EntityFilm: `
+		SELECT m.movie_id, jsonb_build_object(
+			'name', m.movie_name,
+			'description', m.movie_description,
+			'releaseDate', m.releasedate,
+			'rating', m.rating,
+			'deleted', m.deleted_at IS NOT NULL,
+			'actors', COALESCE((
+				SELECT jsonb_agg(jsonb_build_object('id', a.actor_id, 'name', a.actor_name,
+					'characters', am.characters, 'billing', COALESCE(am.billing, 0))
+					ORDER BY am.billing NULLS LAST, a.actor_id)
+				FROM actor_in_movie am
+				INNER JOIN actor a USING (actor_id)
+				WHERE am.movie_id = m.movie_id
+			), '[]'),
+			'crew', COALESCE((
+				SELECT jsonb_agg(jsonb_build_object('id', a.actor_id, 'name', a.actor_name, 'role', mc.crew_role)
+					ORDER BY mc.crew_role, a.actor_id)
+				FROM movie_crew mc
+				INNER JOIN actor a ON a.actor_id = mc.person_id
+				WHERE mc.movie_id = m.movie_id
+			), '[]'),
+			'genres', COALESCE((
+				SELECT jsonb_agg(jsonb_build_object('id', g.genre_id, 'name', g.genre_name)
+					ORDER BY g.genre_name)
+				FROM movie_genre mg
+				INNER JOIN genre g USING (genre_id)
+				WHERE mg.movie_id = m.movie_id
+			), '[]')
+		)
+		FROM movie m
+		WHERE m.movie_id = ANY($1::int[])`,
+	EntityActor: `
+		SELECT a.actor_id, jsonb_build_object(
+			'name', a.actor_name,
+			'sex', a.sex,
+			'birthday', a.birthday,
+			'deleted', a.deleted_at IS NOT NULL
+		)
+		FROM actor a
+		WHERE a.actor_id = ANY($1::int[])`,
+}
+
+var _ HistoryRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) AddChange(ctx context.Context, c *Change) (*Change, error) {
+	const op = "history.Repository.AddChange"
+
+	query := `
+		WITH s(entity_id, snapshot) AS (` + snapshotQueries[c.Entity] + `)
+		INSERT INTO change_history(entity, entity_id, action, user_id, snapshot)
+		SELECT $2, s.entity_id, $3, NULLIF($4, 0), s.snapshot
+		FROM s
+		RETURNING change_id, snapshot, changed_at`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, pq.Array([]int{c.EntityID}), c.Entity, c.Action, c.UserID).Scan(&c.ID, &c.Snapshot, &c.ChangedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: %s with id=%d does not exist\n", c.Entity, c.EntityID)
+			return nil, fmt.Errorf("%s: %w", op, ErrRecordNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c, nil
+}
+
+// AddChanges keeps a version of every record with id in ids, records that
+// do not exist are skipped.
+func (r *Repository) AddChanges(ctx context.Context, entity string, ids []int, action string, userID int) error {
+	const op = "history.Repository.AddChanges"
+
+	query := `
+		WITH s(entity_id, snapshot) AS (` + snapshotQueries[entity] + `)
+		INSERT INTO change_history(entity, entity_id, action, user_id, snapshot)
+		SELECT $2, s.entity_id, $3, NULLIF($4, 0), s.snapshot
+		FROM s
+		ORDER BY s.entity_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, pq.Array(ids), entity, action, userID); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetChanges returns versions of a record in the order they were made.
+func (r *Repository) GetChanges(ctx context.Context, entity string, id int) ([]*Change, error) {
+	const op = "history.Repository.GetChanges"
+
+	const query = `
+		SELECT change_id, entity, entity_id, action, COALESCE(user_id, 0), snapshot, changed_at
+		FROM change_history
+		WHERE entity = $1 AND entity_id = $2
+		ORDER BY change_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, entity, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var changes []*Change
+	for rows.Next() {
+		var c Change
+		err := rows.Scan(&c.ID, &c.Entity, &c.EntityID, &c.Action, &c.UserID, &c.Snapshot, &c.ChangedAt)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		changes = append(changes, &c)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return changes, nil
+}
+
+// GetChangeAsOf returns the latest version of a record made at or before
+// the given time.
+func (r *Repository) GetChangeAsOf(ctx context.Context, entity string, id int, at time.Time) (*Change, error) {
+	const op = "history.Repository.GetChangeAsOf"
+
+	const query = `
+		SELECT change_id, entity, entity_id, action, COALESCE(user_id, 0), snapshot, changed_at
+		FROM change_history
+		WHERE entity = $1 AND entity_id = $2 AND changed_at <= $3
+		ORDER BY changed_at DESC, change_id DESC
+		LIMIT 1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var c Change
+	err = stmt.QueryRowContext(ctx, entity, id, at).Scan(&c.ID, &c.Entity, &c.EntityID, &c.Action, &c.UserID, &c.Snapshot, &c.ChangedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: no version of %s with id=%d as of %s\n", entity, id, at.Format(time.RFC3339))
+			return nil, fmt.Errorf("%s: %w", op, ErrVersionNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &c, nil
+}
+
+// RevertFilm sets fields, credits and genres of a film to the ones of the
+// given version in a single statement, credits of people and genres that
+// no longer exist are skipped.
+func (r *Repository) RevertFilm(ctx context.Context, id int, changeID int64) error {
+	const op = "history.Repository.RevertFilm"
+
+	const query = `
+		WITH v AS (
+			SELECT snapshot FROM change_history
+			WHERE change_id = $2 AND entity = 'film' AND entity_id = $1
+		), m AS (
+			UPDATE movie SET
+				movie_name = v.snapshot->>'name',
+				movie_description = v.snapshot->>'description',
+				releasedate = (v.snapshot->>'releaseDate')::date,
+				rating = (v.snapshot->>'rating')::int,
+				deleted_at = CASE WHEN (v.snapshot->>'deleted')::boolean THEN COALESCE(movie.deleted_at, now()) END
+			FROM v
+			WHERE movie.movie_id = $1
+			RETURNING movie.movie_id
+		), cast_v AS (
+			SELECT (c->>'id')::int AS actor_id,
+				ARRAY(SELECT jsonb_array_elements_text(c->'characters'))::varchar[] AS characters,
+				NULLIF((c->>'billing')::int, 0) AS billing
+			FROM v, jsonb_array_elements(v.snapshot->'actors') c
+			WHERE EXISTS (SELECT 1 FROM actor a WHERE a.actor_id = (c->>'id')::int)
+		), crew_v AS (
+			SELECT (c->>'id')::int AS person_id, c->>'role' AS crew_role
+			FROM v, jsonb_array_elements(v.snapshot->'crew') c
+			WHERE EXISTS (SELECT 1 FROM actor a WHERE a.actor_id = (c->>'id')::int)
+		), genre_v AS (
+			SELECT (g->>'id')::int AS genre_id
+			FROM v, jsonb_array_elements(v.snapshot->'genres') g
+			WHERE EXISTS (SELECT 1 FROM genre WHERE genre_id = (g->>'id')::int)
+		), cast_d AS (
+			DELETE FROM actor_in_movie am USING m
+			WHERE am.movie_id = m.movie_id AND am.actor_id NOT IN (SELECT actor_id FROM cast_v)
+		), cast_i AS (
+			INSERT INTO actor_in_movie(actor_id, movie_id, characters, billing)
+			SELECT c.actor_id, m.movie_id, c.characters, c.billing
+			FROM m, cast_v c
+			ON CONFLICT (actor_id, movie_id) DO UPDATE
+			SET characters = EXCLUDED.characters, billing = EXCLUDED.billing
+		), crew_d AS (
+			DELETE FROM movie_crew mc USING m
+			WHERE mc.movie_id = m.movie_id AND (mc.person_id, mc.crew_role) NOT IN (SELECT person_id, crew_role FROM crew_v)
+		), crew_i AS (
+			INSERT INTO movie_crew(person_id, movie_id, crew_role)
+			SELECT c.person_id, m.movie_id, c.crew_role
+			FROM m, crew_v c
+			ON CONFLICT DO NOTHING
+		), genre_d AS (
+			DELETE FROM movie_genre mg USING m
+			WHERE mg.movie_id = m.movie_id AND mg.genre_id NOT IN (SELECT genre_id FROM genre_v)
+		), genre_i AS (
+			INSERT INTO movie_genre(movie_id, genre_id)
+			SELECT m.movie_id, g.genre_id
+			FROM m, genre_v g
+			ON CONFLICT DO NOTHING
+		)
+		SELECT movie_id FROM m`
+
+	if err := r.revert(ctx, query, id, changeID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) RevertActor(ctx context.Context, id int, changeID int64) error {
+	const op = "history.Repository.RevertActor"
+
+	const query = `
+		WITH v AS (
+			SELECT snapshot FROM change_history
+			WHERE change_id = $2 AND entity = 'actor' AND entity_id = $1
+		)
+		UPDATE actor SET
+			actor_name = v.snapshot->>'name',
+			sex = v.snapshot->>'sex',
+			birthday = (v.snapshot->>'birthday')::date,
+			deleted_at = CASE WHEN (v.snapshot->>'deleted')::boolean THEN COALESCE(actor.deleted_at, now()) END
+		FROM v
+		WHERE actor.actor_id = $1
+		RETURNING actor.actor_id`
+
+	if err := r.revert(ctx, query, id, changeID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// revert runs a revert query, no row is returned when the version does not
+// belong to the record or the record has been purged.
+func (r *Repository) revert(ctx context.Context, query string, id int, changeID int64) error {
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return err
+	}
+	defer stmt.Close()
+
+	var revertedID int
+	err = stmt.QueryRowContext(ctx, id, changeID).Scan(&revertedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: version id=%d of record id=%d does not exist\n", changeID, id)
+			return ErrVersionNotExist
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/tools"
+)
+
+var (
+	ErrIdInvalid = errors.New("invalid id")
+)
+
+var _ HistoryService = (*Service)(nil)
+
+type Service struct {
+	repo HistoryRepository
+	tx   db.Transactor
+}
+
+func NewService(hr HistoryRepository, tx db.Transactor) *Service {
+	return &Service{
+		repo: hr,
+		tx:   tx,
+	}
+}
+
+func (s *Service) Record(ctx context.Context, entity string, id int, action string) error {
+	const op = "history.Service.Record"
+
+	if _, err := s.addChange(ctx, entity, id, action); err != nil {
+		log.Printf("ERROR: failed to record %s change of %s with id=%d\n", action, entity, id)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) RecordAll(ctx context.Context, entity string, ids []int, action string) error {
+	const op = "history.Service.RecordAll"
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var userID int
+	if uc, ok := tools.UserClaimsFromContext(ctx); ok {
+		userID = uc.ID
+	}
+
+	if err := s.repo.AddChanges(ctx, entity, ids, action, userID); err != nil {
+		log.Printf("ERROR: failed to record %s changes of %d %s records\n", action, len(ids), entity)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetFilmHistory(ctx context.Context, req *HistoryIdRequest) ([]*ChangeResponse, error) {
+	const op = "history.Service.GetFilmHistory"
+
+	res, err := s.getHistory(ctx, EntityFilm, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) GetActorHistory(ctx context.Context, req *HistoryIdRequest) ([]*ChangeResponse, error) {
+	const op = "history.Service.GetActorHistory"
+
+	res, err := s.getHistory(ctx, EntityActor, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) GetFilmAsOf(ctx context.Context, req *AsOfRequest) (*VersionResponse, error) {
+	const op = "history.Service.GetFilmAsOf"
+
+	res, err := s.getAsOf(ctx, EntityFilm, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) GetActorAsOf(ctx context.Context, req *AsOfRequest) (*VersionResponse, error) {
+	const op = "history.Service.GetActorAsOf"
+
+	res, err := s.getAsOf(ctx, EntityActor, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// RevertFilm brings a film back to the given version and records the
+// result as a new version, so that the revert itself can be undone.
+func (s *Service) RevertFilm(ctx context.Context, req *RevertRequest) (*VersionResponse, error) {
+	const op = "history.Service.RevertFilm"
+
+	res, err := s.revert(ctx, EntityFilm, req, s.repo.RevertFilm)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) RevertActor(ctx context.Context, req *RevertRequest) (*VersionResponse, error) {
+	const op = "history.Service.RevertActor"
+
+	res, err := s.revert(ctx, EntityActor, req, s.repo.RevertActor)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) addChange(ctx context.Context, entity string, id int, action string) (*Change, error) {
+	c := &Change{
+		Entity:   entity,
+		EntityID: id,
+		Action:   action,
+	}
+	if uc, ok := tools.UserClaimsFromContext(ctx); ok {
+		c.UserID = uc.ID
+	}
+
+	return s.repo.AddChange(ctx, c)
+}
+
+func (s *Service) getHistory(ctx context.Context, entity string, req *HistoryIdRequest) ([]*ChangeResponse, error) {
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, ErrIdInvalid
+	}
+
+	changes, err := s.repo.GetChanges(ctx, entity, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get %s changes from repository\n", entity)
+		return nil, err
+	}
+	if len(changes) == 0 {
+		log.Printf("ERROR: no changes of %s with id=%d\n", entity, id)
+		return nil, ErrRecordNotExist
+	}
+
+	res, err := ToChangesResponse(changes)
+	if err != nil {
+		log.Printf("ERROR: failed to decode %s snapshots\n", entity)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Service) getAsOf(ctx context.Context, entity string, req *AsOfRequest) (*VersionResponse, error) {
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, ErrIdInvalid
+	}
+
+	vErr := ValidateAsOfRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, vErr
+	}
+
+	change, err := s.repo.GetChangeAsOf(ctx, entity, int(id), ToAsOf(req.AsOfQuery))
+	if err != nil {
+		log.Printf("ERROR: failed to get %s version from repository\n", entity)
+		return nil, err
+	}
+	// a deleted record is hidden from point-in-time reads just like it is
+	// from regular ones
+	if ToDeleted(change) {
+		log.Printf("ERROR: %s with id=%d was deleted as of given time\n", entity, id)
+		return nil, ErrVersionNotExist
+	}
+
+	res := ToVersionResponse(change)
+
+	return res, nil
+}
+
+func (s *Service) revert(ctx context.Context, entity string, req *RevertRequest, apply func(ctx context.Context, id int, changeID int64) error) (*VersionResponse, error) {
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, ErrIdInvalid
+	}
+
+	changeID, err := strconv.ParseInt(req.VersionID, 10, 64)
+	if err != nil {
+		log.Printf("ERROR: failed version parameter conversion (string -> int64)\n")
+		return nil, ErrIdInvalid
+	}
+
+	var change *Change
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := apply(ctx, int(id), changeID); err != nil {
+			log.Printf("ERROR: failed to revert %s in repository\n", entity)
+			return err
+		}
+
+		change, err = s.addChange(ctx, entity, int(id), ActionRevert)
+		if err != nil {
+			log.Printf("ERROR: failed to record reverted %s\n", entity)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := ToVersionResponse(change)
+
+	return res, nil
+}
@@ -0,0 +1,21 @@
+package history
+
+import (
+	"time"
+
+	"film-library/src/internal/tools"
+)
+
+func ValidateAsOfRequest(req *AsOfRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if _, err := time.Parse(time.RFC3339, req.AsOfQuery); err != nil {
+		ve.AddViolation("asOf is not an RFC 3339 timestamp")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
@@ -0,0 +1,23 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+)
+
+// ToFingerprint hashes what makes two requests the same, a key reused for
+// a request with another fingerprint is refused.
+func ToFingerprint(req *KeyRequest) []byte {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.Path + "\n"))
+	h.Write(req.Body)
+
+	return h.Sum(nil)
+}
+
+func ToRecord(req *KeyRequest) *Record {
+	return &Record{
+		UserID:      req.UserID,
+		Key:         req.Key,
+		Fingerprint: ToFingerprint(req),
+	}
+}
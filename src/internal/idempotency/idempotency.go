@@ -0,0 +1,52 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Record is a request made with an idempotency key, Response is nil while
+// the request is in progress.
+type Record struct {
+	UserID      int
+	Key         string
+	Fingerprint []byte
+	Response    *Response
+}
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+type IdempotencyRepository interface {
+	// Add keeps rec for ttl unless its key is in use, false is returned
+	// then. A key that has expired is taken over, so is a key whose request
+	// is still in progress after lease.
+	Add(ctx context.Context, rec *Record, ttl, lease time.Duration) (bool, error)
+	Get(ctx context.Context, userID int, key string) (*Record, error)
+	Complete(ctx context.Context, rec *Record) error
+	Delete(ctx context.Context, userID int, key string) error
+	DeleteExpired(ctx context.Context) error
+}
+
+// IdempotencyService reserves keys of requests before they are handled,
+// a reserved key is either completed with the response or released for
+// the request to be made again.
+type IdempotencyService interface {
+	Begin(ctx context.Context, req *KeyRequest) (*Response, error)
+	Complete(ctx context.Context, req *KeyRequest, res *Response) error
+	Release(ctx context.Context, req *KeyRequest) error
+}
+
+// KeyRequest is a request made with an idempotency key, Path includes the
+// query.
+type KeyRequest struct {
+	Key    string
+	UserID int
+	Method string
+	Path   string
+	Body   []byte
+}
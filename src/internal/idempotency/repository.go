@@ -0,0 +1,182 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"film-library/src/internal/db"
+)
+
+var (
+	ErrKeyNotExist = errors.New("idempotency key does not exist")
+)
+
+var _ IdempotencyRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) Add(ctx context.Context, rec *Record, ttl, lease time.Duration) (bool, error) {
+	const op = "idempotency.Repository.Add"
+
+	const query = `
+		INSERT INTO idempotency_key(user_id, idem_key, fingerprint, locked_until, expires_at)
+		VALUES ($1, $2, $3, now() + make_interval(secs => $5), now() + make_interval(secs => $4))
+		ON CONFLICT (user_id, idem_key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, header = NULL, body = NULL,
+			locked_until = EXCLUDED.locked_until, created_at = now(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_key.expires_at <= now()
+			OR (idempotency_key.status_code IS NULL AND idempotency_key.locked_until <= now())`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, rec.UserID, rec.Key, rec.Fingerprint, ttl.Seconds(), lease.Seconds())
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count != 0, nil
+}
+
+func (r *Repository) Get(ctx context.Context, userID int, key string) (*Record, error) {
+	const op = "idempotency.Repository.Get"
+
+	const query = `
+		SELECT fingerprint, status_code, COALESCE(header, '{}'), body
+		FROM idempotency_key
+		WHERE user_id = $1 AND idem_key = $2 AND expires_at > now()`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rec := Record{
+		UserID: userID,
+		Key:    key,
+	}
+	var statusCode sql.NullInt32
+	var header, body []byte
+	err = stmt.QueryRowContext(ctx, userID, key).Scan(&rec.Fingerprint, &statusCode, &header, &body)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrKeyNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if statusCode.Valid {
+		rec.Response = &Response{
+			StatusCode: int(statusCode.Int32),
+			Body:       body,
+		}
+		if err := json.Unmarshal(header, &rec.Response.Header); err != nil {
+			log.Printf("ERROR: failed to decode response header\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return &rec, nil
+}
+
+func (r *Repository) Complete(ctx context.Context, rec *Record) error {
+	const op = "idempotency.Repository.Complete"
+
+	header, err := json.Marshal(rec.Response.Header)
+	if err != nil {
+		log.Printf("ERROR: failed to encode response header\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	const query = `
+		UPDATE idempotency_key SET status_code = $3, header = $4, body = $5, locked_until = NULL
+		WHERE user_id = $1 AND idem_key = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, rec.UserID, rec.Key, rec.Response.StatusCode, header, rec.Response.Body)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by update\n")
+		return fmt.Errorf("%s: %w", op, ErrKeyNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, userID int, key string) error {
+	const op = "idempotency.Repository.Delete"
+
+	const query = `DELETE FROM idempotency_key WHERE user_id = $1 AND idem_key = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, userID, key); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteExpired(ctx context.Context) error {
+	const op = "idempotency.Repository.DeleteExpired"
+
+	const query = `DELETE FROM idempotency_key WHERE expires_at <= now()`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+var (
+	ErrKeyReused     = errors.New("idempotency key is used for another request")
+	ErrKeyInProgress = errors.New("request with the idempotency key is in progress")
+)
+
+var _ IdempotencyService = (*Service)(nil)
+
+type Service struct {
+	repo  IdempotencyRepository
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// NewService keeps keys for ttl, a request made again later is handled as
+// a new one. A request in progress holds its key for lease, for the key not
+// to stay reserved by a request that never completes.
+func NewService(ir IdempotencyRepository, ttl, lease time.Duration) *Service {
+	return &Service{
+		repo:  ir,
+		ttl:   ttl,
+		lease: lease,
+	}
+}
+
+// Begin reserves the key of the request, the response of the first request
+// made with the key is returned when that request is already completed.
+func (s *Service) Begin(ctx context.Context, req *KeyRequest) (*Response, error) {
+	const op = "idempotency.Service.Begin"
+
+	vErr := ValidateKeyRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	rec := ToRecord(req)
+	added, err := s.repo.Add(ctx, rec, s.ttl, s.lease)
+	if err != nil {
+		log.Printf("ERROR: failed to add idempotency key in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if added {
+		return nil, nil
+	}
+
+	existing, err := s.repo.Get(ctx, req.UserID, req.Key)
+	if err != nil {
+		// the key was released or expired right after being found in use
+		if errors.Is(err, ErrKeyNotExist) {
+			return nil, fmt.Errorf("%s: %w", op, ErrKeyInProgress)
+		}
+
+		log.Printf("ERROR: failed to get idempotency key from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !bytes.Equal(existing.Fingerprint, rec.Fingerprint) {
+		log.Printf("ERROR: idempotency key %q is reused for another request\n", req.Key)
+		return nil, fmt.Errorf("%s: %w", op, ErrKeyReused)
+	}
+
+	if existing.Response == nil {
+		log.Printf("ERROR: request with idempotency key %q is in progress\n", req.Key)
+		return nil, fmt.Errorf("%s: %w", op, ErrKeyInProgress)
+	}
+
+	return existing.Response, nil
+}
+
+// Sweep deletes expired keys every interval until ctx is done, expired keys
+// are ignored anyway and are only deleted to free the space they take.
+func (s *Service) Sweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.repo.DeleteExpired(ctx); err != nil {
+				log.Printf("ERROR: failed to delete expired idempotency keys err=%s\n", err.Error())
+			}
+		}
+	}
+}
+
+func (s *Service) Complete(ctx context.Context, req *KeyRequest, res *Response) error {
+	const op = "idempotency.Service.Complete"
+
+	rec := ToRecord(req)
+	rec.Response = res
+	if err := s.repo.Complete(ctx, rec); err != nil {
+		log.Printf("ERROR: failed to complete idempotency key in repository\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) Release(ctx context.Context, req *KeyRequest) error {
+	const op = "idempotency.Service.Release"
+
+	if err := s.repo.Delete(ctx, req.UserID, req.Key); err != nil {
+		log.Printf("ERROR: failed to delete idempotency key in repository\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
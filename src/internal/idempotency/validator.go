@@ -0,0 +1,28 @@
+package idempotency
+
+import (
+	"film-library/src/internal/tools"
+)
+
+const maxKeyLength = 255
+
+func ValidateKeyRequest(req *KeyRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(req.Key) > maxKeyLength {
+		ve.AddViolation("idempotency key length is more than 255 symbols")
+	}
+
+	for _, v := range req.Key {
+		if v < 0x21 || v > 0x7e {
+			ve.AddViolation("idempotency key contains symbols other than printable ASCII")
+			break
+		}
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
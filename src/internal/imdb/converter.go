@@ -0,0 +1,107 @@
+package imdb
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToTitle converts a title.basics row, false is returned for titles not
+// passing the filter or lacking a release year.
+func ToTitle(r Row, tf *TitleFilter) (*Title, bool) {
+	if _, ok := tf.Types[r.Get("titleType")]; !ok {
+		return nil, false
+	}
+
+	if r.Get("isAdult") == "1" && !tf.IncludeAdult {
+		return nil, false
+	}
+
+	year, err := strconv.Atoi(r.Get("startYear"))
+	if err != nil || year <= 0 {
+		return nil, false
+	}
+
+	name := r.Get("primaryTitle")
+	if len(name) == 0 {
+		return nil, false
+	}
+
+	return &Title{
+		ID:          r.Get("tconst"),
+		Name:        name,
+		ReleaseDate: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}, true
+}
+
+// ToName converts a name.basics row, false is returned for people who
+// never worked as actors.
+func ToName(r Row) (*Name, bool) {
+	n := &Name{
+		ID:   r.Get("nconst"),
+		Name: r.Get("primaryName"),
+	}
+	if len(n.Name) == 0 {
+		return nil, false
+	}
+
+	// professions are listed most notable first
+	for _, v := range strings.Split(r.Get("primaryProfession"), ",") {
+		switch v {
+		case "actress":
+			n.Sex = "female"
+		case "actor":
+			n.Sex = "male"
+		default:
+			continue
+		}
+		return n, true
+	}
+
+	return nil, false
+}
+
+// ToPrincipal converts a title.principals row, false is returned for
+// credits other than acting ones.
+func ToPrincipal(r Row) (*Principal, bool) {
+	if _, ok := castCategories[r.Get("category")]; !ok {
+		return nil, false
+	}
+
+	ordering, err := strconv.Atoi(r.Get("ordering"))
+	if err != nil || ordering <= 0 {
+		return nil, false
+	}
+
+	p := &Principal{
+		TitleID:    r.Get("tconst"),
+		NameID:     r.Get("nconst"),
+		Ordering:   ordering,
+		Characters: "[]",
+	}
+
+	if characters := r.Get("characters"); len(characters) != 0 {
+		var c []string
+		if err := json.Unmarshal([]byte(characters), &c); err != nil {
+			return nil, false
+		}
+		p.Characters = characters
+	}
+
+	return p, true
+}
+
+func ToTitleFilter(types []string, includeAdult bool) *TitleFilter {
+	tf := &TitleFilter{
+		Types:        make(map[string]struct{}, len(types)),
+		IncludeAdult: includeAdult,
+	}
+	for _, v := range types {
+		if v = strings.TrimSpace(v); len(v) != 0 {
+			tf.Types[v] = struct{}{}
+		}
+	}
+
+	return tf
+}
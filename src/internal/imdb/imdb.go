@@ -0,0 +1,73 @@
+package imdb
+
+import (
+	"context"
+	"time"
+)
+
+// nullValue marks missing fields in IMDb datasets.
+const nullValue = `\N`
+
+const DefaultBatchSize = 1000
+
+// Columns read from title.basics, name.basics and title.principals.
+var (
+	titleColumns     = []string{"tconst", "titleType", "primaryTitle", "isAdult", "startYear"}
+	nameColumns      = []string{"nconst", "primaryName", "primaryProfession"}
+	principalColumns = []string{"tconst", "ordering", "nconst", "category", "characters"}
+)
+
+// castCategories are principal categories stored as actor credits.
+var castCategories = map[string]struct{}{
+	"actor":   {},
+	"actress": {},
+}
+
+// Title is a film of title.basics, ReleaseDate is the first day of its
+// release year as datasets carry years only.
+type Title struct {
+	ID          string
+	Name        string
+	ReleaseDate time.Time
+}
+
+// Name is an actor of name.basics.
+type Name struct {
+	ID   string
+	Name string
+	Sex  string
+}
+
+// Principal is a cast credit of title.principals, Characters holds the
+// JSON array of played characters as given in the dataset.
+type Principal struct {
+	TitleID    string
+	NameID     string
+	Ordering   int
+	Characters string
+}
+
+// TitleFilter selects titles to import, Types are accepted titleType
+// values.
+type TitleFilter struct {
+	Types        map[string]struct{}
+	IncludeAdult bool
+}
+
+// Stats counts rows of a dataset, Skipped rows are malformed or filtered
+// out, unchanged records and credits of films or actors not imported.
+type Stats struct {
+	Read     int
+	Upserted int
+	Skipped  int
+}
+
+// DatasetRepository upserts records of datasets, ids of films and actors
+// are returned split into added and updated ones.
+type DatasetRepository interface {
+	UpsertFilms(ctx context.Context, t []*Title) ([]int, []int, error)
+	UpsertActors(ctx context.Context, n []*Name) ([]int, []int, error)
+	// UpsertCredits stores credits of films and actors already imported
+	// and ignores the rest, the film id of every stored credit is returned.
+	UpsertCredits(ctx context.Context, p []*Principal) ([]int, error)
+}
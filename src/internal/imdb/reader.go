@@ -0,0 +1,132 @@
+package imdb
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxLineSize bounds a single dataset line, the longest lines of the
+// datasets are well below it.
+const maxLineSize = 1024 * 1024
+
+var (
+	ErrColumnMissing = errors.New("dataset column missing")
+	ErrRowMalformed  = errors.New("malformed dataset row")
+)
+
+// File is an opened dataset, gzip compressed files are decompressed
+// transparently.
+type File struct {
+	io.Reader
+	f  *os.File
+	gz *gzip.Reader
+}
+
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(path, ".gz") {
+		return &File{Reader: f, f: f}, nil
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &File{Reader: gz, f: f, gz: gz}, nil
+}
+
+func (f *File) Close() error {
+	if f.gz != nil {
+		f.gz.Close()
+	}
+
+	return f.f.Close()
+}
+
+// DatasetReader streams rows of a tab separated dataset one line at a
+// time.
+type DatasetReader struct {
+	sc      *bufio.Scanner
+	columns map[string]int
+	width   int
+}
+
+// NewDatasetReader reads the header and makes sure every required column
+// is present.
+func NewDatasetReader(r io.Reader, required []string) (*DatasetReader, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: empty dataset", ErrColumnMissing)
+	}
+
+	header := strings.Split(sc.Text(), "\t")
+	columns := make(map[string]int, len(header))
+	for i, v := range header {
+		columns[v] = i
+	}
+
+	for _, v := range required {
+		if _, ok := columns[v]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrColumnMissing, v)
+		}
+	}
+
+	return &DatasetReader{
+		sc:      sc,
+		columns: columns,
+		width:   len(header),
+	}, nil
+}
+
+// Read returns the next row, io.EOF is returned at the end of the dataset
+// and ErrRowMalformed for rows not matching the header.
+func (d *DatasetReader) Read() (Row, error) {
+	if !d.sc.Scan() {
+		if err := d.sc.Err(); err != nil {
+			return Row{}, err
+		}
+		return Row{}, io.EOF
+	}
+
+	fields := strings.Split(d.sc.Text(), "\t")
+	if len(fields) != d.width {
+		return Row{}, ErrRowMalformed
+	}
+
+	return Row{
+		fields:  fields,
+		columns: d.columns,
+	}, nil
+}
+
+type Row struct {
+	fields  []string
+	columns map[string]int
+}
+
+// Get returns the field of the column or an empty string when the field
+// is missing.
+func (r Row) Get(column string) string {
+	v := r.fields[r.columns[column]]
+	if v == nullValue {
+		return ""
+	}
+
+	return v
+}
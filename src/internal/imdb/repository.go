@@ -0,0 +1,194 @@
+package imdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"film-library/src/internal/db"
+	"github.com/lib/pq"
+)
+
+var _ DatasetRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// UpsertFilms adds films missing by imdb_id and refreshes name and release
+// date of the rest, descriptions and ratings are left to editors. Datasets
+// carry release years only, so a date is only replaced when its year
+// differs, and films that would not change are left untouched.
+func (r *Repository) UpsertFilms(ctx context.Context, t []*Title) ([]int, []int, error) {
+	const op = "imdb.Repository.UpsertFilms"
+
+	ids := make([]string, 0, len(t))
+	names := make([]string, 0, len(t))
+	dates := make([]string, 0, len(t))
+	for _, v := range t {
+		ids = append(ids, v.ID)
+		names = append(names, v.Name)
+		dates = append(dates, v.ReleaseDate.Format(time.DateOnly))
+	}
+
+	const query = `
+		INSERT INTO movie(imdb_id, movie_name, movie_description, releasedate, rating)
+		SELECT t.imdb_id, t.movie_name, '', t.releasedate, 0
+		FROM UNNEST($1::varchar[], $2::varchar[], $3::date[]) AS t(imdb_id, movie_name, releasedate)
+		ON CONFLICT (imdb_id) DO UPDATE
+		SET movie_name = EXCLUDED.movie_name,
+			releasedate = CASE
+				WHEN EXTRACT(year FROM movie.releasedate) <> EXTRACT(year FROM EXCLUDED.releasedate)
+				THEN EXCLUDED.releasedate ELSE movie.releasedate END
+		WHERE movie.movie_name IS DISTINCT FROM EXCLUDED.movie_name
+			OR EXTRACT(year FROM movie.releasedate) <> EXTRACT(year FROM EXCLUDED.releasedate)
+		RETURNING movie_id, xmax = 0`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(ids), pq.Array(names), pq.Array(dates))
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	return scanUpserted(rows, op)
+}
+
+// UpsertActors adds actors missing by imdb_id and refreshes names of the
+// rest, sex is only filled in when unknown. Actors that would not change are
+// left untouched.
+func (r *Repository) UpsertActors(ctx context.Context, n []*Name) ([]int, []int, error) {
+	const op = "imdb.Repository.UpsertActors"
+
+	ids := make([]string, 0, len(n))
+	names := make([]string, 0, len(n))
+	sexes := make([]string, 0, len(n))
+	for _, v := range n {
+		ids = append(ids, v.ID)
+		names = append(names, v.Name)
+		sexes = append(sexes, v.Sex)
+	}
+
+	const query = `
+		INSERT INTO actor(imdb_id, actor_name, sex)
+		SELECT * FROM UNNEST($1::varchar[], $2::varchar[], $3::varchar[])
+		ON CONFLICT (imdb_id) DO UPDATE
+		SET actor_name = EXCLUDED.actor_name,
+			sex = CASE WHEN actor.sex = '' THEN EXCLUDED.sex ELSE actor.sex END
+		WHERE actor.actor_name IS DISTINCT FROM EXCLUDED.actor_name
+			OR (actor.sex = '' AND EXCLUDED.sex <> '')
+		RETURNING actor_id, xmax = 0`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(ids), pq.Array(names), pq.Array(sexes))
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	return scanUpserted(rows, op)
+}
+
+func (r *Repository) UpsertCredits(ctx context.Context, p []*Principal) ([]int, error) {
+	const op = "imdb.Repository.UpsertCredits"
+
+	titleIDs := make([]string, 0, len(p))
+	nameIDs := make([]string, 0, len(p))
+	billings := make([]int64, 0, len(p))
+	characters := make([]string, 0, len(p))
+	for _, v := range p {
+		titleIDs = append(titleIDs, v.TitleID)
+		nameIDs = append(nameIDs, v.NameID)
+		billings = append(billings, int64(v.Ordering))
+		characters = append(characters, v.Characters)
+	}
+
+	const query = `
+		INSERT INTO actor_in_movie(actor_id, movie_id, characters, billing)
+		SELECT a.actor_id, m.movie_id,
+			ARRAY(SELECT json_array_elements_text(p.characters::json)), p.billing
+		FROM UNNEST($1::varchar[], $2::varchar[], $3::int[], $4::varchar[])
+			AS p(title_id, name_id, billing, characters)
+		INNER JOIN movie m ON m.imdb_id = p.title_id
+		INNER JOIN actor a ON a.imdb_id = p.name_id
+		ON CONFLICT (actor_id, movie_id) DO UPDATE
+		SET characters = EXCLUDED.characters, billing = EXCLUDED.billing
+		WHERE actor_in_movie.characters IS DISTINCT FROM EXCLUDED.characters
+			OR actor_in_movie.billing IS DISTINCT FROM EXCLUDED.billing
+		RETURNING movie_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(titleIDs), pq.Array(nameIDs), pq.Array(billings), pq.Array(characters))
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	filmIDs := make([]int, 0, len(p))
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("ERROR: failed to scan row\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		filmIDs = append(filmIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to iterate rows\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return filmIDs, nil
+}
+
+// scanUpserted splits ids returned by an upsert into ids of added and of
+// updated rows, rows are returned with the id and whether they were added.
+func scanUpserted(rows *sql.Rows, op string) ([]int, []int, error) {
+	var created, updated []int
+	for rows.Next() {
+		var id int
+		var inserted bool
+		if err := rows.Scan(&id, &inserted); err != nil {
+			log.Printf("ERROR: failed to scan row\n")
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if inserted {
+			created = append(created, id)
+		} else {
+			updated = append(updated, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to iterate rows\n")
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, updated, nil
+}
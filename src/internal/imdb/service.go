@@ -0,0 +1,247 @@
+package imdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/history"
+	"film-library/src/internal/tools"
+)
+
+// progressInterval is the number of rows read between progress reports.
+const progressInterval = 100000
+
+type Service struct {
+	repo      DatasetRepository
+	tx        db.Transactor
+	history   history.BatchRecorder
+	batchSize int
+}
+
+// NewService upserts batches of batchSize rows, each in a transaction of
+// its own along with the history of the films and actors it changes.
+func NewService(dr DatasetRepository, tx db.Transactor, hr history.BatchRecorder, batchSize int) *Service {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &Service{
+		repo:      dr,
+		tx:        tx,
+		history:   hr,
+		batchSize: batchSize,
+	}
+}
+
+// ImportTitles upserts films of a title.basics dataset passing the filter.
+func (s *Service) ImportTitles(ctx context.Context, r io.Reader, tf *TitleFilter) (*Stats, error) {
+	const op = "imdb.Service.ImportTitles"
+
+	convert := func(row Row) (*Title, bool) {
+		return ToTitle(row, tf)
+	}
+
+	stats, err := importDataset(ctx, r, titleColumns, s.batchSize, convert, s.upsertFilms)
+	if err != nil {
+		log.Printf("ERROR: failed to import titles\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// ImportNames upserts actors of a name.basics dataset.
+func (s *Service) ImportNames(ctx context.Context, r io.Reader) (*Stats, error) {
+	const op = "imdb.Service.ImportNames"
+
+	stats, err := importDataset(ctx, r, nameColumns, s.batchSize, ToName, s.upsertActors)
+	if err != nil {
+		log.Printf("ERROR: failed to import names\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// ImportPrincipals upserts cast credits of a title.principals dataset,
+// films and actors have to be imported beforehand.
+func (s *Service) ImportPrincipals(ctx context.Context, r io.Reader) (*Stats, error) {
+	const op = "imdb.Service.ImportPrincipals"
+
+	// the dataset is sorted by title, so only actors credited on the
+	// current title are remembered to drop repeated credits
+	var title string
+	seen := make(map[string]struct{})
+	convert := func(row Row) (*Principal, bool) {
+		p, ok := ToPrincipal(row)
+		if !ok {
+			return nil, false
+		}
+
+		if p.TitleID != title {
+			title = p.TitleID
+			clear(seen)
+		}
+		if _, ok := seen[p.NameID]; ok {
+			return nil, false
+		}
+		seen[p.NameID] = struct{}{}
+
+		return p, true
+	}
+
+	stats, err := importDataset(ctx, r, principalColumns, s.batchSize, convert, s.upsertCredits)
+	if err != nil {
+		log.Printf("ERROR: failed to import principals\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (s *Service) upsertFilms(ctx context.Context, t []*Title) (int, error) {
+	var n int
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		created, updated, err := s.repo.UpsertFilms(ctx, t)
+		if err != nil {
+			log.Printf("ERROR: failed to upsert film records in repository\n")
+			return err
+		}
+		n = len(created) + len(updated)
+
+		return s.record(ctx, history.EntityFilm, created, updated)
+	})
+
+	return n, err
+}
+
+func (s *Service) upsertActors(ctx context.Context, a []*Name) (int, error) {
+	var n int
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		created, updated, err := s.repo.UpsertActors(ctx, a)
+		if err != nil {
+			log.Printf("ERROR: failed to upsert actor records in repository\n")
+			return err
+		}
+		n = len(created) + len(updated)
+
+		return s.record(ctx, history.EntityActor, created, updated)
+	})
+
+	return n, err
+}
+
+func (s *Service) upsertCredits(ctx context.Context, p []*Principal) (int, error) {
+	var n int
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		filmIDs, err := s.repo.UpsertCredits(ctx, p)
+		if err != nil {
+			log.Printf("ERROR: failed to upsert credit records in repository\n")
+			return err
+		}
+		n = len(filmIDs)
+
+		if err := s.history.RecordAll(ctx, history.EntityFilm, tools.RemoveDuplicateInt(filmIDs), history.ActionCredits); err != nil {
+			log.Printf("ERROR: failed to record film history\n")
+			return err
+		}
+
+		return nil
+	})
+
+	return n, err
+}
+
+// record keeps versions of records added and updated by a batch.
+func (s *Service) record(ctx context.Context, entity string, created, updated []int) error {
+	if err := s.history.RecordAll(ctx, entity, created, history.ActionCreate); err != nil {
+		log.Printf("ERROR: failed to record %s history\n", entity)
+		return err
+	}
+
+	if err := s.history.RecordAll(ctx, entity, updated, history.ActionUpdate); err != nil {
+		log.Printf("ERROR: failed to record %s history\n", entity)
+		return err
+	}
+
+	return nil
+}
+
+// importDataset streams rows of r through convert and upserts them in
+// batches, so memory use does not depend on the dataset size.
+func importDataset[T any](ctx context.Context, r io.Reader, columns []string, batchSize int,
+	convert func(Row) (T, bool), upsert func(context.Context, []T) (int, error)) (*Stats, error) {
+	const op = "imdb.importDataset"
+
+	d, err := NewDatasetReader(r, columns)
+	if err != nil {
+		log.Printf("ERROR: failed to read dataset header\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats := &Stats{}
+	batch := make([]T, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		n, err := upsert(ctx, batch)
+		if err != nil {
+			return err
+		}
+		stats.Upserted += n
+		stats.Skipped += len(batch) - n
+		batch = batch[:0]
+
+		return nil
+	}
+
+	for {
+		row, err := d.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		stats.Read++
+		if stats.Read%progressInterval == 0 {
+			log.Printf("INFO: %d rows read\n", stats.Read)
+		}
+
+		if errors.Is(err, ErrRowMalformed) {
+			stats.Skipped++
+			continue
+		}
+		if err != nil {
+			log.Printf("ERROR: failed to read dataset row\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		v, ok := convert(row)
+		if !ok {
+			stats.Skipped++
+			continue
+		}
+
+		batch = append(batch, v)
+		if len(batch) < batchSize {
+			continue
+		}
+
+		if err := flush(); err != nil {
+			log.Printf("ERROR: failed to upsert batch\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := flush(); err != nil {
+		log.Printf("ERROR: failed to upsert batch\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"strings"
+	"time"
+
+	"film-library/src/internal/film"
+)
+
+func ToRecord(line int, jr *jsonRecord) *Record {
+	rec := &Record{
+		Line: line,
+		Info: film.FilmInfo{
+			Name:        strings.TrimSpace(jr.Name),
+			Description: jr.Description,
+			ReleaseDate: strings.TrimSpace(jr.ReleaseDate),
+			Rating:      jr.Rating,
+		},
+	}
+
+	for _, v := range jr.Actors {
+		rec.Actors = append(rec.Actors, strings.TrimSpace(v))
+	}
+
+	return rec
+}
+
+func ToFilm(fi *film.FilmInfo) *Film {
+	releaseDate, _ := time.Parse(time.DateOnly, fi.ReleaseDate)
+
+	return &Film{
+		Name:        fi.Name,
+		Description: fi.Description,
+		ReleaseDate: releaseDate,
+		Rating:      fi.Rating,
+	}
+}
+
+// ToFilmKey identifies a film of the input the way existing films are
+// matched against it.
+func ToFilmKey(f *Film) string {
+	return strings.ToLower(f.Name) + "|" + f.ReleaseDate.Format(time.DateOnly)
+}
+
+// ToActorNames removes case-insensitive duplicates from names keeping
+// the first spelling.
+func ToActorNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	res := make([]string, 0, len(names))
+	for _, v := range names {
+		key := strings.ToLower(v)
+		if !seen[key] {
+			seen[key] = true
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+func ToFailedReport(rec *Record, message string) *RowReport {
+	return &RowReport{
+		Row:     rec.Line,
+		Status:  StatusFailed,
+		Name:    rec.Info.Name,
+		Message: message,
+	}
+}
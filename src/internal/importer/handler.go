@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+// contentTypeFormats maps request content types to input formats, the
+// format query parameter takes precedence.
+var contentTypeFormats = map[string]string{
+	"text/csv":             FormatCSV,
+	"application/jsonl":    FormatJSONL,
+	"application/x-ndjson": FormatJSONL,
+}
+
+var _ ImportHandler = (*Handler)(nil)
+
+type Handler struct {
+	service ImportService
+	maxSize int64
+}
+
+func NewHandler(is ImportService, maxSize int64) *Handler {
+	return &Handler{
+		service: is,
+		maxSize: maxSize,
+	}
+}
+
+func (h *Handler) ImportFilms(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if len(format) == 0 {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("content-type"))
+		format = contentTypeFormats[mediaType]
+	}
+	if len(format) == 0 {
+		tools.JSON(w, r, http.StatusUnsupportedMediaType, &tools.ErrorMessage{
+			ErrorType: tools.ErrorTypeValidation,
+			Body:      "unsupported content type (expected one of [text/csv, application/x-ndjson])",
+		})
+		return
+	}
+
+	res, err := h.service.ImportFilms(r.Context(), &ImportRequest{
+		Format: format,
+		Body:   http.MaxBytesReader(w, r.Body, h.maxSize),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to import films err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		var mbErr *http.MaxBytesError
+		if errors.As(err, &mbErr) {
+			tools.JSON(w, r, http.StatusRequestEntityTooLarge, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      fmt.Sprintf("upload is larger than %d bytes", h.maxSize),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	if !res.Committed {
+		tools.JSON(w, r, http.StatusBadRequest, res)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
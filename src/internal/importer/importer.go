@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"film-library/src/internal/film"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+const (
+	StatusCreated = "created"
+	StatusUpdated = "updated"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+// actorSeparator splits actor names in the actors column of CSV input.
+const actorSeparator = ";"
+
+// Record is a film read from the import input, Line is the input line it
+// starts on and Err is set when the line could not be parsed.
+type Record struct {
+	Line   int
+	Info   film.FilmInfo
+	Actors []string
+	Err    error
+}
+
+// Film is an imported film, films are matched against existing ones by
+// case-insensitive name and release date.
+type Film struct {
+	ID          int
+	Name        string
+	Description string
+	ReleaseDate time.Time
+	Rating      int
+}
+
+type ImportRepository interface {
+	FindFilm(ctx context.Context, name string, releaseDate time.Time) (*Film, error)
+	AddFilm(ctx context.Context, f *Film) (*Film, error)
+	UpdateFilm(ctx context.Context, f *Film) error
+	// FindActors maps lowercased names to ids of existing actors.
+	FindActors(ctx context.Context, names []string) (map[string]int, error)
+	AddActor(ctx context.Context, name string) (int, error)
+	// AddFilmActors links actors to the film skipping existing links and
+	// returns the number of links added.
+	AddFilmActors(ctx context.Context, filmID int, actorIDs []int) (int, error)
+}
+
+type ImportService interface {
+	ImportFilms(ctx context.Context, req *ImportRequest) (*ImportResponse, error)
+}
+
+type ImportHandler interface {
+	ImportFilms(w http.ResponseWriter, r *http.Request)
+}
+
+type ImportRequest struct {
+	Format string
+	Body   io.Reader
+}
+
+type RowReport struct {
+	Row           int      `json:"row"`
+	Status        string   `json:"status"`
+	FilmID        int      `json:"filmId,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	CreatedActors []string `json:"createdActors,omitempty"`
+	Message       string   `json:"message,omitempty"`
+	// createdActorIDs are recorded in history once the import is committed
+	createdActorIDs []int
+}
+
+// ImportResponse reports the outcome of every input row, nothing is
+// written unless Committed is set.
+type ImportResponse struct {
+	Committed bool         `json:"committed"`
+	Created   int          `json:"created"`
+	Updated   int          `json:"updated"`
+	Skipped   int          `json:"skipped"`
+	Failed    int          `json:"failed"`
+	Rows      []*RowReport `json:"rows"`
+}
@@ -0,0 +1,184 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"film-library/src/internal/tools"
+)
+
+var (
+	ErrRecordInvalid = errors.New("invalid record")
+)
+
+// csvColumns are columns accepted in the CSV header, name and releasedate
+// are required.
+var csvColumns = map[string]struct{}{
+	"name":        {},
+	"description": {},
+	"releasedate": {},
+	"rating":      {},
+	"actors":      {},
+}
+
+// jsonRecord is a line of JSON Lines input.
+type jsonRecord struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	ReleaseDate string   `json:"releasedate"`
+	Rating      int      `json:"rating"`
+	Actors      []string `json:"actors"`
+}
+
+// ParseRecords reads films of the given format, malformed rows are returned
+// with Err set while malformed input as a whole fails with a validation
+// error.
+func ParseRecords(format string, r io.Reader) ([]*Record, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSONL:
+		return parseJSONL(r)
+	}
+
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+func parseCSV(r io.Reader) ([]*Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			ve := &tools.ValidationError{}
+			ve.AddViolation("input empty")
+			return nil, ve
+		}
+
+		var pErr *csv.ParseError
+		if errors.As(err, &pErr) {
+			ve := &tools.ValidationError{}
+			ve.AddViolation("incorrect csv header: " + pErr.Err.Error())
+			return nil, ve
+		}
+
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, v := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(v))
+		columns[header[i]] = i
+	}
+	if vErr := ValidateHeader(header); vErr != nil {
+		return nil, vErr
+	}
+
+	field := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var records []*Record
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var pErr *csv.ParseError
+			if !errors.As(err, &pErr) {
+				return nil, err
+			}
+
+			records = append(records, &Record{
+				Line: pErr.StartLine,
+				Err:  fmt.Errorf("%w: %s", ErrRecordInvalid, pErr.Err.Error()),
+			})
+			continue
+		}
+
+		line, _ := cr.FieldPos(0)
+		rec := &Record{Line: line}
+		rec.Info.Name = field(row, "name")
+		rec.Info.Description = field(row, "description")
+		rec.Info.ReleaseDate = field(row, "releasedate")
+
+		if rating := field(row, "rating"); len(rating) != 0 {
+			rec.Info.Rating, err = strconv.Atoi(rating)
+			if err != nil {
+				rec.Err = fmt.Errorf("%w: incorrect rating, expected integer", ErrRecordInvalid)
+			}
+		}
+
+		for _, v := range strings.Split(field(row, "actors"), actorSeparator) {
+			if v = strings.TrimSpace(v); len(v) != 0 {
+				rec.Actors = append(rec.Actors, v)
+			}
+		}
+
+		records = append(records, rec)
+	}
+
+	if len(records) == 0 {
+		ve := &tools.ValidationError{}
+		ve.AddViolation("input empty")
+		return nil, ve
+	}
+
+	return records, nil
+}
+
+func parseJSONL(r io.Reader) ([]*Record, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var records []*Record
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+
+		var jr jsonRecord
+		if err := json.Unmarshal(b, &jr); err != nil {
+			records = append(records, &Record{
+				Line: line,
+				Err:  fmt.Errorf("%w: %s", ErrRecordInvalid, err.Error()),
+			})
+			continue
+		}
+
+		records = append(records, ToRecord(line, &jr))
+	}
+	if err := sc.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			ve := &tools.ValidationError{}
+			ve.AddViolation("line is longer than 1MB")
+			return nil, ve
+		}
+
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		ve := &tools.ValidationError{}
+		ve.AddViolation("input empty")
+		return nil, ve
+	}
+
+	return records, nil
+}
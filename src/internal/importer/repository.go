@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"film-library/src/internal/db"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrFilmNotExist = errors.New("film does not exist")
+)
+
+var _ ImportRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) FindFilm(ctx context.Context, name string, releaseDate time.Time) (*Film, error) {
+	const op = "importer.Repository.FindFilm"
+
+	const query = `
+		SELECT movie_id, movie_name, movie_description, releasedate, rating
+		FROM movie
+		WHERE LOWER(movie_name) = LOWER($1) AND releasedate = $2 AND deleted_at IS NULL
+		ORDER BY movie_id
+		LIMIT 1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var f Film
+	err = stmt.QueryRowContext(ctx, name, releaseDate).Scan(&f.ID, &f.Name, &f.Description, &f.ReleaseDate, &f.Rating)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &f, nil
+}
+
+func (r *Repository) AddFilm(ctx context.Context, f *Film) (*Film, error) {
+	const op = "importer.Repository.AddFilm"
+
+	const query = `
+		INSERT INTO movie(movie_name, movie_description, releasedate, rating)
+		VALUES ($1, $2, $3, $4) RETURNING movie_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, f.Name, f.Description, f.ReleaseDate, f.Rating).Scan(&f.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return f, nil
+}
+
+func (r *Repository) UpdateFilm(ctx context.Context, f *Film) error {
+	const op = "importer.Repository.UpdateFilm"
+
+	const query = `
+		UPDATE movie SET movie_description = $1, rating = $2
+		WHERE movie_id = $3`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, f.Description, f.Rating, f.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by update\n")
+		return fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) FindActors(ctx context.Context, names []string) (map[string]int, error) {
+	const op = "importer.Repository.FindActors"
+
+	lowered := make([]string, 0, len(names))
+	for _, v := range names {
+		lowered = append(lowered, strings.ToLower(v))
+	}
+
+	// actors sharing a name resolve to the earliest added one
+	const query = `
+		SELECT DISTINCT ON (LOWER(actor_name)) LOWER(actor_name), actor_id
+		FROM actor
+		WHERE LOWER(actor_name) = ANY($1) AND deleted_at IS NULL
+		ORDER BY LOWER(actor_name), actor_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(lowered))
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	res := make(map[string]int, len(names))
+	for rows.Next() {
+		var name string
+		var id int
+		if err := rows.Scan(&name, &id); err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		res[name] = id
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to iterate over rows\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (r *Repository) AddActor(ctx context.Context, name string) (int, error) {
+	const op = "importer.Repository.AddActor"
+
+	const query = `INSERT INTO actor(actor_name) VALUES ($1) RETURNING actor_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var id int
+	if err := stmt.QueryRowContext(ctx, name).Scan(&id); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *Repository) AddFilmActors(ctx context.Context, filmID int, actorIDs []int) (int, error) {
+	const op = "importer.Repository.AddFilmActors"
+
+	const query = `
+		INSERT INTO actor_in_movie(actor_id, movie_id)
+		SELECT UNNEST($2::int[]), $1
+		ON CONFLICT DO NOTHING`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, filmID, pq.Array(actorIDs))
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
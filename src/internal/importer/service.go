@@ -0,0 +1,211 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/history"
+)
+
+// errRowsFailed rolls back an import having failed rows.
+var errRowsFailed = errors.New("import has failed rows")
+
+var _ ImportService = (*Service)(nil)
+
+type Service struct {
+	repo    ImportRepository
+	tx      db.Transactor
+	history history.Recorder
+}
+
+func NewService(ir ImportRepository, tx db.Transactor, hr history.Recorder) *Service {
+	return &Service{
+		repo:    ir,
+		tx:      tx,
+		history: hr,
+	}
+}
+
+// ImportFilms creates or updates films of the input together with their
+// actors in a single transaction, the import is rolled back as a whole
+// when any row fails.
+func (s *Service) ImportFilms(ctx context.Context, req *ImportRequest) (*ImportResponse, error) {
+	const op = "importer.Service.ImportFilms"
+
+	vErr := ValidateFormat(req.Format)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	records, err := ParseRecords(req.Format, req.Body)
+	if err != nil {
+		log.Printf("ERROR: failed to parse import input\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var res *ImportResponse
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		res = &ImportResponse{
+			Rows: make([]*RowReport, 0, len(records)),
+		}
+
+		// seen maps films of the input to rows they first appeared on
+		seen := make(map[string]int)
+		for _, v := range records {
+			row, err := importRecord(ctx, s.repo, v, seen)
+			if err != nil {
+				return err
+			}
+
+			switch row.Status {
+			case StatusCreated:
+				res.Created++
+			case StatusUpdated:
+				res.Updated++
+			case StatusSkipped:
+				res.Skipped++
+			case StatusFailed:
+				res.Failed++
+			}
+			res.Rows = append(res.Rows, row)
+		}
+
+		if res.Failed != 0 {
+			return errRowsFailed
+		}
+
+		return s.record(ctx, res)
+	})
+	if err != nil && !errors.Is(err, errRowsFailed) {
+		log.Printf("ERROR: failed to import films\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	res.Committed = err == nil
+
+	return res, nil
+}
+
+// record keeps versions of films and actors written by the import.
+func (s *Service) record(ctx context.Context, res *ImportResponse) error {
+	for _, v := range res.Rows {
+		for _, id := range v.createdActorIDs {
+			if err := s.history.Record(ctx, history.EntityActor, id, history.ActionCreate); err != nil {
+				log.Printf("ERROR: failed to record actor history\n")
+				return err
+			}
+		}
+
+		var action string
+		switch v.Status {
+		case StatusCreated:
+			action = history.ActionCreate
+		case StatusUpdated:
+			action = history.ActionUpdate
+		default:
+			continue
+		}
+		if err := s.history.Record(ctx, history.EntityFilm, v.FilmID, action); err != nil {
+			log.Printf("ERROR: failed to record film history\n")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func importRecord(ctx context.Context, repo ImportRepository, rec *Record, seen map[string]int) (*RowReport, error) {
+	const op = "importer.importRecord"
+
+	if rec.Err != nil {
+		return ToFailedReport(rec, rec.Err.Error()), nil
+	}
+
+	if vErr := ValidateRecord(rec); vErr != nil {
+		return ToFailedReport(rec, vErr.Error()), nil
+	}
+
+	f := ToFilm(&rec.Info)
+	key := ToFilmKey(f)
+	if line, ok := seen[key]; ok {
+		return &RowReport{
+			Row:     rec.Line,
+			Status:  StatusSkipped,
+			Name:    f.Name,
+			Message: "duplicate of row " + strconv.Itoa(line),
+		}, nil
+	}
+	seen[key] = rec.Line
+
+	row := &RowReport{
+		Row:  rec.Line,
+		Name: f.Name,
+	}
+
+	existing, err := repo.FindFilm(ctx, f.Name, f.ReleaseDate)
+	switch {
+	case errors.Is(err, ErrFilmNotExist):
+		f, err = repo.AddFilm(ctx, f)
+		if err != nil {
+			log.Printf("ERROR: failed to create film record in repository\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		row.Status = StatusCreated
+	case err != nil:
+		log.Printf("ERROR: failed to find film record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	case existing.Description != f.Description || existing.Rating != f.Rating:
+		f.ID = existing.ID
+		if err := repo.UpdateFilm(ctx, f); err != nil {
+			log.Printf("ERROR: failed to update film record in repository\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		row.Status = StatusUpdated
+	default:
+		f.ID = existing.ID
+		row.Status = StatusSkipped
+	}
+	row.FilmID = f.ID
+
+	if len(rec.Actors) == 0 {
+		return row, nil
+	}
+
+	names := ToActorNames(rec.Actors)
+	actors, err := repo.FindActors(ctx, names)
+	if err != nil {
+		log.Printf("ERROR: failed to find actor records in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ids := make([]int, 0, len(names))
+	for _, v := range names {
+		id, ok := actors[strings.ToLower(v)]
+		if !ok {
+			id, err = repo.AddActor(ctx, v)
+			if err != nil {
+				log.Printf("ERROR: failed to create actor record in repository\n")
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			row.CreatedActors = append(row.CreatedActors, v)
+			row.createdActorIDs = append(row.createdActorIDs, id)
+		}
+		ids = append(ids, id)
+	}
+
+	added, err := repo.AddFilmActors(ctx, f.ID, ids)
+	if err != nil {
+		log.Printf("ERROR: failed to add film actors in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if added != 0 && row.Status == StatusSkipped {
+		row.Status = StatusUpdated
+	}
+
+	return row, nil
+}
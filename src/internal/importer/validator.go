@@ -0,0 +1,73 @@
+package importer
+
+import (
+	"slices"
+	"strings"
+
+	"film-library/src/internal/film"
+	"film-library/src/internal/tools"
+)
+
+func ValidateFormat(format string) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if format != FormatCSV && format != FormatJSONL {
+		ve.AddViolation("incorrect format (expected one of [csv, jsonl])")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateHeader(header []string) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	for _, v := range header {
+		if _, ok := csvColumns[v]; !ok {
+			ve.AddViolation("unknown column " + v + " (expected any of [name, description, releasedate, rating, actors])")
+			break
+		}
+	}
+
+	for _, v := range []string{"name", "releasedate"} {
+		if !slices.Contains(header, v) {
+			ve.AddViolation("column " + v + " missing")
+		}
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+// ValidateRecord checks a record with the rules films added through the
+// API follow.
+func ValidateRecord(rec *Record) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if vErr := film.ValidateEmptyFilmInfo(&rec.Info); vErr != nil {
+		ve.AddViolation(vErr.Error())
+	}
+
+	if vErr := film.ValidateFormatFilmInfo(&rec.Info); vErr != nil {
+		ve.AddViolation(vErr.Error())
+	}
+
+	for _, v := range rec.Actors {
+		if len(strings.TrimSpace(v)) == 0 || len(v) > 150 {
+			ve.AddViolation("incorrect actor name, expected non-empty name of at most 150 symbols")
+			break
+		}
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
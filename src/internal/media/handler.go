@@ -0,0 +1,184 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+// formField is the multipart form field carrying the uploaded image.
+const formField = "image"
+
+var _ ImageHandler = (*Handler)(nil)
+
+type Handler struct {
+	service ImageService
+	maxSize int64
+}
+
+func NewHandler(is ImageService, maxSize int64) *Handler {
+	return &Handler{
+		service: is,
+		maxSize: maxSize,
+	}
+}
+
+func (h *Handler) SetFilmPoster(w http.ResponseWriter, r *http.Request) {
+	req := UploadRequest{
+		ID: r.PathValue("id"),
+	}
+	if ok := h.bindImage(w, r, &req); !ok {
+		return
+	}
+
+	res, err := h.service.SetFilmPoster(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to set film poster err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if ok := h.imageError(w, r, err); ok {
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteFilmPoster(w http.ResponseWriter, r *http.Request) {
+	req := ImageIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	err := h.service.DeleteFilmPoster(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete film poster err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrImageNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.OK(w, r)
+}
+
+func (h *Handler) SetActorHeadshot(w http.ResponseWriter, r *http.Request) {
+	req := UploadRequest{
+		ID: r.PathValue("id"),
+	}
+	if ok := h.bindImage(w, r, &req); !ok {
+		return
+	}
+
+	res, err := h.service.SetActorHeadshot(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to set actor headshot err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrActorNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if ok := h.imageError(w, r, err); ok {
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteActorHeadshot(w http.ResponseWriter, r *http.Request) {
+	req := ImageIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	err := h.service.DeleteActorHeadshot(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete actor headshot err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrImageNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.OK(w, r)
+}
+
+// bindImage reads the image from the multipart request body limited to
+// maxSize bytes, on failure the response is written and false returned.
+func (h *Handler) bindImage(w http.ResponseWriter, r *http.Request, req *UploadRequest) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize)
+
+	file, _, err := r.FormFile(formField)
+	if err == nil {
+		defer file.Close()
+		req.Data, err = io.ReadAll(file)
+	}
+	if err != nil {
+		log.Printf("ERROR: failed to read uploaded image err=%s\n", err.Error())
+
+		var mbErr *http.MaxBytesError
+		if errors.As(err, &mbErr) {
+			tools.JSON(w, r, http.StatusRequestEntityTooLarge, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      fmt.Sprintf("upload is larger than %d bytes", h.maxSize),
+			})
+			return false
+		}
+
+		tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+			ErrorType: tools.ErrorTypeValidation,
+			Body:      "expected multipart form with '" + formField + "' file",
+		})
+		return false
+	}
+
+	return true
+}
+
+// imageError writes the response for errors caused by the uploaded image
+// itself and reports whether err was one of them.
+func (h *Handler) imageError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var body string
+	switch {
+	case errors.Is(err, ErrTypeUnsupported):
+		tools.JSON(w, r, http.StatusUnsupportedMediaType, &tools.ErrorMessage{
+			ErrorType: tools.ErrorTypeValidation,
+			Body:      "unsupported image type, expected one of [image/jpeg, image/png]",
+		})
+		return true
+	case errors.Is(err, ErrImageInvalid):
+		body = "image can not be decoded"
+	case errors.Is(err, ErrImageTooLarge):
+		body = fmt.Sprintf("image width and height must not exceed %d pixels", maxImageSide)
+	default:
+		return false
+	}
+
+	tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+		ErrorType: tools.ErrorTypeValidation,
+		Body:      body,
+	})
+	return true
+}
@@ -0,0 +1,69 @@
+package media
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+const (
+	SizeOriginal = "original"
+	SizeSmall    = "small"
+	SizeMedium   = "medium"
+	SizeLarge    = "large"
+)
+
+// thumbnailWidths are widths in pixels thumbnails are scaled down to,
+// heights follow the aspect ratio of the uploaded image.
+var thumbnailWidths = map[string]int{
+	SizeSmall:  185,
+	SizeMedium: 342,
+	SizeLarge:  780,
+}
+
+// Image is an uploaded image stored under the Key prefix together with its
+// thumbnails, URLs maps size names to public URLs of the files.
+type Image struct {
+	Key  string
+	URLs map[string]string
+}
+
+// Storage keeps uploaded files, keys are slash separated paths.
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, r io.Reader) error
+	// Delete removes every file stored under the prefix.
+	Delete(ctx context.Context, prefix string) error
+	URL(key string) string
+}
+
+// ImageRepository binds images to films and actors, every setter returns
+// the key of the replaced image or an empty string.
+type ImageRepository interface {
+	SetFilmPoster(ctx context.Context, id int, img *Image) (string, error)
+	DeleteFilmPoster(ctx context.Context, id int) (string, error)
+	SetActorHeadshot(ctx context.Context, id int, img *Image) (string, error)
+	DeleteActorHeadshot(ctx context.Context, id int) (string, error)
+}
+
+type ImageService interface {
+	SetFilmPoster(ctx context.Context, req *UploadRequest) (map[string]string, error)
+	DeleteFilmPoster(ctx context.Context, req *ImageIdRequest) error
+	SetActorHeadshot(ctx context.Context, req *UploadRequest) (map[string]string, error)
+	DeleteActorHeadshot(ctx context.Context, req *ImageIdRequest) error
+}
+
+type ImageHandler interface {
+	SetFilmPoster(w http.ResponseWriter, r *http.Request)
+	DeleteFilmPoster(w http.ResponseWriter, r *http.Request)
+	SetActorHeadshot(w http.ResponseWriter, r *http.Request)
+	DeleteActorHeadshot(w http.ResponseWriter, r *http.Request)
+}
+
+type UploadRequest struct {
+	ID   string
+	Data []byte
+}
+
+type ImageIdRequest struct {
+	ID string
+}
@@ -0,0 +1,157 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"film-library/src/internal/db"
+)
+
+var (
+	ErrFilmNotExist  = errors.New("film does not exist")
+	ErrActorNotExist = errors.New("actor does not exist")
+	ErrImageNotExist = errors.New("image does not exist")
+)
+
+var _ ImageRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) SetFilmPoster(ctx context.Context, id int, img *Image) (string, error) {
+	const op = "media.Repository.SetFilmPoster"
+
+	const query = `
+		WITH old AS (SELECT movie_id, poster_key FROM movie WHERE movie_id = $1 AND deleted_at IS NULL FOR UPDATE)
+		UPDATE movie m SET poster_key = $2, poster_urls = $3
+		FROM old WHERE m.movie_id = old.movie_id
+		RETURNING COALESCE(old.poster_key, '')`
+
+	oldKey, err := r.setImage(ctx, query, id, img)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: film with id=%d does not exist\n", id)
+			return "", fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return oldKey, nil
+}
+
+func (r *Repository) DeleteFilmPoster(ctx context.Context, id int) (string, error) {
+	const op = "media.Repository.DeleteFilmPoster"
+
+	const query = `
+		WITH old AS (SELECT movie_id, poster_key FROM movie WHERE movie_id = $1 AND deleted_at IS NULL FOR UPDATE)
+		UPDATE movie m SET poster_key = NULL, poster_urls = NULL
+		FROM old WHERE m.movie_id = old.movie_id AND old.poster_key IS NOT NULL
+		RETURNING old.poster_key`
+
+	oldKey, err := r.deleteImage(ctx, query, id)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return oldKey, nil
+}
+
+func (r *Repository) SetActorHeadshot(ctx context.Context, id int, img *Image) (string, error) {
+	const op = "media.Repository.SetActorHeadshot"
+
+	const query = `
+		WITH old AS (SELECT actor_id, headshot_key FROM actor WHERE actor_id = $1 AND deleted_at IS NULL FOR UPDATE)
+		UPDATE actor a SET headshot_key = $2, headshot_urls = $3
+		FROM old WHERE a.actor_id = old.actor_id
+		RETURNING COALESCE(old.headshot_key, '')`
+
+	oldKey, err := r.setImage(ctx, query, id, img)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: actor with id=%d does not exist\n", id)
+			return "", fmt.Errorf("%s: %w", op, ErrActorNotExist)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return oldKey, nil
+}
+
+func (r *Repository) DeleteActorHeadshot(ctx context.Context, id int) (string, error) {
+	const op = "media.Repository.DeleteActorHeadshot"
+
+	const query = `
+		WITH old AS (SELECT actor_id, headshot_key FROM actor WHERE actor_id = $1 AND deleted_at IS NULL FOR UPDATE)
+		UPDATE actor a SET headshot_key = NULL, headshot_urls = NULL
+		FROM old WHERE a.actor_id = old.actor_id AND old.headshot_key IS NOT NULL
+		RETURNING old.headshot_key`
+
+	oldKey, err := r.deleteImage(ctx, query, id)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return oldKey, nil
+}
+
+func (r *Repository) setImage(ctx context.Context, query string, id int, img *Image) (string, error) {
+	urls, err := json.Marshal(img.URLs)
+	if err != nil {
+		log.Printf("ERROR: failed to encode image urls\n")
+		return "", err
+	}
+
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return "", err
+	}
+	defer stmt.Close()
+
+	var oldKey string
+	err = stmt.QueryRowContext(ctx, id, img.Key, urls).Scan(&oldKey)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: failed to execute query\n")
+		}
+		return "", err
+	}
+
+	return oldKey, nil
+}
+
+func (r *Repository) deleteImage(ctx context.Context, query string, id int) (string, error) {
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return "", err
+	}
+	defer stmt.Close()
+
+	var oldKey string
+	err = stmt.QueryRowContext(ctx, id).Scan(&oldKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: no image to delete\n")
+			return "", ErrImageNotExist
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return "", err
+	}
+
+	return oldKey, nil
+}
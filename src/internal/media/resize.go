@@ -0,0 +1,57 @@
+package media
+
+import (
+	"image"
+	"image/draw"
+)
+
+// ToRGBA copies img into an RGBA image with bounds starting at (0, 0).
+func ToRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+
+	return dst
+}
+
+// Resize scales src down to the given width keeping the aspect ratio, each
+// destination pixel is the average of the source pixels it covers. Images
+// not wider than width are returned as is.
+func Resize(src *image.RGBA, width int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if sw <= width {
+		return src
+	}
+	height := max(sh*width/sw, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * sh / height
+		y1 := max((y+1)*sh/height, y0+1)
+
+		for x := 0; x < width; x++ {
+			x0 := x * sw / width
+			x1 := max((x+1)*sw/width, x0+1)
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					sum[0] += int(src.Pix[i])
+					sum[1] += int(src.Pix[i+1])
+					sum[2] += int(src.Pix[i+2])
+					sum[3] += int(src.Pix[i+3])
+					i += 4
+				}
+			}
+
+			n := (x1 - x0) * (y1 - y0)
+			j := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[j+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,229 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// maxImageSide bounds width and height of uploaded images, so that a
+	// small file can not decode into a huge bitmap.
+	maxImageSide = 8000
+	jpegQuality  = 85
+)
+
+var (
+	ErrIdInvalid       = errors.New("invalid id")
+	ErrTypeUnsupported = errors.New("unsupported image type")
+	ErrImageInvalid    = errors.New("image can not be decoded")
+	ErrImageTooLarge   = errors.New("image dimensions are too large")
+)
+
+// imageExtensions lists accepted content types with the file extensions
+// used to store them.
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+}
+
+var _ ImageService = (*Service)(nil)
+
+type Service struct {
+	repo    ImageRepository
+	storage Storage
+}
+
+func NewService(ir ImageRepository, st Storage) *Service {
+	return &Service{
+		repo:    ir,
+		storage: st,
+	}
+}
+
+func (s *Service) SetFilmPoster(ctx context.Context, req *UploadRequest) (map[string]string, error) {
+	const op = "media.Service.SetFilmPoster"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	urls, err := s.setImage(ctx, fmt.Sprintf("films/%d", id), req.Data, func(img *Image) (string, error) {
+		return s.repo.SetFilmPoster(ctx, int(id), img)
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to set film poster\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return urls, nil
+}
+
+func (s *Service) DeleteFilmPoster(ctx context.Context, req *ImageIdRequest) error {
+	const op = "media.Service.DeleteFilmPoster"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	oldKey, err := s.repo.DeleteFilmPoster(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to delete film poster in repository\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.discard(ctx, oldKey)
+
+	return nil
+}
+
+func (s *Service) SetActorHeadshot(ctx context.Context, req *UploadRequest) (map[string]string, error) {
+	const op = "media.Service.SetActorHeadshot"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	urls, err := s.setImage(ctx, fmt.Sprintf("actors/%d", id), req.Data, func(img *Image) (string, error) {
+		return s.repo.SetActorHeadshot(ctx, int(id), img)
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to set actor headshot\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return urls, nil
+}
+
+func (s *Service) DeleteActorHeadshot(ctx context.Context, req *ImageIdRequest) error {
+	const op = "media.Service.DeleteActorHeadshot"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	oldKey, err := s.repo.DeleteActorHeadshot(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to delete actor headshot in repository\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.discard(ctx, oldKey)
+
+	return nil
+}
+
+// setImage stores data with its thumbnails under a fresh key below dir and
+// binds it using set, files of the replaced image are removed afterwards.
+func (s *Service) setImage(ctx context.Context, dir string, data []byte, set func(img *Image) (string, error)) (map[string]string, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		log.Printf("ERROR: unsupported content type %s\n", contentType)
+		return nil, ErrTypeUnsupported
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		log.Printf("ERROR: failed to decode image config\n")
+		return nil, ErrImageInvalid
+	}
+	if cfg.Width > maxImageSide || cfg.Height > maxImageSide {
+		log.Printf("ERROR: image is %dx%d pixels\n", cfg.Width, cfg.Height)
+		return nil, ErrImageTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		log.Printf("ERROR: failed to decode image\n")
+		return nil, ErrImageInvalid
+	}
+
+	token, err := newToken()
+	if err != nil {
+		log.Printf("ERROR: failed to generate image key\n")
+		return nil, err
+	}
+
+	img := &Image{
+		Key:  dir + "/" + token,
+		URLs: make(map[string]string, len(thumbnailWidths)+1),
+	}
+
+	key := img.Key + "/" + SizeOriginal + "." + ext
+	if err := s.storage.Put(ctx, key, contentType, bytes.NewReader(data)); err != nil {
+		log.Printf("ERROR: failed to store original image\n")
+		s.discard(ctx, img.Key)
+		return nil, err
+	}
+	img.URLs[SizeOriginal] = s.storage.URL(key)
+
+	src := ToRGBA(decoded)
+	for size, width := range thumbnailWidths {
+		var buf bytes.Buffer
+		thumb := Resize(src, width)
+		if ext == "png" {
+			err = png.Encode(&buf, thumb)
+		} else {
+			err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: jpegQuality})
+		}
+		if err != nil {
+			log.Printf("ERROR: failed to encode %s thumbnail\n", size)
+			s.discard(ctx, img.Key)
+			return nil, err
+		}
+
+		key := img.Key + "/" + size + "." + ext
+		if err := s.storage.Put(ctx, key, contentType, &buf); err != nil {
+			log.Printf("ERROR: failed to store %s thumbnail\n", size)
+			s.discard(ctx, img.Key)
+			return nil, err
+		}
+		img.URLs[size] = s.storage.URL(key)
+	}
+
+	oldKey, err := set(img)
+	if err != nil {
+		s.discard(ctx, img.Key)
+		return nil, err
+	}
+	s.discard(ctx, oldKey)
+
+	return img.URLs, nil
+}
+
+// discard removes stored files of an image, failures only leave orphaned
+// files behind and are logged.
+func (s *Service) discard(ctx context.Context, key string) {
+	if len(key) == 0 {
+		return
+	}
+
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("ERROR: failed to delete stored image key=%s err=%s\n", key, err.Error())
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
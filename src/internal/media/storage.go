@@ -0,0 +1,56 @@
+package media
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var _ Storage = (*LocalStorage)(nil)
+
+// LocalStorage keeps files in a directory of the local filesystem, the
+// directory is expected to be served under baseURL.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStorage(dir, baseURL string) *LocalStorage {
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key, contentType string, r io.Reader) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, prefix string) error {
+	return os.RemoveAll(s.path(prefix))
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(filepath.Clean("/"+key)))
+}
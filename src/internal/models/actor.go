@@ -2,59 +2,146 @@ package models
 
 import (
 	"context"
+	"encoding/xml"
 	"net/http"
 	"time"
+
+	"film-library/src/internal/tools"
 )
 
 type Actor struct {
-	ID       int       `json:"id"`
-	Name     string    `json:"name"`
-	Sex      string    `json:"sex"`
-	Birthday time.Time `json:"birthday"`
-	Films    []string  `json:"films"`
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	Sex      string            `json:"sex"`
+	Birthday time.Time         `json:"birthday"`
+	Films    []*FilmShort      `json:"films"`
+	Headshot map[string]string `json:"headshot"`
+	Version  int               `json:"version"`
+}
+
+type FilmShort struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
 }
 
 type ActorRepository interface {
 	Get(ctx context.Context, id int) (*Actor, error)
+	// GetVersion locks the actor until the end of the transaction.
+	GetVersion(ctx context.Context, id int) (int, error)
 	Add(ctx context.Context, a *Actor) (*Actor, error)
 	Delete(ctx context.Context, id int) error
 	Update(ctx context.Context, a *Actor) error
-	GetAll(ctx context.Context) ([]*Actor, error)
+	GetAll(ctx context.Context, q *Query) ([]*Actor, error)
+	Count(ctx context.Context) (int, error)
+	GetFilmography(ctx context.Context, id int) ([]*FilmCredit, error)
 }
 
 type ActorService interface {
-	GetAll(ctx context.Context) ([]*ActorResponse, error)
+	GetAll(ctx context.Context, req *GetActorsRequest) (*GetActorsResponse, error)
 	Add(ctx context.Context, req *ActorInfo) (*ActorResponse, error)
+	AddPerson(ctx context.Context, req *ActorInfo) (*ActorResponse, error)
 	Get(ctx context.Context, req *ActorIdRequest) (*ActorResponse, error)
 	Update(ctx context.Context, req *ActorIdInfoRequest) (*ActorResponse, error)
+	Patch(ctx context.Context, req *ActorPatchRequest) (*ActorResponse, error)
 	Delete(ctx context.Context, req *ActorIdRequest) (*ActorResponse, error)
+	GetFilmography(ctx context.Context, req *ActorIdRequest) (*FilmographyResponse, error)
 }
 
 type ActorHandler interface {
 	GetAll(w http.ResponseWriter, r *http.Request)
 	Add(w http.ResponseWriter, r *http.Request)
+	AddPerson(w http.ResponseWriter, r *http.Request)
 	Get(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
+	Patch(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	GetFilmography(w http.ResponseWriter, r *http.Request)
+}
+
+// FilmCredit is a single film a person took part in, acting credits
+// are reported with the "actor" role and carry the characters played.
+type FilmCredit struct {
+	ID          int
+	Name        string
+	ReleaseDate time.Time
+	Role        string
+	Characters  []string
+	Billing     int
+}
+
+type Query struct {
+	Limit    int
+	Cursor   *tools.Cursor
+	Backward bool
+}
+
+type GetActorsRequest struct {
+	LimitQuery  string
+	AfterQuery  string
+	BeforeQuery string
+	TotalQuery  string
+}
+
+type GetActorsResponse struct {
+	XMLName    xml.Name         `json:"-" xml:"actors"`
+	Actors     []*ActorResponse `json:"actors" xml:"actor"`
+	NextCursor string           `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+	PrevCursor string           `json:"prevCursor,omitempty" xml:"prevCursor,omitempty"`
+	Total      *int             `json:"total,omitempty" xml:"total,omitempty"`
 }
 
 type ActorInfo struct {
-	Name     string `json:"name"`
-	Sex      string `json:"sex"`
-	Birthday string `json:"birthday"`
+	Name     string `json:"name" xml:"name"`
+	Sex      string `json:"sex" xml:"sex"`
+	Birthday string `json:"birthday" xml:"birthday"`
 }
 
 type ActorResponse struct {
-	ID    int       `json:"id"`
-	Info  ActorInfo `json:"info"`
-	Films []string  `json:"films,omitempty"`
+	XMLName  xml.Name             `json:"-" xml:"actor"`
+	ID       int                  `json:"id" xml:"id"`
+	Info     ActorInfo            `json:"info" xml:"info"`
+	Films    []*FilmShortResponse `json:"films,omitempty" xml:"film,omitempty"`
+	Headshot tools.StringMap      `json:"headshot,omitempty" xml:"headshot,omitempty"`
+	Version  int                  `json:"-" xml:"-"`
+}
+
+type FilmShortResponse struct {
+	ID   int    `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
 }
 
+// ActorIdRequest identifies an actor, IfMatch is set only where the actor
+// is changed.
 type ActorIdRequest struct {
-	ID string
+	ID      string
+	IfMatch string
 }
 
 type ActorIdInfoRequest struct {
-	ID   string
-	Info ActorInfo
+	ID      string
+	Info    ActorInfo
+	IfMatch string
+}
+
+// ActorPatchRequest holds a patch document of MediaType, one of
+// tools.MediaTypeMergePatch and tools.MediaTypeJSONPatch.
+type ActorPatchRequest struct {
+	ID        string
+	MediaType string
+	Patch     []byte
+	IfMatch   string
+}
+
+type FilmographyResponse struct {
+	ID      int                              `json:"id"`
+	Name    string                           `json:"name"`
+	Credits map[string][]*FilmCreditResponse `json:"credits"`
+}
+
+type FilmCreditResponse struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	ReleaseDate string   `json:"releasedate"`
+	Characters  []string `json:"characters,omitempty"`
+	Billing     int      `json:"billing,omitempty"`
 }
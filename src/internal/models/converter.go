@@ -1,39 +1,43 @@
 package models
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"film-library/src/internal/tools"
 )
 
-func ToQueryableObject(a *Actor) *tools.QueryableObject {
-	qo := tools.NewQueryableObject()
-
-	if len(a.Name) != 0 {
-		qo.Add("actor_name", a.Name)
-	}
-
-	if len(a.Sex) != 0 {
-		qo.Add("sex", a.Sex)
+func ToActorResponse(a *Actor) *ActorResponse {
+	res := &ActorResponse{
+		ID: int(a.ID),
+		Info: ActorInfo{
+			Name: a.Name,
+			Sex:  a.Sex,
+		},
+		Films:    ToFilmsShortResponse(a.Films),
+		Headshot: a.Headshot,
+		Version:  a.Version,
 	}
 
+	// actors created by film imports may lack a birthday
 	if !a.Birthday.IsZero() {
-		qo.Add("birthday", a.Birthday)
+		res.Info.Birthday = a.Birthday.Format(time.DateOnly)
 	}
 
-	return qo
+	return res
 }
 
-func ToActorResponse(a *Actor) *ActorResponse {
-	return &ActorResponse{
-		ID: int(a.ID),
-		Info: ActorInfo{
-			Name:     a.Name,
-			Sex:      a.Sex,
-			Birthday: a.Birthday.Format(time.DateOnly),
-		},
-		Films: a.Films,
+func ToFilmsShortResponse(f []*FilmShort) []*FilmShortResponse {
+	res := make([]*FilmShortResponse, 0, len(f))
+	for _, v := range f {
+		res = append(res, &FilmShortResponse{
+			ID:   v.ID,
+			Name: v.Name,
+		})
 	}
+
+	return res
 }
 
 func ToActor(ai *ActorInfo) *Actor {
@@ -45,3 +49,89 @@ func ToActor(ai *ActorInfo) *Actor {
 		Birthday: birthday,
 	}
 }
+
+// ToQueryConditions applies keyset position, ordering and limit of q to
+// the actors query.
+func ToQueryConditions(q *Query, qb *tools.SelectBuilder) *tools.SelectBuilder {
+	cmp, order := ">", "ASC"
+	if q.Backward {
+		cmp, order = "<", "DESC"
+	}
+
+	qb.Where(tools.Expr("a.deleted_at IS NULL"))
+	if q.Cursor != nil {
+		qb.Where(tools.Expr("a.actor_id "+cmp+" ?", q.Cursor.ID))
+	}
+
+	return qb.OrderBy("a.actor_id " + order).Limit(q.Limit)
+}
+
+func ToQuery(req *GetActorsRequest) *Query {
+	limit, cursor, backward := tools.ToPageQuery(req.LimitQuery, req.AfterQuery, req.BeforeQuery)
+
+	return &Query{
+		Limit:    limit,
+		Cursor:   cursor,
+		Backward: backward,
+	}
+}
+
+func ToActorCursor(a *Actor) *tools.Cursor {
+	return &tools.Cursor{
+		ID: a.ID,
+	}
+}
+
+func ToFilmographyResponse(a *Actor, credits []*FilmCredit) *FilmographyResponse {
+	res := &FilmographyResponse{
+		ID:      a.ID,
+		Name:    a.Name,
+		Credits: make(map[string][]*FilmCreditResponse),
+	}
+
+	for _, v := range credits {
+		res.Credits[v.Role] = append(res.Credits[v.Role], &FilmCreditResponse{
+			ID:          v.ID,
+			Name:        v.Name,
+			ReleaseDate: v.ReleaseDate.Format(time.DateOnly),
+			Characters:  v.Characters,
+			Billing:     v.Billing,
+		})
+	}
+
+	return res
+}
+
+var actorCSVHeader = []string{"id", "name", "sex", "birthday", "films"}
+
+// MarshalCSV renders the actor as a single record, film names are
+// separated by ";".
+func (res *ActorResponse) MarshalCSV() ([]string, [][]string) {
+	return actorCSVHeader, [][]string{ToActorCSVRecord(res)}
+}
+
+// MarshalCSV renders a record per actor, cursors are sent in the Link header
+// instead.
+func (res *GetActorsResponse) MarshalCSV() ([]string, [][]string) {
+	records := make([][]string, 0, len(res.Actors))
+	for _, v := range res.Actors {
+		records = append(records, ToActorCSVRecord(v))
+	}
+
+	return actorCSVHeader, records
+}
+
+func ToActorCSVRecord(res *ActorResponse) []string {
+	films := make([]string, 0, len(res.Films))
+	for _, v := range res.Films {
+		films = append(films, v.Name)
+	}
+
+	return []string{
+		strconv.Itoa(res.ID),
+		res.Info.Name,
+		res.Info.Sex,
+		res.Info.Birthday,
+		strings.Join(films, ";"),
+	}
+}
@@ -18,14 +18,35 @@ func NewHandler(as ActorService) *Handler {
 }
 
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	res, err := h.service.GetAll(r.Context())
+	mediaType := tools.Accept(w, r, tools.TabularOffers)
+	if len(mediaType) == 0 {
+		return
+	}
+
+	res, err := h.service.GetAll(r.Context(), &GetActorsRequest{
+		LimitQuery:  r.URL.Query().Get("limit"),
+		AfterQuery:  r.URL.Query().Get("after"),
+		BeforeQuery: r.URL.Query().Get("before"),
+		TotalQuery:  r.URL.Query().Get("total"),
+	})
 	if err != nil {
 		log.Printf("ERROR: can't get actors err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
 		tools.InternalServerError(w, r)
 		return
 	}
 
-	tools.JSON(w, r, http.StatusOK, res)
+	tools.SetPageLinks(w, r, res.NextCursor, res.PrevCursor)
+	tools.Render(w, r, mediaType, http.StatusOK, res)
 }
 
 func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
@@ -54,7 +75,38 @@ func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
 	tools.JSON(w, r, http.StatusOK, res)
 }
 
+func (h *Handler) AddPerson(w http.ResponseWriter, r *http.Request) {
+	var req ActorInfo
+	ok := tools.BindJSON(w, r, &req)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.AddPerson(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: can't to add person err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	mediaType := tools.Accept(w, r, tools.TabularOffers)
+	if len(mediaType) == 0 {
+		return
+	}
+
 	req := ActorIdRequest{
 		ID: r.PathValue("id"),
 	}
@@ -71,12 +123,19 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tools.JSON(w, r, http.StatusOK, res)
+	// the body is negotiated and only shown to signed in callers
+	w.Header().Set("vary", "Accept, Cookie")
+	if tools.NotModified(w, r, tools.VariantETag(res.Version, tools.MediaTypeVariant(mediaType))) {
+		return
+	}
+
+	tools.Render(w, r, mediaType, http.StatusOK, res)
 }
 
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	req := ActorIdInfoRequest{
-		ID: r.PathValue("id"),
+		ID:      r.PathValue("id"),
+		IfMatch: r.Header.Get("if-match"),
 	}
 
 	ok := tools.BindJSON(w, r, &req.Info)
@@ -93,6 +152,11 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, ErrVersionMismatch) {
+			tools.PreconditionFailed(w, r)
+			return
+		}
+
 		var ve *tools.ValidationError
 		if errors.As(err, &ve) {
 			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
@@ -102,10 +166,60 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if errors.Is(err, ErrEmptyUpdate) {
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	w.Header().Set("etag", tools.ETag(res.Version))
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Patch(w http.ResponseWriter, r *http.Request) {
+	mediaType, patch, ok := tools.BindPatch(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Patch(r.Context(), &ActorPatchRequest{
+		ID:        r.PathValue("id"),
+		MediaType: mediaType,
+		Patch:     patch,
+		IfMatch:   r.Header.Get("if-match"),
+	})
+	if err != nil {
+		log.Printf("ERROR: can't patch actor err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrActorNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrVersionMismatch) {
+			tools.PreconditionFailed(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, tools.ErrPatchInvalid) {
 			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
 				ErrorType: tools.ErrorTypeValidation,
-				Body:      "empty update",
+				Body:      "invalid patch document",
+			})
+			return
+		}
+
+		if errors.Is(err, tools.ErrPatchConflict) {
+			tools.JSON(w, r, http.StatusConflict, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "patch does not apply to the actor",
 			})
 			return
 		}
@@ -114,12 +228,14 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("etag", tools.ETag(res.Version))
 	tools.JSON(w, r, http.StatusOK, res)
 }
 
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	req := ActorIdRequest{
-		ID: r.PathValue("id"),
+		ID:      r.PathValue("id"),
+		IfMatch: r.Header.Get("if-match"),
 	}
 
 	res, err := h.service.Delete(r.Context(), &req)
@@ -130,6 +246,31 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, ErrVersionMismatch) {
+			tools.PreconditionFailed(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetFilmography(w http.ResponseWriter, r *http.Request) {
+	req := ActorIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.GetFilmography(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: can't get filmography err=%s\n", err.Error())
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrActorNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
 		tools.InternalServerError(w, r)
 		return
 	}
@@ -3,19 +3,31 @@ package models
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 
 	"film-library/src/internal/db"
+	"film-library/src/internal/tools"
+	"github.com/lib/pq"
 )
 
 var (
 	ErrActorNotExist = errors.New("actor does not exist")
-	ErrEmptyUpdate   = errors.New("no updates to apply")
 )
 
+// filmListColumn selects films of actor a in release order as a JSON
+// array of {id, name} objects.
+const filmListColumn = `
+	COALESCE((
+		SELECT json_agg(json_build_object('id', m.movie_id, 'name', m.movie_name)
+			ORDER BY m.releasedate, m.movie_id)
+		FROM actor_in_movie am
+		INNER JOIN movie m USING (movie_id)
+		WHERE am.actor_id = a.actor_id AND m.deleted_at IS NULL
+	), '[]') film_list`
+
 var _ ActorRepository = (*Repository)(nil)
 
 type Repository struct {
@@ -32,13 +44,10 @@ func (r *Repository) Get(ctx context.Context, id int) (*Actor, error) {
 	const op = "actor.Repository.Get"
 
 	const query = `
-		SELECT a.actor_id, a.actor_name, a.sex, a.birthday,
-			STRING_AGG (m.movie_name, ';') movie_list
-		FROM actor a 
-		LEFT JOIN actor_in_movie am USING (actor_id)
-		LEFT JOIN movie m USING (movie_id)
-		WHERE a.actor_id = $1
-		GROUP BY a.actor_id`
+		SELECT a.actor_id, a.actor_name, a.sex, a.birthday, ` + filmListColumn + `,
+			COALESCE(a.headshot_urls, '{}'), a.version
+		FROM actor a
+		WHERE a.actor_id = $1 AND a.deleted_at IS NULL`
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -47,8 +56,9 @@ func (r *Repository) Get(ctx context.Context, id int) (*Actor, error) {
 	defer stmt.Close()
 
 	var a Actor
-	var filmString sql.NullString
-	err = stmt.QueryRowContext(ctx, id).Scan(&a.ID, &a.Name, &a.Sex, &a.Birthday, &filmString)
+	var birthday sql.NullTime
+	var filmList, headshotURLs []byte
+	err = stmt.QueryRowContext(ctx, id).Scan(&a.ID, &a.Name, &a.Sex, &birthday, &filmList, &headshotURLs, &a.Version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Printf("ERROR: actor with id=%d does not exist\n", id)
@@ -58,13 +68,45 @@ func (r *Repository) Get(ctx context.Context, id int) (*Actor, error) {
 		log.Printf("ERROR: failed to execute query\n")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	if len(filmString.String) != 0 {
-		a.Films = strings.Split(filmString.String, ";")
+	a.Birthday = birthday.Time
+	if err := json.Unmarshal(filmList, &a.Films); err != nil {
+		log.Printf("ERROR: failed to decode actor films\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := json.Unmarshal(headshotURLs, &a.Headshot); err != nil {
+		log.Printf("ERROR: failed to decode actor headshot\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return &a, nil
 }
 
+func (r *Repository) GetVersion(ctx context.Context, id int) (int, error) {
+	const op = "actor.Repository.GetVersion"
+
+	const query = `SELECT version FROM actor WHERE actor_id = $1 AND deleted_at IS NULL FOR UPDATE`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var version int
+	err = stmt.QueryRowContext(ctx, id).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: actor with id=%d does not exist\n", id)
+			return 0, fmt.Errorf("%s: %w", op, ErrActorNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return version, nil
+}
+
 func (r *Repository) Add(ctx context.Context, a *Actor) (*Actor, error) {
 	const op = "actor.Repository.Add"
 
@@ -78,7 +120,8 @@ func (r *Repository) Add(ctx context.Context, a *Actor) (*Actor, error) {
 	}
 	defer stmt.Close()
 
-	err = stmt.QueryRowContext(ctx, a.Name, a.Sex, a.Birthday).Scan(&a.ID)
+	birthday := sql.NullTime{Time: a.Birthday, Valid: !a.Birthday.IsZero()}
+	err = stmt.QueryRowContext(ctx, a.Name, a.Sex, birthday).Scan(&a.ID)
 	if err != nil {
 		log.Printf("ERROR: failed to execute query\n")
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -90,7 +133,7 @@ func (r *Repository) Add(ctx context.Context, a *Actor) (*Actor, error) {
 func (r *Repository) Delete(ctx context.Context, id int) error {
 	const op = "actor.Repository.Delete"
 
-	const query = `DELETE FROM actor WHERE actor_id = $1`
+	const query = `UPDATE actor SET deleted_at = now() WHERE actor_id = $1 AND deleted_at IS NULL`
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -120,14 +163,9 @@ func (r *Repository) Delete(ctx context.Context, id int) error {
 func (r *Repository) Update(ctx context.Context, a *Actor) error {
 	const op = "actor.Repository.UpdateActor"
 
-	qo := ToQueryableObject(a)
-	if qo.IsEmpty() {
-		log.Print("ERROR: no updates to apply\n")
-		return fmt.Errorf("%s: %w", op, ErrEmptyUpdate)
-	}
-
-	query := `UPDATE actor SET ` + qo.Args(1) +
-		` WHERE actor_id = ` + fmt.Sprintf("$%d", qo.Len()+1)
+	const query = `
+		UPDATE actor SET actor_name = $1, sex = $2, birthday = $3
+		WHERE actor_id = $4 AND deleted_at IS NULL`
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -135,9 +173,8 @@ func (r *Repository) Update(ctx context.Context, a *Actor) error {
 	}
 	defer stmt.Close()
 
-	values := qo.Values()
-	values = append(values, a.ID)
-	res, err := stmt.ExecContext(ctx, values...)
+	birthday := sql.NullTime{Time: a.Birthday, Valid: !a.Birthday.IsZero()}
+	res, err := stmt.ExecContext(ctx, a.Name, a.Sex, birthday, a.ID)
 	if err != nil {
 		log.Printf("ERROR: failed to execute query\n")
 		return fmt.Errorf("%s: %w", op, err)
@@ -156,16 +193,14 @@ func (r *Repository) Update(ctx context.Context, a *Actor) error {
 	return nil
 }
 
-func (r *Repository) GetAll(ctx context.Context) ([]*Actor, error) {
+func (r *Repository) GetAll(ctx context.Context, q *Query) ([]*Actor, error) {
 	const op = "actor.Repository.GetAll"
 
-	const query = `
-		SELECT a.actor_id, a.actor_name, a.sex, a.birthday,
-    		STRING_AGG (m.movie_name, ';') movie_list
-		FROM actor a
-		LEFT JOIN actor_in_movie am USING (actor_id)
-		LEFT JOIN movie m USING (movie_id)
-		GROUP BY a.actor_id`
+	qb := tools.NewSelectBuilder(`
+		SELECT a.actor_id, a.actor_name, a.sex, a.birthday, ` + filmListColumn + `,
+			COALESCE(a.headshot_urls, '{}')
+		FROM actor a`)
+	query, args := ToQueryConditions(q, qb).Build()
 	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		log.Printf("ERROR: failed to prepare query\n")
@@ -173,24 +208,32 @@ func (r *Repository) GetAll(ctx context.Context) ([]*Actor, error) {
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.QueryContext(ctx)
+	rows, err := stmt.QueryContext(ctx, args...)
 	if err != nil {
 		log.Printf("ERROR: failed to execute query\n")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
 	var actors []*Actor
 
 	for rows.Next() {
 		var a Actor
-		var filmString sql.NullString
-		err := rows.Scan(&a.ID, &a.Name, &a.Sex, &a.Birthday, &filmString)
+		var birthday sql.NullTime
+		var filmList, headshotURLs []byte
+		err := rows.Scan(&a.ID, &a.Name, &a.Sex, &birthday, &filmList, &headshotURLs)
 		if err != nil {
 			log.Printf("ERROR: failed to execute query\n")
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		if len(filmString.String) != 0 {
-			a.Films = strings.Split(filmString.String, ";")
+		a.Birthday = birthday.Time
+		if err := json.Unmarshal(filmList, &a.Films); err != nil {
+			log.Printf("ERROR: failed to decode actor films\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := json.Unmarshal(headshotURLs, &a.Headshot); err != nil {
+			log.Printf("ERROR: failed to decode actor headshot\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
 		actors = append(actors, &a)
@@ -202,3 +245,71 @@ func (r *Repository) GetAll(ctx context.Context) ([]*Actor, error) {
 
 	return actors, nil
 }
+
+func (r *Repository) Count(ctx context.Context) (int, error) {
+	const op = "actor.Repository.Count"
+
+	const query = `SELECT COUNT(*) FROM actor WHERE deleted_at IS NULL`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var count int
+	err = stmt.QueryRowContext(ctx).Scan(&count)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *Repository) GetFilmography(ctx context.Context, id int) ([]*FilmCredit, error) {
+	const op = "actor.Repository.GetFilmography"
+
+	const query = `
+		SELECT m.movie_id, m.movie_name, m.releasedate, 'actor' AS role, am.characters, COALESCE(am.billing, 0)
+		FROM actor_in_movie am
+		INNER JOIN movie m USING (movie_id)
+		WHERE am.actor_id = $1 AND m.deleted_at IS NULL
+		UNION ALL
+		SELECT m.movie_id, m.movie_name, m.releasedate, mc.crew_role, '{}'::varchar[], 0
+		FROM movie_crew mc
+		INNER JOIN movie m USING (movie_id)
+		WHERE mc.person_id = $1 AND m.deleted_at IS NULL
+		ORDER BY releasedate DESC, movie_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var credits []*FilmCredit
+	for rows.Next() {
+		var fc FilmCredit
+		err := rows.Scan(&fc.ID, &fc.Name, &fc.ReleaseDate, &fc.Role, pq.Array(&fc.Characters), &fc.Billing)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		credits = append(credits, &fc)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return credits, nil
+}
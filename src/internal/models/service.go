@@ -5,37 +5,87 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/history"
+	"film-library/src/internal/tools"
 )
 
 var (
-	ErrIdInvalid = errors.New("invalid id")
+	ErrIdInvalid       = errors.New("invalid id")
+	ErrVersionMismatch = errors.New("actor version does not match")
 )
 
 var _ ActorService = (*Service)(nil)
 
 type Service struct {
-	repo ActorRepository
+	repo    ActorRepository
+	tx      db.Transactor
+	history history.Recorder
 }
 
-func NewService(ar ActorRepository) *Service {
+func NewService(ar ActorRepository, tx db.Transactor, hr history.Recorder) *Service {
 	return &Service{
-		repo: ar,
+		repo:    ar,
+		tx:      tx,
+		history: hr,
 	}
 }
 
-func (s *Service) GetAll(ctx context.Context) ([]*ActorResponse, error) {
+func (s *Service) GetAll(ctx context.Context, req *GetActorsRequest) (*GetActorsResponse, error) {
 	const op = "actor.Service.GetAll"
 
-	actors, err := s.repo.GetAll(ctx)
+	vErr := ValidateGetActorsRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	q := ToQuery(req)
+
+	// one extra actor is requested to find out whether the next page exists
+	limit := q.Limit
+	q.Limit = limit + 1
+
+	actors, err := s.repo.GetAll(ctx, q)
 	if err != nil {
 		log.Printf("ERROR: failed to get actor records from repository\n")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res := make([]*ActorResponse, 0, len(actors))
+	hasMore := len(actors) > limit
+	if hasMore {
+		actors = actors[:limit]
+	}
+	if q.Backward {
+		slices.Reverse(actors)
+	}
+
+	res := &GetActorsResponse{
+		Actors: make([]*ActorResponse, 0, len(actors)),
+	}
 	for _, v := range actors {
-		res = append(res, ToActorResponse(v))
+		res.Actors = append(res.Actors, ToActorResponse(v))
+	}
+
+	next, prev := tools.PageLinks(hasMore, q.Backward, q.Cursor != nil)
+	if len(actors) != 0 {
+		if next {
+			res.NextCursor = tools.EncodeCursor(ToActorCursor(actors[len(actors)-1]))
+		}
+		if prev {
+			res.PrevCursor = tools.EncodeCursor(ToActorCursor(actors[0]))
+		}
+	}
+
+	if req.TotalQuery == "true" {
+		total, err := s.repo.Count(ctx)
+		if err != nil {
+			log.Printf("ERROR: failed to count actor records in repository\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		res.Total = &total
 	}
 
 	return res, nil
@@ -49,19 +99,56 @@ func (s *Service) Add(ctx context.Context, req *ActorInfo) (*ActorResponse, erro
 		log.Printf("ERROR: failed request empty validation\n")
 		return nil, fmt.Errorf("%s: %w", op, vErr)
 	}
-	vErr = ValidateFormatActorInfo(req)
+
+	res, err := s.add(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// AddPerson adds a person the way Add adds an actor, sex and birthday may be
+// left empty.
+func (s *Service) AddPerson(ctx context.Context, req *ActorInfo) (*ActorResponse, error) {
+	const op = "actor.Service.AddPerson"
+
+	vErr := ValidateEmptyPersonInfo(req)
 	if vErr != nil {
-		log.Printf("ERROR: failed request format validation\n")
+		log.Printf("ERROR: failed request empty validation\n")
 		return nil, fmt.Errorf("%s: %w", op, vErr)
 	}
-	actor := ToActor(req)
 
-	actor, err := s.repo.Add(ctx, actor)
+	res, err := s.add(ctx, req)
 	if err != nil {
-		log.Printf("ERROR: failed to create actor record in repository")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	return res, nil
+}
+
+func (s *Service) add(ctx context.Context, req *ActorInfo) (*ActorResponse, error) {
+	vErr := ValidateFormatActorInfo(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request format validation\n")
+		return nil, vErr
+	}
+	actor := ToActor(req)
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		actor, err = s.repo.Add(ctx, actor)
+		if err != nil {
+			log.Printf("ERROR: failed to create actor record in repository")
+			return err
+		}
+
+		return s.record(ctx, actor.ID, history.ActionCreate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	res := ToActorResponse(actor)
 
 	return res, nil
@@ -96,7 +183,12 @@ func (s *Service) Update(ctx context.Context, req *ActorIdInfoRequest) (*ActorRe
 		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
 	}
 
-	vErr := ValidateFormatActorInfo(&req.Info)
+	vErr := ValidateReplacementActorInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request empty validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	vErr = ValidateFormatActorInfo(&req.Info)
 	if vErr != nil {
 		log.Printf("ERROR: failed request format validation\n")
 		return nil, fmt.Errorf("%s: %w", op, vErr)
@@ -105,9 +197,19 @@ func (s *Service) Update(ctx context.Context, req *ActorIdInfoRequest) (*ActorRe
 	actor := ToActor(&req.Info)
 	actor.ID = int(id)
 
-	err = s.repo.Update(ctx, actor)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkVersion(ctx, actor.ID, req.IfMatch); err != nil {
+			return err
+		}
+
+		if err := s.repo.Update(ctx, actor); err != nil {
+			log.Printf("ERROR: failed to update actor record in repository")
+			return err
+		}
+
+		return s.record(ctx, actor.ID, history.ActionUpdate)
+	})
 	if err != nil {
-		log.Printf("ERROR: failed to update actor record in repository")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -122,6 +224,67 @@ func (s *Service) Update(ctx context.Context, req *ActorIdInfoRequest) (*ActorRe
 	return res, nil
 }
 
+// Patch applies a JSON merge patch or JSON patch to the info of the actor,
+// the patched info replaces the current one as with Update.
+func (s *Service) Patch(ctx context.Context, req *ActorPatchRequest) (*ActorResponse, error) {
+	const op = "actor.Service.Patch"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkVersion(ctx, int(id), req.IfMatch); err != nil {
+			return err
+		}
+
+		actor, err := s.repo.Get(ctx, int(id))
+		if err != nil {
+			log.Printf("ERROR: failed to get actor record from repository\n")
+			return err
+		}
+
+		var info ActorInfo
+		if err := tools.ApplyPatch(req.MediaType, &ToActorResponse(actor).Info, req.Patch, &info); err != nil {
+			log.Printf("ERROR: failed to apply patch to actor\n")
+			return err
+		}
+
+		if vErr := ValidateReplacementActorInfo(&info); vErr != nil {
+			log.Printf("ERROR: failed patched actor empty validation\n")
+			return vErr
+		}
+		if vErr := ValidateFormatActorInfo(&info); vErr != nil {
+			log.Printf("ERROR: failed patched actor format validation\n")
+			return vErr
+		}
+
+		actor = ToActor(&info)
+		actor.ID = int(id)
+		if err := s.repo.Update(ctx, actor); err != nil {
+			log.Printf("ERROR: failed to update actor record in repository")
+			return err
+		}
+
+		return s.record(ctx, actor.ID, history.ActionUpdate)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	actor, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get actor record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToActorResponse(actor)
+
+	return res, nil
+}
+
 func (s *Service) Delete(ctx context.Context, req *ActorIdRequest) (*ActorResponse, error) {
 	const op = "actor.Service.Delete"
 
@@ -137,9 +300,19 @@ func (s *Service) Delete(ctx context.Context, req *ActorIdRequest) (*ActorRespon
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = s.repo.Delete(ctx, int(id))
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkVersion(ctx, int(id), req.IfMatch); err != nil {
+			return err
+		}
+
+		if err := s.repo.Delete(ctx, int(id)); err != nil {
+			log.Printf("ERROR: failed to delete actor record in repository")
+			return err
+		}
+
+		return s.record(ctx, actor.ID, history.ActionDelete)
+	})
 	if err != nil {
-		log.Printf("ERROR: failed to delete actor record in repository")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -147,3 +320,58 @@ func (s *Service) Delete(ctx context.Context, req *ActorIdRequest) (*ActorRespon
 
 	return res, nil
 }
+
+func (s *Service) GetFilmography(ctx context.Context, req *ActorIdRequest) (*FilmographyResponse, error) {
+	const op = "actor.Service.GetFilmography"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	actor, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get actor record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	credits, err := s.repo.GetFilmography(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get filmography from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToFilmographyResponse(actor, credits)
+
+	return res, nil
+}
+
+// checkVersion locks the actor for the rest of the transaction and fails
+// unless its version matches the If-Match header, any version matching an
+// empty header.
+func (s *Service) checkVersion(ctx context.Context, id int, ifMatch string) error {
+	version, err := s.repo.GetVersion(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to get actor version from repository\n")
+		return err
+	}
+
+	if ifMatch != "" && !tools.MatchVersion(ifMatch, version) {
+		log.Printf("ERROR: actor version %d does not match %s\n", version, ifMatch)
+		return ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// record keeps a version of the actor after a change, it is meant to run
+// in the transaction of the change so that both are kept or neither is.
+func (s *Service) record(ctx context.Context, id int, action string) error {
+	if err := s.history.Record(ctx, history.EntityActor, id, action); err != nil {
+		log.Printf("ERROR: failed to record actor history\n")
+		return err
+	}
+
+	return nil
+}
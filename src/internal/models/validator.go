@@ -12,6 +12,18 @@ var sexMap = map[string]struct{}{
 	"male":   {},
 }
 
+func ValidateGetActorsRequest(req *GetActorsRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	tools.ValidatePageQuery(ve, req.LimitQuery, req.AfterQuery, req.BeforeQuery, req.TotalQuery)
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
 func ValidateFormatActorInfo(ai *ActorInfo) *tools.ValidationError {
 	ve := &tools.ValidationError{}
 
@@ -32,6 +44,39 @@ func ValidateFormatActorInfo(ai *ActorInfo) *tools.ValidationError {
 	return ve
 }
 
+// ValidateReplacementActorInfo checks info replacing the one of an actor,
+// sex and birthday may be left empty as they are for actors added by film
+// imports.
+func ValidateReplacementActorInfo(ai *ActorInfo) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(ai.Name) == 0 {
+		ve.AddViolation("name empty")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+// ValidateEmptyPersonInfo checks info of a person added for crew credits,
+// only the name is required as sex and birthday are rarely known for crew.
+func ValidateEmptyPersonInfo(ai *ActorInfo) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(ai.Name) == 0 {
+		ve.AddViolation("name empty")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
 func ValidateEmptyActorInfo(ai *ActorInfo) *tools.ValidationError {
 	ve := &tools.ValidationError{}
 
@@ -0,0 +1,83 @@
+package review
+
+import (
+	"strings"
+
+	"film-library/src/internal/tools"
+)
+
+func ToReviewResponse(rv *Review) *ReviewResponse {
+	return &ReviewResponse{
+		ID:     rv.ID,
+		FilmID: rv.FilmID,
+		Author: ReviewAuthor{
+			ID:       rv.UserID,
+			Username: rv.Username,
+		},
+		Info: ReviewInfo{
+			Title: rv.Title,
+			Body:  rv.Body,
+		},
+		Status:    rv.Status,
+		CreatedAt: rv.CreatedAt,
+		UpdatedAt: rv.UpdatedAt,
+	}
+}
+
+func ToReview(ri *ReviewInfo) *Review {
+	return &Review{
+		Title: strings.TrimSpace(ri.Title),
+		Body:  strings.TrimSpace(ri.Body),
+	}
+}
+
+// ToFilterConditions returns conditions selecting reviews that match q
+// regardless of the page requested.
+func ToFilterConditions(q *Query) tools.Cond {
+	var conds []tools.Cond
+
+	if q.FilmID != 0 {
+		conds = append(conds, tools.Expr("rv.movie_id = ?", q.FilmID))
+	}
+
+	if len(q.Status) != 0 {
+		conds = append(conds, tools.Expr("rv.status = ?", q.Status))
+	}
+
+	return tools.And(conds...)
+}
+
+// ToQueryConditions applies filters, keyset position, ordering and limit
+// of q to the reviews query, newest reviews come first.
+func ToQueryConditions(q *Query, qb *tools.SelectBuilder) *tools.SelectBuilder {
+	qb.Where(ToFilterConditions(q))
+
+	cmp, order := "<", "DESC"
+	if q.Backward {
+		cmp, order = ">", "ASC"
+	}
+
+	if q.Cursor != nil {
+		qb.Where(tools.Expr("rv.review_id "+cmp+" ?", q.Cursor.ID))
+	}
+
+	return qb.OrderBy("rv.review_id " + order).Limit(q.Limit)
+}
+
+func ToQuery(req *GetReviewsRequest, filmID int, status string) *Query {
+	limit, cursor, backward := tools.ToPageQuery(req.LimitQuery, req.AfterQuery, req.BeforeQuery)
+
+	return &Query{
+		FilmID:   filmID,
+		Status:   status,
+		Limit:    limit,
+		Cursor:   cursor,
+		Backward: backward,
+	}
+}
+
+func ToReviewCursor(rv *Review) *tools.Cursor {
+	return &tools.Cursor{
+		ID: rv.ID,
+	}
+}
@@ -0,0 +1,280 @@
+package review
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+var _ ReviewHandler = (*Handler)(nil)
+
+type Handler struct {
+	service ReviewService
+}
+
+func NewHandler(rs ReviewService) *Handler {
+	return &Handler{
+		service: rs,
+	}
+}
+
+func (h *Handler) GetFilmReviews(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetFilmReviews(r.Context(), &GetReviewsRequest{
+		FilmID:      r.PathValue("id"),
+		LimitQuery:  r.URL.Query().Get("limit"),
+		AfterQuery:  r.URL.Query().Get("after"),
+		BeforeQuery: r.URL.Query().Get("before"),
+		TotalQuery:  r.URL.Query().Get("total"),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to get film reviews err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := AddReviewRequest{
+		FilmID: r.PathValue("id"),
+		UserID: uc.ID,
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.Add(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to add review err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrReviewExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeConflict,
+				Body:      "film is already reviewed by the user",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := ReviewIdRequest{
+		ID:      r.PathValue("id"),
+		UserID:  uc.ID,
+		IsAdmin: uc.IsAdmin,
+	}
+
+	res, err := h.service.Get(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get review err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrReviewNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := ReviewIdInfoRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.Update(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to update review err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrReviewNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrForbidden) {
+			tools.Forbidden(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := ReviewIdRequest{
+		ID:      r.PathValue("id"),
+		UserID:  uc.ID,
+		IsAdmin: uc.IsAdmin,
+	}
+
+	res, err := h.service.Delete(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete review err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrReviewNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, ErrForbidden) {
+			tools.Forbidden(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetModerationQueue(r.Context(), &GetReviewsRequest{
+		StatusQuery: r.URL.Query().Get("status"),
+		LimitQuery:  r.URL.Query().Get("limit"),
+		AfterQuery:  r.URL.Query().Get("after"),
+		BeforeQuery: r.URL.Query().Get("before"),
+		TotalQuery:  r.URL.Query().Get("total"),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to get moderation queue err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Moderate(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	var req ModerationRequest
+	if ok := tools.BindJSON(w, r, &req); !ok {
+		return
+	}
+	req.ID = r.PathValue("id")
+	req.ModeratorID = uc.ID
+
+	res, err := h.service.Moderate(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to moderate review err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrReviewNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
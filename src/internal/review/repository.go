@@ -0,0 +1,269 @@
+package review
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/tools"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrReviewNotExist = errors.New("review does not exist")
+	ErrReviewExist    = errors.New("user has already reviewed given film")
+	ErrFilmNotExist   = errors.New("film with given id does not exist")
+)
+
+const reviewColumns = `
+	rv.review_id, rv.movie_id, rv.user_id, u.user_name, rv.title, rv.body,
+	rv.status, rv.created_at, rv.updated_at`
+
+var _ ReviewRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) Get(ctx context.Context, id int) (*Review, error) {
+	const op = "review.Repository.Get"
+
+	const query = `
+		SELECT ` + reviewColumns + `
+		FROM review rv
+		INNER JOIN users u USING (user_id)
+		WHERE rv.review_id = $1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var rv Review
+	err = stmt.QueryRowContext(ctx, id).Scan(&rv.ID, &rv.FilmID, &rv.UserID, &rv.Username, &rv.Title, &rv.Body,
+		&rv.Status, &rv.CreatedAt, &rv.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: review with id=%d does not exist\n", id)
+			return nil, fmt.Errorf("%s: %w", op, ErrReviewNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &rv, nil
+}
+
+func (r *Repository) Add(ctx context.Context, rv *Review) (*Review, error) {
+	const op = "review.Repository.Add"
+
+	const query = `
+		INSERT INTO review(movie_id, user_id, title, body)
+		SELECT movie_id, $2, $3, $4 FROM movie WHERE movie_id = $1 AND deleted_at IS NULL
+		RETURNING review_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, rv.FilmID, rv.UserID, rv.Title, rv.Body).Scan(&rv.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: film with id=%d does not exist\n", rv.FilmID)
+			return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+		}
+
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "unique_violation" {
+				log.Printf("ERROR: user with id=%d has already reviewed film with id=%d\n", rv.UserID, rv.FilmID)
+				return nil, fmt.Errorf("%s: %w", op, ErrReviewExist)
+			}
+			if pgErr.Code.Name() == "foreign_key_violation" {
+				log.Printf("ERROR: film with id=%d does not exist\n", rv.FilmID)
+				return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rv, nil
+}
+
+// Update replaces the text of the review and sends it back to moderation.
+func (r *Repository) Update(ctx context.Context, rv *Review) error {
+	const op = "review.Repository.Update"
+
+	const query = `
+		UPDATE review
+		SET title = $1, body = $2, status = 'pending', updated_at = now(),
+			moderated_by = NULL, moderated_at = NULL
+		WHERE review_id = $3`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, rv.Title, rv.Body, rv.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by update\n")
+		return fmt.Errorf("%s: %w", op, ErrReviewNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	const op = "review.Repository.Delete"
+
+	const query = `DELETE FROM review WHERE review_id = $1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by delete\n")
+		return fmt.Errorf("%s: %w", op, ErrReviewNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetAll(ctx context.Context, q *Query) ([]*Review, error) {
+	const op = "review.Repository.GetAll"
+
+	qb := tools.NewSelectBuilder(`
+		SELECT ` + reviewColumns + `
+		FROM review rv
+		INNER JOIN users u USING (user_id)`)
+	query, args := ToQueryConditions(q, qb).Build()
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var reviews []*Review
+
+	for rows.Next() {
+		var rv Review
+		err := rows.Scan(&rv.ID, &rv.FilmID, &rv.UserID, &rv.Username, &rv.Title, &rv.Body,
+			&rv.Status, &rv.CreatedAt, &rv.UpdatedAt)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		reviews = append(reviews, &rv)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reviews, nil
+}
+
+func (r *Repository) Count(ctx context.Context, q *Query) (int, error) {
+	const op = "review.Repository.Count"
+
+	qb := tools.NewSelectBuilder(`SELECT COUNT(*) FROM review rv`)
+	query, args := qb.Where(ToFilterConditions(q)).Build()
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var count int
+	err = stmt.QueryRowContext(ctx, args...).Scan(&count)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *Repository) Moderate(ctx context.Context, m *Moderation) error {
+	const op = "review.Repository.Moderate"
+
+	const query = `
+		UPDATE review
+		SET status = $1, moderated_by = $2, moderated_at = now()
+		WHERE review_id = $3`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, m.Status, m.ModeratorID, m.ReviewID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by update\n")
+		return fmt.Errorf("%s: %w", op, ErrReviewNotExist)
+	}
+
+	return nil
+}
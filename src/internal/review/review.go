@@ -0,0 +1,137 @@
+package review
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"film-library/src/internal/tools"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+type Review struct {
+	ID        int
+	FilmID    int
+	UserID    int
+	Username  string
+	Title     string
+	Body      string
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Moderation is a decision of an admin on the status of a review.
+type Moderation struct {
+	ReviewID    int
+	ModeratorID int
+	Status      string
+}
+
+// Query selects a page of reviews, FilmID of zero matches reviews of all
+// films.
+type Query struct {
+	FilmID   int
+	Status   string
+	Limit    int
+	Cursor   *tools.Cursor
+	Backward bool
+}
+
+type ReviewRepository interface {
+	Get(ctx context.Context, id int) (*Review, error)
+	Add(ctx context.Context, rv *Review) (*Review, error)
+	Update(ctx context.Context, rv *Review) error
+	Delete(ctx context.Context, id int) error
+	GetAll(ctx context.Context, q *Query) ([]*Review, error)
+	Count(ctx context.Context, q *Query) (int, error)
+	Moderate(ctx context.Context, m *Moderation) error
+}
+
+type ReviewService interface {
+	GetFilmReviews(ctx context.Context, req *GetReviewsRequest) (*GetReviewsResponse, error)
+	Add(ctx context.Context, req *AddReviewRequest) (*ReviewResponse, error)
+	Get(ctx context.Context, req *ReviewIdRequest) (*ReviewResponse, error)
+	Update(ctx context.Context, req *ReviewIdInfoRequest) (*ReviewResponse, error)
+	Delete(ctx context.Context, req *ReviewIdRequest) (*ReviewResponse, error)
+	GetModerationQueue(ctx context.Context, req *GetReviewsRequest) (*GetReviewsResponse, error)
+	Moderate(ctx context.Context, req *ModerationRequest) (*ReviewResponse, error)
+}
+
+type ReviewHandler interface {
+	GetFilmReviews(w http.ResponseWriter, r *http.Request)
+	Add(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	GetModerationQueue(w http.ResponseWriter, r *http.Request)
+	Moderate(w http.ResponseWriter, r *http.Request)
+}
+
+type ReviewInfo struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type ReviewAuthor struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+type ReviewResponse struct {
+	ID        int          `json:"id"`
+	FilmID    int          `json:"filmId"`
+	Author    ReviewAuthor `json:"author"`
+	Info      ReviewInfo   `json:"info"`
+	Status    string       `json:"status"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// GetReviewsRequest is shared by the film reviews listing, where FilmID
+// is set and only approved reviews are shown, and the moderation queue.
+type GetReviewsRequest struct {
+	FilmID      string
+	StatusQuery string
+	LimitQuery  string
+	AfterQuery  string
+	BeforeQuery string
+	TotalQuery  string
+}
+
+type GetReviewsResponse struct {
+	Reviews    []*ReviewResponse `json:"reviews"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+	PrevCursor string            `json:"prevCursor,omitempty"`
+	Total      *int              `json:"total,omitempty"`
+}
+
+type AddReviewRequest struct {
+	FilmID string
+	UserID int
+	Info   ReviewInfo
+}
+
+// ReviewIdRequest identifies a review together with the user acting on it.
+type ReviewIdRequest struct {
+	ID      string
+	UserID  int
+	IsAdmin bool
+}
+
+type ReviewIdInfoRequest struct {
+	ID     string
+	UserID int
+	Info   ReviewInfo
+}
+
+type ModerationRequest struct {
+	ID          string
+	ModeratorID int
+	Status      string `json:"status"`
+}
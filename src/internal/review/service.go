@@ -0,0 +1,299 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+
+	"film-library/src/internal/tools"
+)
+
+var (
+	ErrIdInvalid = errors.New("invalid id")
+	ErrForbidden = errors.New("review belongs to another user")
+)
+
+var _ ReviewService = (*Service)(nil)
+
+type Service struct {
+	repo ReviewRepository
+}
+
+func NewService(rr ReviewRepository) *Service {
+	return &Service{
+		repo: rr,
+	}
+}
+
+func (s *Service) GetFilmReviews(ctx context.Context, req *GetReviewsRequest) (*GetReviewsResponse, error) {
+	const op = "review.Service.GetFilmReviews"
+
+	id, err := strconv.ParseUint(req.FilmID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateGetReviewsRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	// only approved reviews are published
+	res, err := s.getReviews(ctx, ToQuery(req, int(id), StatusApproved), req.TotalQuery == "true")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) GetModerationQueue(ctx context.Context, req *GetReviewsRequest) (*GetReviewsResponse, error) {
+	const op = "review.Service.GetModerationQueue"
+
+	vErr := ValidateGetReviewsRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	status := req.StatusQuery
+	if len(status) == 0 {
+		status = StatusPending
+	}
+
+	res, err := s.getReviews(ctx, ToQuery(req, 0, status), req.TotalQuery == "true")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (s *Service) getReviews(ctx context.Context, q *Query, total bool) (*GetReviewsResponse, error) {
+	// one extra review is requested to find out whether the next page exists
+	limit := q.Limit
+	q.Limit = limit + 1
+
+	reviews, err := s.repo.GetAll(ctx, q)
+	if err != nil {
+		log.Printf("ERROR: failed to get review records from repository\n")
+		return nil, err
+	}
+
+	hasMore := len(reviews) > limit
+	if hasMore {
+		reviews = reviews[:limit]
+	}
+	if q.Backward {
+		slices.Reverse(reviews)
+	}
+
+	res := &GetReviewsResponse{
+		Reviews: make([]*ReviewResponse, 0, len(reviews)),
+	}
+	for _, v := range reviews {
+		res.Reviews = append(res.Reviews, ToReviewResponse(v))
+	}
+
+	next, prev := tools.PageLinks(hasMore, q.Backward, q.Cursor != nil)
+	if len(reviews) != 0 {
+		if next {
+			res.NextCursor = tools.EncodeCursor(ToReviewCursor(reviews[len(reviews)-1]))
+		}
+		if prev {
+			res.PrevCursor = tools.EncodeCursor(ToReviewCursor(reviews[0]))
+		}
+	}
+
+	if total {
+		count, err := s.repo.Count(ctx, q)
+		if err != nil {
+			log.Printf("ERROR: failed to count review records in repository\n")
+			return nil, err
+		}
+		res.Total = &count
+	}
+
+	return res, nil
+}
+
+func (s *Service) Add(ctx context.Context, req *AddReviewRequest) (*ReviewResponse, error) {
+	const op = "review.Service.Add"
+
+	id, err := strconv.ParseUint(req.FilmID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateReviewInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	review := ToReview(&req.Info)
+	review.FilmID = int(id)
+	review.UserID = req.UserID
+
+	review, err = s.repo.Add(ctx, review)
+	if err != nil {
+		log.Printf("ERROR: failed to create review record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	review, err = s.repo.Get(ctx, review.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to get added review record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToReviewResponse(review)
+
+	return res, nil
+}
+
+// Get hides reviews that are not approved yet from everyone except their
+// authors and admins.
+func (s *Service) Get(ctx context.Context, req *ReviewIdRequest) (*ReviewResponse, error) {
+	const op = "review.Service.Get"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	review, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get review record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if review.Status != StatusApproved && review.UserID != req.UserID && !req.IsAdmin {
+		log.Printf("ERROR: review with id=%d is not published\n", review.ID)
+		return nil, fmt.Errorf("%s: %w", op, ErrReviewNotExist)
+	}
+
+	res := ToReviewResponse(review)
+
+	return res, nil
+}
+
+func (s *Service) Update(ctx context.Context, req *ReviewIdInfoRequest) (*ReviewResponse, error) {
+	const op = "review.Service.Update"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateReviewInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	review, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get review record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if review.UserID != req.UserID {
+		log.Printf("ERROR: user with id=%d is not the author of review with id=%d\n", req.UserID, review.ID)
+		return nil, fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	update := ToReview(&req.Info)
+	update.ID = review.ID
+
+	err = s.repo.Update(ctx, update)
+	if err != nil {
+		log.Printf("ERROR: failed to update review record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	review, err = s.repo.Get(ctx, review.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to get updated review record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToReviewResponse(review)
+
+	return res, nil
+}
+
+func (s *Service) Delete(ctx context.Context, req *ReviewIdRequest) (*ReviewResponse, error) {
+	const op = "review.Service.Delete"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	review, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get review record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if review.UserID != req.UserID && !req.IsAdmin {
+		log.Printf("ERROR: user with id=%d is not the author of review with id=%d\n", req.UserID, review.ID)
+		return nil, fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	err = s.repo.Delete(ctx, review.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to delete review record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToReviewResponse(review)
+
+	return res, nil
+}
+
+func (s *Service) Moderate(ctx context.Context, req *ModerationRequest) (*ReviewResponse, error) {
+	const op = "review.Service.Moderate"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateModerationRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+
+	m := &Moderation{
+		ReviewID:    int(id),
+		ModeratorID: req.ModeratorID,
+		Status:      req.Status,
+	}
+	err = s.repo.Moderate(ctx, m)
+	if err != nil {
+		log.Printf("ERROR: failed to moderate review record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	review, err := s.repo.Get(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get moderated review record from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToReviewResponse(review)
+
+	return res, nil
+}
@@ -0,0 +1,69 @@
+package review
+
+import (
+	"strings"
+
+	"film-library/src/internal/tools"
+)
+
+var statusMap = map[string]struct{}{
+	StatusPending:  {},
+	StatusApproved: {},
+	StatusRejected: {},
+}
+
+func ValidateGetReviewsRequest(req *GetReviewsRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if _, ok := statusMap[req.StatusQuery]; !ok && len(req.StatusQuery) != 0 {
+		ve.AddViolation("incorrect status query, expected one of [pending, approved, rejected]")
+	}
+
+	tools.ValidatePageQuery(ve, req.LimitQuery, req.AfterQuery, req.BeforeQuery, req.TotalQuery)
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateReviewInfo(ri *ReviewInfo) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(strings.TrimSpace(ri.Title)) == 0 {
+		ve.AddViolation("title empty")
+	}
+
+	if len(ri.Title) > 150 {
+		ve.AddViolation("title length is more than 150 symbols")
+	}
+
+	if len(strings.TrimSpace(ri.Body)) == 0 {
+		ve.AddViolation("body empty")
+	}
+
+	if len(ri.Body) > 10000 {
+		ve.AddViolation("body length is more than 10000 symbols")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateModerationRequest(req *ModerationRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if req.Status != StatusApproved && req.Status != StatusRejected {
+		ve.AddViolation("incorrect status, expected one of [approved, rejected]")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
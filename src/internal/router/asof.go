@@ -0,0 +1,21 @@
+package router
+
+import (
+	"net/http"
+)
+
+// NewAsOfMiddleware hands requests having the asOf query over to asOf, so
+// that a past version of a record is served on the same path as its
+// current state.
+func NewAsOfMiddleware(asOf http.HandlerFunc) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Has("asOf") {
+				asOf(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
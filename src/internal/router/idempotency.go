@@ -0,0 +1,180 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/idempotency"
+	"film-library/src/internal/tools"
+)
+
+var idempotentMethods = map[string]struct{}{
+	http.MethodPost:   {},
+	http.MethodPut:    {},
+	http.MethodPatch:  {},
+	http.MethodDelete: {},
+}
+
+// NewIdempotencyMiddleware replays the response of a mutating request made
+// again with the same Idempotency-Key header. Keys are scoped to the signed
+// in user, bodies are read up to maxBody bytes to fingerprint the request
+// and requests without the header are passed through.
+func NewIdempotencyMiddleware(key string, maxBody int64, is idempotency.IdempotencyService) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, ok := idempotentMethods[r.Method]
+			idemKey := r.Header.Get("idempotency-key")
+			if !ok || len(idemKey) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxBody+1))
+			if err != nil {
+				log.Printf("ERROR: failed to read request body err=%s\n", err.Error())
+				tools.InternalServerError(w, r)
+				return
+			}
+			if int64(len(body)) > maxBody {
+				log.Printf("ERROR: request body exceeds %d bytes\n", maxBody)
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			req := &idempotency.KeyRequest{
+				Key:    idemKey,
+				Method: r.Method,
+				Path:   r.URL.RequestURI(),
+				Body:   body,
+			}
+			if cookie, err := r.Cookie("jwt"); err == nil {
+				if uc, err := tools.ParseUserClaims(cookie.Value, key); err == nil {
+					req.UserID = uc.ID
+				}
+			}
+
+			res, err := is.Begin(r.Context(), req)
+			if err != nil {
+				log.Printf("ERROR: failed to begin idempotent request err=%s\n", err.Error())
+
+				var ve *tools.ValidationError
+				if errors.As(err, &ve) {
+					tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+						ErrorType: tools.ErrorTypeValidation,
+						Body:      ve.Error(),
+					})
+					return
+				}
+
+				if errors.Is(err, idempotency.ErrKeyReused) {
+					tools.JSON(w, r, http.StatusUnprocessableEntity, &tools.ErrorMessage{
+						ErrorType: tools.ErrorTypeConflict,
+						Body:      "idempotency key is already used for another request",
+					})
+					return
+				}
+
+				if errors.Is(err, idempotency.ErrKeyInProgress) {
+					tools.JSON(w, r, http.StatusConflict, &tools.ErrorMessage{
+						ErrorType: tools.ErrorTypeConflict,
+						Body:      "request with the idempotency key is in progress",
+					})
+					return
+				}
+
+				tools.InternalServerError(w, r)
+				return
+			}
+
+			if res != nil {
+				log.Printf("INFO: replaying response of idempotency key %q\n", idemKey)
+				for k, v := range res.Header {
+					w.Header()[k] = v
+				}
+				w.Header().Set("idempotent-replayed", "true")
+				w.WriteHeader(res.StatusCode)
+				w.Write(res.Body)
+				return
+			}
+
+			// the key outlives a client that went away during the request
+			ctx := context.WithoutCancel(r.Context())
+
+			// a panicking handler leaves no response to keep, the key is
+			// released for the request to be retried
+			defer func() {
+				if p := recover(); p != nil {
+					if err := is.Release(ctx, req); err != nil {
+						log.Printf("ERROR: failed to release idempotency key err=%s\n", err.Error())
+					}
+					panic(p)
+				}
+			}()
+
+			rec := &responseRecorder{
+				ResponseWriter: w,
+			}
+			next.ServeHTTP(rec, r)
+
+			res = rec.response()
+
+			// failed requests are forgotten so that they can be retried, so
+			// are responses setting cookies as these carry session tokens
+			if res.StatusCode >= http.StatusInternalServerError || len(res.Header.Values("set-cookie")) != 0 {
+				if err := is.Release(ctx, req); err != nil {
+					log.Printf("ERROR: failed to release idempotency key err=%s\n", err.Error())
+				}
+				return
+			}
+
+			if err := is.Complete(ctx, req, res); err != nil {
+				log.Printf("ERROR: failed to complete idempotency key err=%s\n", err.Error())
+			}
+		})
+	}
+}
+
+// responseRecorder passes a response through while keeping a copy of it,
+// the header is copied as it is when written.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	header     http.Header
+	body       bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if rr.statusCode == 0 {
+		rr.statusCode = statusCode
+		rr.header = rr.Header().Clone()
+	}
+
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.statusCode == 0 {
+		rr.WriteHeader(http.StatusOK)
+	}
+	rr.body.Write(b)
+
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) response() *idempotency.Response {
+	if rr.statusCode == 0 {
+		rr.statusCode = http.StatusOK
+		rr.header = rr.Header().Clone()
+	}
+
+	return &idempotency.Response{
+		StatusCode: rr.statusCode,
+		Header:     rr.header,
+		Body:       rr.body.Bytes(),
+	}
+}
@@ -0,0 +1,183 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"film-library/src/internal/idempotency"
+)
+
+// fakeIdempotencyService keeps records in memory the way the service keeps
+// them in the database.
+type fakeIdempotencyService struct {
+	records map[string]*idempotency.Record
+}
+
+func (s *fakeIdempotencyService) Begin(ctx context.Context, req *idempotency.KeyRequest) (*idempotency.Response, error) {
+	if ve := idempotency.ValidateKeyRequest(req); ve != nil {
+		return nil, ve
+	}
+
+	rec := idempotency.ToRecord(req)
+	existing, ok := s.records[req.Key]
+	if !ok {
+		s.records[req.Key] = rec
+		return nil, nil
+	}
+	if !bytes.Equal(existing.Fingerprint, rec.Fingerprint) {
+		return nil, idempotency.ErrKeyReused
+	}
+	if existing.Response == nil {
+		return nil, idempotency.ErrKeyInProgress
+	}
+
+	return existing.Response, nil
+}
+
+func (s *fakeIdempotencyService) Complete(ctx context.Context, req *idempotency.KeyRequest, res *idempotency.Response) error {
+	s.records[req.Key].Response = res
+
+	return nil
+}
+
+func (s *fakeIdempotencyService) Release(ctx context.Context, req *idempotency.KeyRequest) error {
+	delete(s.records, req.Key)
+
+	return nil
+}
+
+func newIdempotencyTestHandler() (http.Handler, *fakeIdempotencyService, *int) {
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/panic":
+			panic(http.ErrAbortHandler)
+		case "/signin":
+			http.SetCookie(w, &http.Cookie{Name: "jwt", Value: "token"})
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.Header().Set("location", "/films/1")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, "created %d", calls)
+		}
+	})
+
+	is := &fakeIdempotencyService{records: make(map[string]*idempotency.Record)}
+
+	return NewIdempotencyMiddleware("key", 100, is)(next), is, &calls
+}
+
+func serveIdempotent(h http.Handler, path, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if key != "" {
+		r.Header.Set("idempotency-key", key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestIdempotencyMiddlewareReplays(t *testing.T) {
+	h, _, calls := newIdempotencyTestHandler()
+
+	first := serveIdempotent(h, "/films", "a", "{}")
+	second := serveIdempotent(h, "/films", "a", "{}")
+
+	if *calls != 1 {
+		t.Fatalf("handler called %d times, want 1", *calls)
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Fatalf("replay = %d %q, want %d %q", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get("location") != "/films/1" || second.Header().Get("idempotent-replayed") != "true" {
+		t.Fatalf("replay header = %v", second.Header())
+	}
+}
+
+func TestIdempotencyMiddlewareRefusesReusedKey(t *testing.T) {
+	h, _, _ := newIdempotencyTestHandler()
+
+	serveIdempotent(h, "/films", "a", "{}")
+	w := serveIdempotent(h, "/films", "a", `{"other": true}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestIdempotencyMiddlewareForgets(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"server error", "/fail"},
+		{"session cookie", "/signin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, is, calls := newIdempotencyTestHandler()
+
+			serveIdempotent(h, tt.path, "a", "{}")
+			if _, ok := is.records["a"]; ok {
+				t.Fatalf("response of %s kept", tt.path)
+			}
+
+			serveIdempotent(h, tt.path, "a", "{}")
+			if *calls != 2 {
+				t.Fatalf("handler called %d times, want 2", *calls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesOnPanic(t *testing.T) {
+	h, is, _ := newIdempotencyTestHandler()
+
+	func() {
+		defer func() {
+			if p := recover(); p != http.ErrAbortHandler {
+				t.Fatalf("recovered %v, want the panic of the handler", p)
+			}
+		}()
+		serveIdempotent(h, "/panic", "a", "{}")
+	}()
+
+	if _, ok := is.records["a"]; ok {
+		t.Fatalf("key of panicked request kept")
+	}
+}
+
+func TestIdempotencyMiddlewarePassesThrough(t *testing.T) {
+	h, is, calls := newIdempotencyTestHandler()
+
+	serveIdempotent(h, "/films", "", "{}")
+	serveIdempotent(h, "/films", "", "{}")
+
+	if *calls != 2 || len(is.records) != 0 {
+		t.Fatalf("calls = %d, records = %d, want requests without key handled as is", *calls, len(is.records))
+	}
+}
+
+func TestIdempotencyMiddlewareValidates(t *testing.T) {
+	h, _, calls := newIdempotencyTestHandler()
+
+	if w := serveIdempotent(h, "/films", "bad key", "{}"); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d for invalid key, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := serveIdempotent(h, "/films", "b", strings.Repeat("x", 101)); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d for large body, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+	if *calls != 0 {
+		t.Errorf("handler called %d times, want 0", *calls)
+	}
+}
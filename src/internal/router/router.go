@@ -3,22 +3,38 @@ package router
 import (
 	"net/http"
 
+	"film-library/src/internal/collection"
 	"film-library/src/internal/config"
+	"film-library/src/internal/export"
 	"film-library/src/internal/film"
+	"film-library/src/internal/franchise"
+	"film-library/src/internal/genre"
+	"film-library/src/internal/history"
+	"film-library/src/internal/idempotency"
+	"film-library/src/internal/importer"
+	"film-library/src/internal/media"
 	"film-library/src/internal/models"
+	"film-library/src/internal/review"
+	"film-library/src/internal/search"
+	"film-library/src/internal/trash"
 	"film-library/src/internal/user"
+	"film-library/src/internal/watchlist"
 )
 
 type Router struct {
-	mux *http.ServeMux
+	mux     *http.ServeMux
+	handler http.Handler
 }
 
-func NewRouter(cfg *config.Config, uh user.UserHandler, ah models.ActorHandler, fh film.FilmHandler) *Router {
+func NewRouter(cfg *config.Config, uh user.UserHandler, ah models.ActorHandler, fh film.FilmHandler, sh search.SearchHandler, gh genre.GenreHandler, rh review.ReviewHandler, wh watchlist.WatchlistHandler, ch collection.CollectionHandler, frh franchise.FranchiseHandler, mh media.ImageHandler, ih importer.ImportHandler, eh export.ExportHandler, th trash.TrashHandler, hh history.HistoryHandler, is idempotency.IdempotencyService) *Router {
 	mux := http.NewServeMux()
 
 	authMW := NewAuthMiddleware(cfg.SigningKey, false)
 	adminOnlyMW := NewAuthMiddleware(cfg.SigningKey, true)
 	logMW := NewLogMiddleware()
+	filmAsOfMW := NewAsOfMiddleware(hh.GetFilmAsOf)
+	actorAsOfMW := NewAsOfMiddleware(hh.GetActorAsOf)
+	idempotencyMW := NewIdempotencyMiddleware(cfg.SigningKey, cfg.MaxUploadSize, is)
 
 	mux.HandleFunc("GET /ping", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -33,30 +49,113 @@ func NewRouter(cfg *config.Config, uh user.UserHandler, ah models.ActorHandler,
 		http.ServeFile(w, r, cfg.DocsYAML)
 	})
 
+	mux.Handle("GET /media/", http.StripPrefix("/media/", http.FileServer(http.Dir(cfg.MediaDir))))
+
 	mux.Handle("POST /signup", logMW(http.HandlerFunc(uh.CreateUser)))
 	mux.Handle("POST /signin", logMW(http.HandlerFunc(uh.Login)))
 	mux.Handle("DELETE /signout", logMW(http.HandlerFunc(uh.Logout)))
 
 	mux.Handle("GET /actors", logMW(authMW(http.HandlerFunc(ah.GetAll))))
 	mux.Handle("POST /actors", logMW(adminOnlyMW(http.HandlerFunc(ah.Add))))
-	mux.Handle("GET /actors/{id}", logMW(authMW(http.HandlerFunc(ah.Get))))
+	mux.Handle("GET /actors/{id}", logMW(authMW(actorAsOfMW(http.HandlerFunc(ah.Get)))))
 	mux.Handle("PUT /actors/{id}", logMW(adminOnlyMW(http.HandlerFunc(ah.Update))))
+	mux.Handle("PATCH /actors/{id}", logMW(adminOnlyMW(http.HandlerFunc(ah.Patch))))
 	mux.Handle("DELETE /actors/{id}", logMW(adminOnlyMW(http.HandlerFunc(ah.Delete))))
+	mux.Handle("PUT /actors/{id}/headshot", logMW(adminOnlyMW(http.HandlerFunc(mh.SetActorHeadshot))))
+	mux.Handle("DELETE /actors/{id}/headshot", logMW(adminOnlyMW(http.HandlerFunc(mh.DeleteActorHeadshot))))
+	mux.Handle("GET /actors/{id}/history", logMW(adminOnlyMW(http.HandlerFunc(hh.GetActorHistory))))
+	mux.Handle("POST /actors/{id}/history/{version}/revert", logMW(adminOnlyMW(http.HandlerFunc(hh.RevertActor))))
+	mux.Handle("POST /people", logMW(adminOnlyMW(http.HandlerFunc(ah.AddPerson))))
+	mux.Handle("GET /people/{id}/filmography", logMW(authMW(http.HandlerFunc(ah.GetFilmography))))
 
 	mux.Handle("GET /films", logMW(authMW(http.HandlerFunc(fh.GetFilms))))
+	mux.Handle("GET /films/suggest", logMW(authMW(http.HandlerFunc(fh.SuggestFilms))))
 	mux.Handle("POST /films", logMW(adminOnlyMW(http.HandlerFunc(fh.AddFilm))))
-	mux.Handle("GET /films/{id}", logMW(authMW(http.HandlerFunc(fh.GetFilm))))
+	mux.Handle("GET /films/{id}", logMW(authMW(filmAsOfMW(http.HandlerFunc(fh.GetFilm)))))
 	mux.Handle("PUT /films/{id}", logMW(adminOnlyMW(http.HandlerFunc(fh.UpdateFilm))))
+	mux.Handle("PATCH /films/{id}", logMW(adminOnlyMW(http.HandlerFunc(fh.PatchFilm))))
 	mux.Handle("DELETE /films/{id}", logMW(adminOnlyMW(http.HandlerFunc(fh.DeleteFilm))))
 	mux.Handle("GET /films/{id}/actors", logMW(authMW(http.HandlerFunc(fh.GetFilmActors))))
 	mux.Handle("PUT /films/{id}/actors", logMW(adminOnlyMW(http.HandlerFunc(fh.AddFilmActors))))
 	mux.Handle("DELETE /films/{id}/actors", logMW(adminOnlyMW(http.HandlerFunc(fh.DeleteFilmActors))))
+	mux.Handle("GET /films/{id}/genres", logMW(authMW(http.HandlerFunc(fh.GetFilmGenres))))
+	mux.Handle("PUT /films/{id}/genres", logMW(adminOnlyMW(http.HandlerFunc(fh.AddFilmGenres))))
+	mux.Handle("DELETE /films/{id}/genres", logMW(adminOnlyMW(http.HandlerFunc(fh.DeleteFilmGenres))))
+	mux.Handle("GET /films/{id}/crew", logMW(authMW(http.HandlerFunc(fh.GetFilmCrew))))
+	mux.Handle("PUT /films/{id}/crew", logMW(adminOnlyMW(http.HandlerFunc(fh.AddFilmCrew))))
+	mux.Handle("DELETE /films/{id}/crew", logMW(adminOnlyMW(http.HandlerFunc(fh.DeleteFilmCrew))))
+	mux.Handle("PUT /films/{id}/poster", logMW(adminOnlyMW(http.HandlerFunc(mh.SetFilmPoster))))
+	mux.Handle("DELETE /films/{id}/poster", logMW(adminOnlyMW(http.HandlerFunc(mh.DeleteFilmPoster))))
+	mux.Handle("GET /films/{id}/history", logMW(adminOnlyMW(http.HandlerFunc(hh.GetFilmHistory))))
+	mux.Handle("POST /films/{id}/history/{version}/revert", logMW(adminOnlyMW(http.HandlerFunc(hh.RevertFilm))))
+	mux.Handle("GET /films/{id}/relations", logMW(authMW(http.HandlerFunc(fh.GetFilmRelations))))
+	mux.Handle("PUT /films/{id}/relations", logMW(adminOnlyMW(http.HandlerFunc(fh.AddFilmRelations))))
+	mux.Handle("DELETE /films/{id}/relations", logMW(adminOnlyMW(http.HandlerFunc(fh.DeleteFilmRelations))))
+	mux.Handle("PUT /films/{id}/my-rating", logMW(authMW(http.HandlerFunc(fh.SetUserRating))))
+	mux.Handle("DELETE /films/{id}/my-rating", logMW(authMW(http.HandlerFunc(fh.DeleteUserRating))))
+	mux.Handle("GET /films/{id}/reviews", logMW(authMW(http.HandlerFunc(rh.GetFilmReviews))))
+	mux.Handle("POST /films/{id}/reviews", logMW(authMW(http.HandlerFunc(rh.Add))))
+
+	mux.Handle("GET /reviews/{id}", logMW(authMW(http.HandlerFunc(rh.Get))))
+	mux.Handle("PUT /reviews/{id}", logMW(authMW(http.HandlerFunc(rh.Update))))
+	mux.Handle("DELETE /reviews/{id}", logMW(authMW(http.HandlerFunc(rh.Delete))))
+	mux.Handle("GET /moderation/reviews", logMW(adminOnlyMW(http.HandlerFunc(rh.GetModerationQueue))))
+	mux.Handle("PUT /moderation/reviews/{id}", logMW(adminOnlyMW(http.HandlerFunc(rh.Moderate))))
+
+	mux.Handle("GET /me/watchlist", logMW(authMW(http.HandlerFunc(wh.GetWatchlist))))
+	mux.Handle("PUT /me/watchlist/{filmId}", logMW(authMW(http.HandlerFunc(wh.AddToWatchlist))))
+	mux.Handle("DELETE /me/watchlist/{filmId}", logMW(authMW(http.HandlerFunc(wh.DeleteFromWatchlist))))
+	mux.Handle("GET /me/diary", logMW(authMW(http.HandlerFunc(wh.GetDiary))))
+	mux.Handle("POST /me/diary", logMW(authMW(http.HandlerFunc(wh.AddDiaryEntry))))
+	mux.Handle("GET /me/diary/{id}", logMW(authMW(http.HandlerFunc(wh.GetDiaryEntry))))
+	mux.Handle("PUT /me/diary/{id}", logMW(authMW(http.HandlerFunc(wh.UpdateDiaryEntry))))
+	mux.Handle("DELETE /me/diary/{id}", logMW(authMW(http.HandlerFunc(wh.DeleteDiaryEntry))))
+
+	mux.Handle("GET /me/collections", logMW(authMW(http.HandlerFunc(ch.GetOwn))))
+	mux.Handle("GET /collections", logMW(authMW(http.HandlerFunc(ch.GetPublic))))
+	mux.Handle("POST /collections", logMW(authMW(http.HandlerFunc(ch.Add))))
+	mux.Handle("GET /collections/{id}", logMW(authMW(http.HandlerFunc(ch.Get))))
+	mux.Handle("PUT /collections/{id}", logMW(authMW(http.HandlerFunc(ch.Update))))
+	mux.Handle("DELETE /collections/{id}", logMW(authMW(http.HandlerFunc(ch.Delete))))
+	mux.Handle("POST /collections/{id}/share-link", logMW(authMW(http.HandlerFunc(ch.ResetShareLink))))
+	mux.Handle("PUT /collections/{id}/order", logMW(authMW(http.HandlerFunc(ch.ReorderEntries))))
+	mux.Handle("PUT /collections/{id}/entries/{filmId}", logMW(authMW(http.HandlerFunc(ch.PutEntry))))
+	mux.Handle("DELETE /collections/{id}/entries/{filmId}", logMW(authMW(http.HandlerFunc(ch.DeleteEntry))))
+	mux.Handle("GET /shared/collections/{token}", logMW(http.HandlerFunc(ch.GetShared)))
+
+	mux.Handle("GET /franchises", logMW(authMW(http.HandlerFunc(frh.GetAll))))
+	mux.Handle("POST /franchises", logMW(adminOnlyMW(http.HandlerFunc(frh.Add))))
+	mux.Handle("GET /franchises/{id}", logMW(authMW(http.HandlerFunc(frh.Get))))
+	mux.Handle("PUT /franchises/{id}", logMW(adminOnlyMW(http.HandlerFunc(frh.Update))))
+	mux.Handle("DELETE /franchises/{id}", logMW(adminOnlyMW(http.HandlerFunc(frh.Delete))))
+	mux.Handle("PUT /franchises/{id}/films/{filmId}", logMW(adminOnlyMW(http.HandlerFunc(frh.PutFilm))))
+	mux.Handle("DELETE /franchises/{id}/films/{filmId}", logMW(adminOnlyMW(http.HandlerFunc(frh.DeleteFilm))))
+
+	mux.Handle("GET /genres", logMW(authMW(http.HandlerFunc(gh.GetAll))))
+	mux.Handle("POST /genres", logMW(adminOnlyMW(http.HandlerFunc(gh.Add))))
+	mux.Handle("GET /genres/{id}", logMW(authMW(http.HandlerFunc(gh.Get))))
+	mux.Handle("PUT /genres/{id}", logMW(adminOnlyMW(http.HandlerFunc(gh.Update))))
+	mux.Handle("DELETE /genres/{id}", logMW(adminOnlyMW(http.HandlerFunc(gh.Delete))))
+
+	mux.Handle("POST /import/films", logMW(adminOnlyMW(http.HandlerFunc(ih.ImportFilms))))
+	mux.Handle("GET /export/films", logMW(adminOnlyMW(http.HandlerFunc(eh.ExportFilms))))
+	mux.Handle("GET /export/actors", logMW(adminOnlyMW(http.HandlerFunc(eh.ExportActors))))
+
+	mux.Handle("GET /trash", logMW(adminOnlyMW(http.HandlerFunc(th.GetAll))))
+	mux.Handle("POST /trash/purge", logMW(adminOnlyMW(http.HandlerFunc(th.Purge))))
+	mux.Handle("POST /trash/films/{id}/restore", logMW(adminOnlyMW(http.HandlerFunc(th.RestoreFilm))))
+	mux.Handle("DELETE /trash/films/{id}", logMW(adminOnlyMW(http.HandlerFunc(th.PurgeFilm))))
+	mux.Handle("POST /trash/actors/{id}/restore", logMW(adminOnlyMW(http.HandlerFunc(th.RestoreActor))))
+	mux.Handle("DELETE /trash/actors/{id}", logMW(adminOnlyMW(http.HandlerFunc(th.PurgeActor))))
+
+	mux.Handle("GET /search", logMW(authMW(http.HandlerFunc(sh.Search))))
 
 	return &Router{
-		mux: mux,
+		mux:     mux,
+		handler: idempotencyMW(mux),
 	}
 }
 
 func (r *Router) Run(addr string) error {
-	return http.ListenAndServe(addr, r.mux)
+	return http.ListenAndServe(addr, r.handler)
 }
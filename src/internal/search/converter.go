@@ -0,0 +1,36 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+
+	"film-library/src/internal/tools"
+)
+
+func ToQuery(req *SearchRequest) *Query {
+	types := []string{TypeFilm, TypeActor}
+	if len(req.TypeQuery) != 0 {
+		types = strings.Split(req.TypeQuery, ",")
+	}
+
+	limit := tools.DefaultPageLimit
+	if len(req.LimitQuery) != 0 {
+		limit, _ = strconv.Atoi(req.LimitQuery)
+	}
+
+	return &Query{
+		Text:  strings.TrimSpace(req.TextQuery),
+		Types: types,
+		Limit: limit,
+	}
+}
+
+func ToResultResponse(r *Result) *ResultResponse {
+	return &ResultResponse{
+		Type:    r.Type,
+		ID:      r.ID,
+		Name:    r.Name,
+		Snippet: r.Snippet,
+		Rank:    r.Rank,
+	}
+}
@@ -0,0 +1,47 @@
+package search
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+var _ SearchHandler = (*Handler)(nil)
+
+type Handler struct {
+	service SearchService
+}
+
+func NewHandler(ss SearchService) *Handler {
+	return &Handler{
+		service: ss,
+	}
+}
+
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	res, err := h.service.Search(r.Context(), &SearchRequest{
+		TextQuery:  query.Get("q"),
+		TypeQuery:  query.Get("type"),
+		LimitQuery: query.Get("limit"),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to search err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"film-library/src/internal/db"
+	"github.com/lib/pq"
+)
+
+var _ SearchRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) Search(ctx context.Context, q *Query) ([]*Result, error) {
+	const op = "search.Repository.Search"
+
+	// films are searched with english stemming while actor names are
+	// matched word by word, both are ranked against each other
+	const query = `
+		SELECT type, id, name, snippet, rank FROM (
+			SELECT 'film' AS type, m.movie_id AS id, m.movie_name AS name,
+				ts_headline('english', m.movie_name || '. ' || m.movie_description, fq,
+					'MaxFragments=2, MaxWords=20, MinWords=5') AS snippet,
+				ts_rank(m.search_vector, fq) AS rank
+			FROM movie m, websearch_to_tsquery('english', $1) fq
+			WHERE 'film' = ANY ($2) AND m.search_vector @@ fq AND m.deleted_at IS NULL
+			UNION ALL
+			SELECT 'actor', a.actor_id, a.actor_name,
+				ts_headline('simple', a.actor_name, aq),
+				ts_rank(a.search_vector, aq)
+			FROM actor a, websearch_to_tsquery('simple', $1) aq
+			WHERE 'actor' = ANY ($2) AND a.search_vector @@ aq AND a.deleted_at IS NULL
+		) r
+		ORDER BY rank DESC, type, id
+		LIMIT $3`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, q.Text, pq.Array(q.Types), q.Limit)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var results []*Result
+	for rows.Next() {
+		var res Result
+		err := rows.Scan(&res.Type, &res.ID, &res.Name, &res.Snippet, &res.Rank)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		results = append(results, &res)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
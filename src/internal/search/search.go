@@ -0,0 +1,55 @@
+package search
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	TypeFilm  = "film"
+	TypeActor = "actor"
+)
+
+type Result struct {
+	Type    string  `json:"type"`
+	ID      int     `json:"id"`
+	Name    string  `json:"name"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, q *Query) ([]*Result, error)
+}
+
+type SearchService interface {
+	Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
+}
+
+type SearchHandler interface {
+	Search(w http.ResponseWriter, r *http.Request)
+}
+
+type Query struct {
+	Text  string
+	Types []string
+	Limit int
+}
+
+type SearchRequest struct {
+	TextQuery  string
+	TypeQuery  string
+	LimitQuery string
+}
+
+type SearchResponse struct {
+	Results []*ResultResponse `json:"results"`
+}
+
+type ResultResponse struct {
+	Type    string  `json:"type"`
+	ID      int     `json:"id"`
+	Name    string  `json:"name"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+var _ SearchService = (*Service)(nil)
+
+type Service struct {
+	repo SearchRepository
+}
+
+func NewService(sr SearchRepository) *Service {
+	return &Service{
+		repo: sr,
+	}
+}
+
+func (s *Service) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	const op = "search.Service.Search"
+
+	vErr := ValidateSearchRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	q := ToQuery(req)
+
+	results, err := s.repo.Search(ctx, q)
+	if err != nil {
+		log.Printf("ERROR: failed to search repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := &SearchResponse{
+		Results: make([]*ResultResponse, 0, len(results)),
+	}
+	for _, v := range results {
+		res.Results = append(res.Results, ToResultResponse(v))
+	}
+
+	return res, nil
+}
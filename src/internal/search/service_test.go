@@ -0,0 +1,180 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"film-library/src/internal/tools"
+)
+
+// memoryRepository searches results held in memory, a result matches when
+// its name contains the query text and is ranked as the database would.
+type memoryRepository struct {
+	results []*Result
+	queries []*Query
+}
+
+func (r *memoryRepository) Search(ctx context.Context, q *Query) ([]*Result, error) {
+	r.queries = append(r.queries, q)
+
+	var found []*Result
+	for _, v := range r.results {
+		if slices.Contains(q.Types, v.Type) && strings.Contains(strings.ToLower(v.Name), strings.ToLower(q.Text)) {
+			found = append(found, v)
+		}
+	}
+
+	slices.SortFunc(found, func(a, b *Result) int {
+		switch {
+		case a.Rank != b.Rank:
+			if a.Rank > b.Rank {
+				return -1
+			}
+			return 1
+		case a.Type != b.Type:
+			return strings.Compare(a.Type, b.Type)
+		default:
+			return a.ID - b.ID
+		}
+	})
+
+	if len(found) > q.Limit {
+		found = found[:q.Limit]
+	}
+
+	return found, nil
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		results: []*Result{
+			{Type: TypeFilm, ID: 1, Name: "Star Wars", Rank: 0.5},
+			{Type: TypeFilm, ID: 2, Name: "Star Trek", Rank: 0.9},
+			{Type: TypeActor, ID: 1, Name: "Ringo Starr", Rank: 0.5},
+			{Type: TypeActor, ID: 2, Name: "Harrison Ford", Rank: 0.7},
+			{Type: TypeFilm, ID: 3, Name: "A Star Is Born", Rank: 0.1},
+		},
+	}
+}
+
+func resultKeys(res *SearchResponse) []string {
+	keys := make([]string, 0, len(res.Results))
+	for _, v := range res.Results {
+		keys = append(keys, v.Type+":"+v.Name)
+	}
+
+	return keys
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *SearchRequest
+		want []string
+	}{
+		{
+			name: "ranked across types",
+			req:  &SearchRequest{TextQuery: "star"},
+			want: []string{"film:Star Trek", "actor:Ringo Starr", "film:Star Wars", "film:A Star Is Born"},
+		},
+		{
+			name: "films only",
+			req:  &SearchRequest{TextQuery: "star", TypeQuery: "film"},
+			want: []string{"film:Star Trek", "film:Star Wars", "film:A Star Is Born"},
+		},
+		{
+			name: "actors only",
+			req:  &SearchRequest{TextQuery: "star", TypeQuery: "actor"},
+			want: []string{"actor:Ringo Starr"},
+		},
+		{
+			name: "both types",
+			req:  &SearchRequest{TextQuery: "r", TypeQuery: "actor,film"},
+			want: []string{"film:Star Trek", "actor:Harrison Ford", "actor:Ringo Starr", "film:Star Wars", "film:A Star Is Born"},
+		},
+		{
+			name: "limited",
+			req:  &SearchRequest{TextQuery: "star", LimitQuery: "2"},
+			want: []string{"film:Star Trek", "actor:Ringo Starr"},
+		},
+		{
+			name: "trimmed",
+			req:  &SearchRequest{TextQuery: "  ford "},
+			want: []string{"actor:Harrison Ford"},
+		},
+		{
+			name: "nothing found",
+			req:  &SearchRequest{TextQuery: "matrix"},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(newMemoryRepository())
+
+			res, err := s.Search(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Search() err = %v", err)
+			}
+			if got := resultKeys(res); !slices.Equal(got, tt.want) {
+				t.Errorf("Search() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchDefaultLimit(t *testing.T) {
+	repo := newMemoryRepository()
+	s := NewService(repo)
+
+	if _, err := s.Search(context.Background(), &SearchRequest{TextQuery: "star"}); err != nil {
+		t.Fatalf("Search() err = %v", err)
+	}
+	if q := repo.queries[0]; q.Limit != tools.DefaultPageLimit {
+		t.Errorf("limit = %d, want %d", q.Limit, tools.DefaultPageLimit)
+	}
+}
+
+func TestSearchValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *SearchRequest
+		want string
+	}{
+		{"empty text", &SearchRequest{TextQuery: "  "}, "search query empty"},
+		{"long text", &SearchRequest{TextQuery: strings.Repeat("a", 201)}, "search query length is more than 200 symbols"},
+		{"unknown type", &SearchRequest{TextQuery: "a", TypeQuery: "genre"}, "incorrect type query"},
+		{"repeated separator", &SearchRequest{TextQuery: "a", TypeQuery: "film,"}, "incorrect type query"},
+		{"zero limit", &SearchRequest{TextQuery: "a", LimitQuery: "0"}, "incorrect limit"},
+		{"large limit", &SearchRequest{TextQuery: "a", LimitQuery: "101"}, "incorrect limit"},
+		{"non numeric limit", &SearchRequest{TextQuery: "a", LimitQuery: "ten"}, "incorrect limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			s := NewService(repo)
+
+			_, err := s.Search(context.Background(), tt.req)
+
+			var ve *tools.ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("Search() err = %v, want validation error", err)
+			}
+			if !strings.Contains(ve.Error(), tt.want) {
+				t.Errorf("Search() err = %q, want it to mention %q", ve.Error(), tt.want)
+			}
+			if len(repo.queries) != 0 {
+				t.Errorf("repository searched %d times, want 0", len(repo.queries))
+			}
+		})
+	}
+
+	if ve := ValidateSearchRequest(&SearchRequest{TextQuery: "a", TypeQuery: "film,actor", LimitQuery: "100"}); ve != nil {
+		t.Errorf("ValidateSearchRequest() = %v, want nil", ve)
+	}
+}
@@ -0,0 +1,40 @@
+package search
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"film-library/src/internal/tools"
+)
+
+var validTypeQuery = regexp.MustCompile("^(film|actor)(,(film|actor))?$")
+
+func ValidateSearchRequest(req *SearchRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(strings.TrimSpace(req.TextQuery)) == 0 {
+		ve.AddViolation("search query empty")
+	}
+
+	if len(req.TextQuery) > 200 {
+		ve.AddViolation("search query length is more than 200 symbols")
+	}
+
+	if len(req.TypeQuery) != 0 && !validTypeQuery.MatchString(req.TypeQuery) {
+		ve.AddViolation("incorrect type query, expect value of pattern: '^(film|actor)(,(film|actor))?$'")
+	}
+
+	if len(req.LimitQuery) != 0 {
+		n, err := strconv.Atoi(req.LimitQuery)
+		if err != nil || n < 1 || n > tools.MaxPageLimit {
+			ve.AddViolation("incorrect limit, expected: 1 <= limit <= " + strconv.Itoa(tools.MaxPageLimit))
+		}
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrCursorInvalid = errors.New("invalid cursor")
+)
+
+// Cursor points at a single row of an ordered listing: Value holds the sort
+// key of the row and ID breaks ties between rows sharing that key.
+type Cursor struct {
+	Sort  string `json:"s,omitempty"`
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+}
+
+func EncodeCursor(c *Cursor) string {
+	jsonBytes, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(jsonBytes)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	jsonBytes, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrCursorInvalid
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(jsonBytes, &c); err != nil {
+		return nil, ErrCursorInvalid
+	}
+	if c.ID <= 0 {
+		return nil, ErrCursorInvalid
+	}
+
+	return &c, nil
+}
+
+// PageLinks reports whether the pages after and before the current one exist,
+// given that the page was fetched with one row more than requested.
+func PageLinks(hasMore, backward, hasCursor bool) (next, prev bool) {
+	if backward {
+		return hasCursor, hasMore
+	}
+
+	return hasMore, hasCursor
+}
+
+// SetPageLinks sets a Link header pointing at the pages after and before the
+// current one, so that pages can be followed whatever the format of the body.
+// The links keep the query of r with its cursor replaced.
+func SetPageLinks(w http.ResponseWriter, r *http.Request, next, prev string) {
+	for _, v := range []struct{ rel, param, cursor string }{
+		{"next", "after", next},
+		{"prev", "before", prev},
+	} {
+		if len(v.cursor) == 0 {
+			continue
+		}
+
+		query := r.URL.Query()
+		query.Del("after")
+		query.Del("before")
+		query.Set(v.param, v.cursor)
+
+		w.Header().Add("link", "<"+r.URL.Path+"?"+query.Encode()+`>; rel="`+v.rel+`"`)
+	}
+}
+
+func ValidatePageQuery(ve *ValidationError, limit, after, before, total string) {
+	if len(limit) != 0 {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxPageLimit {
+			ve.AddViolation("incorrect limit, expected: 1 <= limit <= " + strconv.Itoa(MaxPageLimit))
+		}
+	}
+
+	if len(after) != 0 && len(before) != 0 {
+		ve.AddViolation("'after' and 'before' cursors are mutually exclusive")
+	}
+
+	for _, v := range []string{after, before} {
+		if _, err := DecodeCursor(v); err != nil && len(v) != 0 {
+			ve.AddViolation("incorrect cursor")
+		}
+	}
+
+	if len(total) != 0 && total != "true" && total != "false" {
+		ve.AddViolation("incorrect total query, expected one of [true, false]")
+	}
+}
+
+// ToPageQuery converts validated pagination query parameters into the page
+// size, the cursor to start from and the direction of traversal.
+func ToPageQuery(limit, after, before string) (int, *Cursor, bool) {
+	n := DefaultPageLimit
+	if len(limit) != 0 {
+		n, _ = strconv.Atoi(limit)
+	}
+
+	if len(before) != 0 {
+		c, _ := DecodeCursor(before)
+		return n, c, true
+	}
+
+	if len(after) != 0 {
+		c, _ := DecodeCursor(after)
+		return n, c, false
+	}
+
+	return n, nil, false
+}
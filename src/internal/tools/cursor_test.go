@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSetPageLinks(t *testing.T) {
+	tests := []struct {
+		target     string
+		next, prev string
+		want       []string
+	}{
+		{"/films?sort=name&genre=1&genre=2", "n", "", []string{
+			`</films?after=n&genre=1&genre=2&sort=name>; rel="next"`,
+		}},
+		{"/films?after=a&limit=5", "n", "p", []string{
+			`</films?after=n&limit=5>; rel="next"`,
+			`</films?before=p&limit=5>; rel="prev"`,
+		}},
+		{"/actors?before=b", "", "p", []string{
+			`</actors?before=p>; rel="prev"`,
+		}},
+		{"/actors", "", "", nil},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		SetPageLinks(w, httptest.NewRequest("GET", tt.target, nil), tt.next, tt.prev)
+
+		if got := w.Header().Values("link"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SetPageLinks(%q, %q, %q) = %q, want %q", tt.target, tt.next, tt.prev, got, tt.want)
+		}
+	}
+}
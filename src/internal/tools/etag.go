@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ETag formats a record version as a strong entity tag.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// VariantETag formats a record version as a strong entity tag of one of its
+// representations, variant telling apart those that depend on the caller.
+func VariantETag(version int, variant string) string {
+	return `"` + strconv.Itoa(version) + "-" + variant + `"`
+}
+
+// MatchVersion tells whether a tag of version, of any variant, is listed in
+// an If-Match header value, "*" matching any. Weak tags are skipped.
+func MatchVersion(header string, version int) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" {
+			return true
+		}
+
+		if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+			continue
+		}
+
+		tag, _, _ := strings.Cut(v[1:len(v)-1], "-")
+		if tag == strconv.Itoa(version) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchETag tells whether etag is listed in an If-Match or If-None-Match
+// header value, "*" matching any. If-Match compares tags strongly so weak
+// tags of the header are skipped unless weak is set, as for If-None-Match.
+func MatchETag(header, etag string, weak bool) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" {
+			return true
+		}
+
+		if strings.HasPrefix(v, "W/") {
+			if !weak {
+				continue
+			}
+			v = v[len("W/"):]
+		}
+
+		if v == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotModified sets etag on the response and answers 304 when it matches the
+// If-None-Match header of the request, the caller has nothing more to
+// write when true is returned.
+func NotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("etag", etag)
+	if header := r.Header.Get("if-none-match"); header == "" || !MatchETag(header, etag, true) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+
+	return true
+}
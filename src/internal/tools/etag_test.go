@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestMatchVersion(t *testing.T) {
+	tests := []struct {
+		header  string
+		version int
+		want    bool
+	}{
+		{`"3"`, 3, true},
+		{`"3-w1l0"`, 3, true},
+		{`"2", "3-w0l0"`, 3, true},
+		{`*`, 3, true},
+		{`"4"`, 3, false},
+		{`"34"`, 3, false},
+		{`W/"3"`, 3, false},
+		{`3`, 3, false},
+		{``, 3, false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchVersion(tt.header, tt.version); got != tt.want {
+			t.Errorf("MatchVersion(%q, %d) = %v, want %v", tt.header, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	tests := []struct {
+		header string
+		etag   string
+		weak   bool
+		want   bool
+	}{
+		{`"3"`, `"3"`, false, true},
+		{`"3-w1l0"`, `"3-w0l0"`, true, false},
+		{`W/"3"`, `"3"`, false, false},
+		{`W/"3"`, `"3"`, true, true},
+		{`"1", "3"`, `"3"`, false, true},
+		{`*`, `"3"`, false, true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchETag(tt.header, tt.etag, tt.weak); got != tt.want {
+			t.Errorf("MatchETag(%q, %q, %v) = %v, want %v", tt.header, tt.etag, tt.weak, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"time"
@@ -12,7 +13,7 @@ var (
 	ErrUnknownClaimsType = errors.New("unknown claims type, cannot proceed")
 )
 
-var JWTContextKey struct{}
+type userClaimsKey struct{}
 
 type UserClaims struct {
 	ID       int    `json:"id"`
@@ -57,6 +58,18 @@ func ParseUserClaims(ss string, key string) (*UserClaims, error) {
 	}
 }
 
+// ContextWithUserClaims returns a copy of ctx carrying claims of the
+// authenticated user.
+func ContextWithUserClaims(ctx context.Context, uc *UserClaims) context.Context {
+	return context.WithValue(ctx, userClaimsKey{}, uc)
+}
+
+// UserClaimsFromContext returns claims put into ctx by the auth middleware.
+func UserClaimsFromContext(ctx context.Context) (*UserClaims, bool) {
+	uc, ok := ctx.Value(userClaimsKey{}).(*UserClaims)
+	return uc, ok
+}
+
 func SetJWTCookie(w http.ResponseWriter, token string) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "jwt",
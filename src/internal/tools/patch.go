@@ -0,0 +1,418 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+var (
+	ErrPatchUnsupported = errors.New("unsupported patch media type")
+	ErrPatchInvalid     = errors.New("invalid patch document")
+	ErrPatchConflict    = errors.New("patch does not apply")
+)
+
+// BindPatch reads a JSON merge patch or JSON patch document from the
+// request, 415 is returned for other media types with the supported ones
+// listed in Accept-Patch.
+func BindPatch(w http.ResponseWriter, r *http.Request) (string, []byte, bool) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("content-type"))
+	if err != nil || (mediaType != MediaTypeMergePatch && mediaType != MediaTypeJSONPatch) {
+		log.Printf("ERROR: unsupported patch media type %q\n", r.Header.Get("content-type"))
+
+		supported := MediaTypeMergePatch + ", " + MediaTypeJSONPatch
+		w.Header().Set("accept-patch", supported)
+		w.Header().Set("content-type", "text/plain")
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		w.Write([]byte("supported types: " + supported))
+		return "", nil, false
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("ERROR: failed to read request body err=%s\n", err.Error())
+		InternalServerError(w, r)
+		return "", nil, false
+	}
+
+	if len(bytes.TrimSpace(patch)) == 0 {
+		w.Header().Set("content-type", "text/plain")
+		BadRequest(w, r)
+		w.Write([]byte("empty request"))
+		return "", nil, false
+	}
+
+	if !json.Valid(patch) {
+		w.Header().Set("content-type", "text/plain")
+		BadRequest(w, r)
+		w.Write([]byte("invalid json"))
+		return "", nil, false
+	}
+
+	return mediaType, patch, true
+}
+
+// ApplyPatch applies patch of mediaType to the JSON encoding of src and
+// decodes the result into dst. Members removed or set to null by the patch
+// keep zero values of dst, so dst is expected to be empty. A result not
+// fitting dst is reported as a ValidationError.
+func ApplyPatch(mediaType string, src any, patch []byte, dst any) error {
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+
+	switch mediaType {
+	case MediaTypeMergePatch:
+		var p any
+		if err := json.Unmarshal(patch, &p); err != nil {
+			return fmt.Errorf("%w: %s", ErrPatchInvalid, err.Error())
+		}
+		doc = mergePatch(doc, p)
+	case MediaTypeJSONPatch:
+		var ops []*patchOperation
+		if err := json.Unmarshal(patch, &ops); err != nil {
+			return fmt.Errorf("%w: expected an array of operations", ErrPatchInvalid)
+		}
+		if doc, err = jsonPatch(doc, ops); err != nil {
+			return err
+		}
+	default:
+		return ErrPatchUnsupported
+	}
+
+	raw, err = json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		ve := &ValidationError{}
+		ve.AddViolation("patched document is malformed: " + strings.TrimPrefix(err.Error(), "json: "))
+		return ve
+	}
+
+	return nil
+}
+
+// mergePatch applies a JSON merge patch as of RFC 7396.
+func mergePatch(doc, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	d, ok := doc.(map[string]any)
+	if !ok {
+		d = make(map[string]any)
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(d, k)
+			continue
+		}
+		d[k] = mergePatch(d[k], v)
+	}
+
+	return d
+}
+
+// patchOperation is a single operation of a JSON patch, Value is kept raw
+// to tell a null value from a missing one.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// jsonPatch applies a JSON patch as of RFC 6902, operations are applied in
+// order and the first failing one fails the whole patch.
+func jsonPatch(doc any, ops []*patchOperation) (any, error) {
+	for i, op := range ops {
+		var err error
+		doc, err = op.apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func (op *patchOperation) apply(doc any) (any, error) {
+	if op == nil || op.Path == nil {
+		return nil, fmt.Errorf("%w: missing path", ErrPatchInvalid)
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var from []string
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrPatchInvalid)
+		}
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: missing from", ErrPatchInvalid)
+		}
+		if from, err = parsePointer(*op.From); err != nil {
+			return nil, err
+		}
+	case "remove":
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrPatchInvalid, op.Op)
+	}
+
+	var value any
+	if op.Value != nil {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrPatchInvalid, err.Error())
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return addValue(doc, path, value)
+	case "remove":
+		doc, _, err = removeValue(doc, path)
+		return doc, err
+	case "replace":
+		// the whole document is replaced as it is, there is nothing to remove
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, _, err = removeValue(doc, path); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case "move":
+		if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+			return nil, fmt.Errorf("%w: cannot move %s into itself", ErrPatchInvalid, *op.From)
+		}
+		if doc, value, err = removeValue(doc, from); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case "copy":
+		if value, err = getValue(doc, from); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, copyValue(value))
+	default:
+		current, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, fmt.Errorf("%w: test of %s failed", ErrPatchConflict, *op.Path)
+		}
+		return doc, nil
+	}
+}
+
+// parsePointer splits a JSON pointer as of RFC 6901 into unescaped
+// reference tokens, the empty pointer refers to the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if len(pointer) == 0 {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: pointer %q does not start with /", ErrPatchInvalid, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, v := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(v, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex resolves a reference token within an array of n elements, "-"
+// refers to the position past the last element.
+func arrayIndex(token string, n int) (int, error) {
+	if token == "-" {
+		return n, nil
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > n || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: no element %q in array", ErrPatchConflict, token)
+	}
+
+	return i, nil
+}
+
+func getValue(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch v := doc.(type) {
+		case map[string]any:
+			member, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: no member %q", ErrPatchConflict, token)
+			}
+			doc = member
+		case []any:
+			i, err := arrayIndex(token, len(v))
+			if err != nil {
+				return nil, err
+			}
+			if i == len(v) {
+				return nil, fmt.Errorf("%w: no element %q in array", ErrPatchConflict, token)
+			}
+			doc = v[i]
+		default:
+			return nil, fmt.Errorf("%w: no member %q in a scalar", ErrPatchConflict, token)
+		}
+	}
+
+	return doc, nil
+}
+
+// addValue returns doc with value added at path, members are replaced and
+// array elements are inserted before the one path refers to.
+func addValue(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token := path[0]
+	switch v := doc.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			v[token] = value
+			return v, nil
+		}
+
+		member, ok := v[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: no member %q", ErrPatchConflict, token)
+		}
+		member, err := addValue(member, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		v[token] = member
+
+		return v, nil
+	case []any:
+		i, err := arrayIndex(token, len(v))
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 1 {
+			v = append(v, nil)
+			copy(v[i+1:], v[i:])
+			v[i] = value
+			return v, nil
+		}
+
+		if i == len(v) {
+			return nil, fmt.Errorf("%w: no element %q in array", ErrPatchConflict, token)
+		}
+		if v[i], err = addValue(v[i], path[1:], value); err != nil {
+			return nil, err
+		}
+
+		return v, nil
+	default:
+		return nil, fmt.Errorf("%w: no member %q in a scalar", ErrPatchConflict, token)
+	}
+}
+
+// removeValue returns doc without the value at path together with the
+// removed value.
+func removeValue(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document", ErrPatchInvalid)
+	}
+
+	token := path[0]
+	switch v := doc.(type) {
+	case map[string]any:
+		member, ok := v[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: no member %q", ErrPatchConflict, token)
+		}
+		if len(path) == 1 {
+			delete(v, token)
+			return v, member, nil
+		}
+
+		member, removed, err := removeValue(member, path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		v[token] = member
+
+		return v, removed, nil
+	case []any:
+		i, err := arrayIndex(token, len(v))
+		if err != nil {
+			return nil, nil, err
+		}
+		if i == len(v) {
+			return nil, nil, fmt.Errorf("%w: no element %q in array", ErrPatchConflict, token)
+		}
+		if len(path) == 1 {
+			removed := v[i]
+			return append(v[:i], v[i+1:]...), removed, nil
+		}
+
+		element, removed, err := removeValue(v[i], path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		v[i] = element
+
+		return v, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: no member %q in a scalar", ErrPatchConflict, token)
+	}
+}
+
+// copyValue deep copies a decoded JSON value so that a copy shares no
+// maps or arrays with its source.
+func copyValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for k, member := range v {
+			res[k] = copyValue(member)
+		}
+		return res
+	case []any:
+		res := make([]any, len(v))
+		for i, element := range v {
+			res[i] = copyValue(element)
+		}
+		return res
+	default:
+		return v
+	}
+}
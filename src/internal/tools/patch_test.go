@@ -0,0 +1,306 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, s string) any {
+	t.Helper()
+
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("invalid test JSON %s: %v", s, err)
+	}
+
+	return v
+}
+
+// TestJSONPatch runs the examples of RFC 6902 Appendix A, numbered as there,
+// followed by cases of rules the examples do not cover.
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+		err   error
+	}{
+		{
+			name:  "A.1 adding an object member",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:  `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:  "A.2 adding an array element",
+			doc:   `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:  `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:  "A.3 removing an object member",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "/baz"}]`,
+			want:  `{"foo": "bar"}`,
+		},
+		{
+			name:  "A.4 removing an array element",
+			doc:   `{"foo": ["bar", "qux", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/1"}]`,
+			want:  `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:  "A.5 replacing a value",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			want:  `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:  "A.6 moving a value",
+			doc:   `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:  `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:  "A.7 moving an array element",
+			doc:   `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			want:  `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name:  "A.8 testing a value: success",
+			doc:   `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			want:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:  "A.9 testing a value: error",
+			doc:   `{"baz": "qux"}`,
+			patch: `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			err:   ErrPatchConflict,
+		},
+		{
+			name:  "A.10 adding a nested member object",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			want:  `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:  "A.11 ignoring unrecognized elements",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			want:  `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:  "A.12 adding to a nonexistent target",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			err:   ErrPatchConflict,
+		},
+		{
+			name:  "A.13 invalid JSON patch document",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "op": "remove"}]`,
+			err:   ErrPatchConflict,
+		},
+		{
+			name:  "A.14 ~ escape ordering",
+			doc:   `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": 10}]`,
+			want:  `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:  "A.15 comparing strings and numbers",
+			doc:   `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			err:   ErrPatchConflict,
+		},
+		{
+			name:  "A.16 adding an array value",
+			doc:   `{"foo": ["bar"]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			want:  `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+		{
+			name:  "replacing the whole document",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "", "value": {"baz": "qux"}}]`,
+			want:  `{"baz": "qux"}`,
+		},
+		{
+			name:  "removing the whole document",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "remove", "path": ""}]`,
+			err:   ErrPatchInvalid,
+		},
+		{
+			name:  "replacing a missing member",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": "qux"}]`,
+			err:   ErrPatchConflict,
+		},
+		{
+			name:  "moving a value into itself",
+			doc:   `{"foo": {"bar": {}}}`,
+			patch: `[{"op": "move", "from": "/foo", "path": "/foo/bar/baz"}]`,
+			err:   ErrPatchInvalid,
+		},
+		{
+			name:  "moving a value to its own place",
+			doc:   `{"foo": {"bar": 1}}`,
+			patch: `[{"op": "move", "from": "/foo", "path": "/foo"}]`,
+			want:  `{"foo": {"bar": 1}}`,
+		},
+		{
+			name:  "copying a value",
+			doc:   `{"foo": {"bar": [1]}}`,
+			patch: `[{"op": "copy", "from": "/foo", "path": "/baz"}, {"op": "add", "path": "/baz/bar/-", "value": 2}]`,
+			want:  `{"foo": {"bar": [1]}, "baz": {"bar": [1, 2]}}`,
+		},
+		{
+			name:  "array index with leading zero",
+			doc:   `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/01"}]`,
+			err:   ErrPatchConflict,
+		},
+		{
+			name:  "array index past the end",
+			doc:   `{"foo": ["bar"]}`,
+			patch: `[{"op": "add", "path": "/foo/2", "value": "baz"}]`,
+			err:   ErrPatchConflict,
+		},
+		{
+			name:  "removing past the last element",
+			doc:   `{"foo": ["bar"]}`,
+			patch: `[{"op": "remove", "path": "/foo/-"}]`,
+			err:   ErrPatchConflict,
+		},
+		{
+			name:  "adding a null value",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": null}]`,
+			want:  `{"foo": "bar", "baz": null}`,
+		},
+		{
+			name:  "missing value",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz"}]`,
+			err:   ErrPatchInvalid,
+		},
+		{
+			name:  "missing path",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "remove"}]`,
+			err:   ErrPatchInvalid,
+		},
+		{
+			name:  "pointer without leading slash",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "foo"}]`,
+			err:   ErrPatchInvalid,
+		},
+		{
+			name:  "unknown operation",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "rename", "path": "/foo"}]`,
+			err:   ErrPatchInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ops []*patchOperation
+			if err := json.Unmarshal([]byte(tt.patch), &ops); err != nil {
+				t.Fatalf("invalid test patch %s: %v", tt.patch, err)
+			}
+
+			got, err := jsonPatch(decodeJSON(t, tt.doc), ops)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("jsonPatch() err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("jsonPatch() err = %v", err)
+			}
+
+			if want := decodeJSON(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("jsonPatch() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestMergePatch runs the examples of RFC 7396 Appendix A.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{`{"a": "b"}`, `{"a": "c"}`, `{"a": "c"}`},
+		{`{"a": "b"}`, `{"b": "c"}`, `{"a": "b", "b": "c"}`},
+		{`{"a": "b"}`, `{"a": null}`, `{}`},
+		{`{"a": "b", "b": "c"}`, `{"a": null}`, `{"b": "c"}`},
+		{`{"a": ["b"]}`, `{"a": "c"}`, `{"a": "c"}`},
+		{`{"a": "c"}`, `{"a": ["b"]}`, `{"a": ["b"]}`},
+		{`{"a": {"b": "c"}}`, `{"a": {"b": "d", "c": null}}`, `{"a": {"b": "d"}}`},
+		{`{"a": [{"b": "c"}]}`, `{"a": [1]}`, `{"a": [1]}`},
+		{`["a", "b"]`, `["c", "d"]`, `["c", "d"]`},
+		{`{"a": "b"}`, `["c"]`, `["c"]`},
+		{`{"a": "foo"}`, `null`, `null`},
+		{`{"a": "foo"}`, `"bar"`, `"bar"`},
+		{`{"e": null}`, `{"a": 1}`, `{"e": null, "a": 1}`},
+		{`[1, 2]`, `{"a": "b", "c": null}`, `{"a": "b"}`},
+		{`{}`, `{"a": {"bb": {"ccc": null}}}`, `{"a": {"bb": {}}}`},
+	}
+
+	for _, tt := range tests {
+		got := mergePatch(decodeJSON(t, tt.doc), decodeJSON(t, tt.patch))
+
+		if want := decodeJSON(t, tt.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("mergePatch(%s, %s) = %v, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	type info struct {
+		Name   string   `json:"name"`
+		Rating int      `json:"rating"`
+		Genres []string `json:"genres"`
+	}
+	src := &info{Name: "Alien", Rating: 8, Genres: []string{"horror"}}
+
+	var merged info
+	if err := ApplyPatch(MediaTypeMergePatch, src, []byte(`{"rating": 9, "genres": null}`), &merged); err != nil {
+		t.Fatalf("ApplyPatch() err = %v", err)
+	}
+	if want := (info{Name: "Alien", Rating: 9}); !reflect.DeepEqual(merged, want) {
+		t.Errorf("ApplyPatch() = %+v, want %+v", merged, want)
+	}
+
+	var patched info
+	if err := ApplyPatch(MediaTypeJSONPatch, src, []byte(`[{"op": "add", "path": "/genres/-", "value": "sci-fi"}]`), &patched); err != nil {
+		t.Fatalf("ApplyPatch() err = %v", err)
+	}
+	if want := []string{"horror", "sci-fi"}; !reflect.DeepEqual(patched.Genres, want) {
+		t.Errorf("ApplyPatch() genres = %v, want %v", patched.Genres, want)
+	}
+
+	var unknown info
+	err := ApplyPatch(MediaTypeMergePatch, src, []byte(`{"director": "Scott"}`), &unknown)
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Errorf("ApplyPatch() err = %v for unknown member, want validation error", err)
+	}
+
+	var invalid info
+	if err := ApplyPatch(MediaTypeJSONPatch, src, []byte(`{"op": "remove", "path": "/name"}`), &invalid); !errors.Is(err, ErrPatchInvalid) {
+		t.Errorf("ApplyPatch() err = %v for patch not being an array, want %v", err, ErrPatchInvalid)
+	}
+}
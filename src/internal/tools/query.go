@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Cond is a piece of SQL boolean expression. Every user supplied value is
+// referenced by a '?' placeholder in expr and kept in args, placeholders are
+// numbered only when the whole statement is built, so conditions can be
+// freely composed with And and Or.
+type Cond struct {
+	expr string
+	args []any
+}
+
+func Expr(expr string, args ...any) Cond {
+	if n := strings.Count(expr, "?"); n != len(args) {
+		panic(fmt.Sprintf("tools.Expr: %d placeholders for %d arguments in %q", n, len(args), expr))
+	}
+
+	return Cond{
+		expr: expr,
+		args: args,
+	}
+}
+
+func And(conds ...Cond) Cond {
+	return join(" AND ", conds)
+}
+
+func Or(conds ...Cond) Cond {
+	return join(" OR ", conds)
+}
+
+func join(sep string, conds []Cond) Cond {
+	exprs := make([]string, 0, len(conds))
+	var args []any
+
+	for _, c := range conds {
+		if c.IsEmpty() {
+			continue
+		}
+
+		exprs = append(exprs, "("+c.expr+")")
+		args = append(args, c.args...)
+	}
+
+	return Cond{
+		expr: strings.Join(exprs, sep),
+		args: args,
+	}
+}
+
+// Nest embeds c into expr in place of its only %s verb, expr itself must not
+// contain placeholders. It allows conditions to be used within subqueries.
+func Nest(expr string, c Cond) Cond {
+	return Cond{
+		expr: fmt.Sprintf(expr, "("+c.expr+")"),
+		args: c.args,
+	}
+}
+
+func (c Cond) IsEmpty() bool {
+	return len(c.expr) == 0
+}
+
+type SelectBuilder struct {
+	base    string
+	where   []Cond
+	groupBy string
+	having  []Cond
+	orderBy []string
+	limit   int
+}
+
+// NewSelectBuilder starts a statement from base, which holds the SELECT list
+// together with the FROM and JOIN clauses and must not contain placeholders.
+func NewSelectBuilder(base string) *SelectBuilder {
+	return &SelectBuilder{
+		base: base,
+	}
+}
+
+func (sb *SelectBuilder) Where(c Cond) *SelectBuilder {
+	if !c.IsEmpty() {
+		sb.where = append(sb.where, c)
+	}
+
+	return sb
+}
+
+func (sb *SelectBuilder) GroupBy(expr string) *SelectBuilder {
+	sb.groupBy = expr
+
+	return sb
+}
+
+func (sb *SelectBuilder) Having(c Cond) *SelectBuilder {
+	if !c.IsEmpty() {
+		sb.having = append(sb.having, c)
+	}
+
+	return sb
+}
+
+// OrderBy appends ordering expressions, these are spliced into the statement
+// as is and therefore must never come from user input directly.
+func (sb *SelectBuilder) OrderBy(exprs ...string) *SelectBuilder {
+	sb.orderBy = append(sb.orderBy, exprs...)
+
+	return sb
+}
+
+func (sb *SelectBuilder) Limit(n int) *SelectBuilder {
+	sb.limit = n
+
+	return sb
+}
+
+// Build renders the statement with numbered placeholders and returns it
+// along with the values to bind.
+func (sb *SelectBuilder) Build() (string, []any) {
+	var b strings.Builder
+	var args []any
+
+	b.WriteString(sb.base)
+
+	if where := And(sb.where...); !where.IsEmpty() {
+		b.WriteString(" WHERE ")
+		args = writeCond(&b, where, args)
+	}
+
+	if len(sb.groupBy) != 0 {
+		b.WriteString(" GROUP BY ")
+		b.WriteString(sb.groupBy)
+	}
+
+	if having := And(sb.having...); !having.IsEmpty() {
+		b.WriteString(" HAVING ")
+		args = writeCond(&b, having, args)
+	}
+
+	if len(sb.orderBy) != 0 {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(strings.Join(sb.orderBy, ", "))
+	}
+
+	if sb.limit > 0 {
+		b.WriteString(" LIMIT ")
+		b.WriteString(strconv.Itoa(sb.limit))
+	}
+
+	return b.String(), args
+}
+
+func writeCond(b *strings.Builder, c Cond, args []any) []any {
+	parts := strings.Split(c.expr, "?")
+	for i, v := range parts {
+		if i > 0 {
+			args = append(args, c.args[i-1])
+			b.WriteString("$" + strconv.Itoa(len(args)))
+		}
+		b.WriteString(v)
+	}
+
+	return args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike makes s match itself literally when used as a LIKE pattern
+// with the default backslash escape character.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// ContainsPattern returns a LIKE pattern matching any string containing s.
+func ContainsPattern(s string) string {
+	return "%" + EscapeLike(s) + "%"
+}
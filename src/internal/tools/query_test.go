@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var hostileInputs = []string{
+	`'`,
+	`"`,
+	`%`,
+	`_`,
+	`\`,
+	`?`,
+	`;--`,
+	`$1`,
+	`' OR '1'='1`,
+	`'; DROP TABLE movie; --`,
+	`100%_off\`,
+	`what?? $2 ?`,
+	`/* comment */`,
+	"tab\tnewline\nnul\x00",
+}
+
+var placeholderRe = regexp.MustCompile(`\$(\d+)`)
+
+// checkPlaceholders fails unless query references each of args exactly once
+// and in order.
+func checkPlaceholders(t *testing.T, query string, args []any) {
+	t.Helper()
+
+	matches := placeholderRe.FindAllStringSubmatch(query, -1)
+	if len(matches) != len(args) {
+		t.Fatalf("%d placeholders for %d arguments in %q", len(matches), len(args), query)
+	}
+	for i, m := range matches {
+		if m[1] != strconv.Itoa(i+1) {
+			t.Fatalf("placeholder $%s found at position %d in %q", m[1], i+1, query)
+		}
+	}
+}
+
+// unescapedWildcard reports whether p holds a '%' or '_' that is not escaped
+// by a backslash, or ends with a dangling backslash.
+func unescapedWildcard(p string) bool {
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case '\\':
+			if i+1 == len(p) {
+				return true
+			}
+			i++
+		case '%', '_':
+			return true
+		}
+	}
+
+	return false
+}
+
+func unescapeLike(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		if p[i] == '\\' && i+1 < len(p) {
+			i++
+		}
+		b.WriteByte(p[i])
+	}
+
+	return b.String()
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"plain", "plain"},
+		{"%", `\%`},
+		{"_", `\_`},
+		{`\`, `\\`},
+		{`100%_off\`, `100\%\_off\\`},
+		{`\%`, `\\\%`},
+		{"'; --", "'; --"},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeLike(tt.in); got != tt.want {
+			t.Errorf("EscapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func FuzzEscapeLike(f *testing.F) {
+	for _, v := range hostileInputs {
+		f.Add(v)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		escaped := EscapeLike(s)
+		if unescapedWildcard(escaped) {
+			t.Fatalf("EscapeLike(%q) = %q leaves a wildcard unescaped", s, escaped)
+		}
+		if got := unescapeLike(escaped); got != s {
+			t.Fatalf("EscapeLike(%q) = %q does not match the input literally", s, escaped)
+		}
+
+		pattern := ContainsPattern(s)
+		if !strings.HasPrefix(pattern, "%") || !strings.HasSuffix(pattern, "%") {
+			t.Fatalf("ContainsPattern(%q) = %q is not wrapped in wildcards", s, pattern)
+		}
+		if inner := pattern[1 : len(pattern)-1]; inner != escaped {
+			t.Fatalf("ContainsPattern(%q) = %q, want %q inside wildcards", s, pattern, escaped)
+		}
+	})
+}
+
+func buildHostileQuery(s string) (string, []any) {
+	cond := And(
+		Expr("m.deleted_at IS NULL"),
+		Or(
+			Expr(`m.movie_name ILIKE ? ESCAPE '\'`, ContainsPattern(s)),
+			Expr("? <% m.movie_name", s),
+		),
+		Nest("EXISTS (SELECT 1 FROM actor a WHERE %s)", Expr("a.actor_name = ?", s)),
+	)
+
+	return NewSelectBuilder("SELECT m.movie_id FROM movie m").
+		Where(cond).
+		Where(Expr("(m.movie_name, m.movie_id) > (?, ?)", s, 1)).
+		GroupBy("m.movie_id").
+		Having(Expr("COUNT(*) > ?", s)).
+		OrderBy("m.movie_name ASC", "m.movie_id ASC").
+		Limit(21).
+		Build()
+}
+
+func FuzzSelectBuilderBuild(f *testing.F) {
+	for _, v := range hostileInputs {
+		f.Add(v)
+	}
+
+	want, _ := buildHostileQuery("x")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		query, args := buildHostileQuery(s)
+
+		if query != want {
+			t.Fatalf("input %q changed the statement:\n%s\nwant:\n%s", s, query, want)
+		}
+		checkPlaceholders(t, query, args)
+
+		wantArgs := []any{ContainsPattern(s), s, s, s, 1, s}
+		for i, v := range wantArgs {
+			if args[i] != v {
+				t.Fatalf("argument $%d = %#v, want %#v", i+1, args[i], v)
+			}
+		}
+	})
+}
+
+func TestSelectBuilderBuildHostileInput(t *testing.T) {
+	for _, s := range hostileInputs {
+		query, args := buildHostileQuery(s)
+		checkPlaceholders(t, query, args)
+
+		// single symbols like '_' are part of the statement itself, longer
+		// inputs must not show up in it at all
+		if len(s) > 2 && strings.Contains(query, s) {
+			t.Errorf("input %q found in statement %q", s, query)
+		}
+	}
+}
+
+func TestExprPlaceholderMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expr did not panic on placeholder and argument count mismatch")
+		}
+	}()
+
+	Expr("a = ? AND b = ?", 1)
+}
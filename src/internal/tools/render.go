@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	MediaTypeJSON = "application/json"
+	MediaTypeXML  = "application/xml"
+	MediaTypeCSV  = "text/csv"
+)
+
+// TabularOffers are the representations of CSVMarshalers.
+var TabularOffers = []string{MediaTypeJSON, MediaTypeXML, MediaTypeCSV}
+
+// CSVMarshaler is implemented by responses having a tabular form, lists
+// yield a record per item.
+type CSVMarshaler interface {
+	MarshalCSV() (header []string, records [][]string)
+}
+
+// StringMap is a map of strings rendered in XML as elements named after
+// the keys in key order.
+type StringMap map[string]string
+
+func (m StringMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		if err := e.EncodeElement(m[k], xml.StartElement{Name: xml.Name{Local: k}}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// Accept picks the offer preferred by the Accept header of the request, JSON
+// when no preference is given. It is called before the response is built so
+// that no work is done for a representation that is not acceptable, 406 is
+// written and an empty string returned then.
+func Accept(w http.ResponseWriter, r *http.Request, offers []string) string {
+	mediaType := Negotiate(r.Header.Get("accept"), offers)
+	if len(mediaType) == 0 {
+		w.Header().Set("content-type", "text/plain")
+		w.WriteHeader(http.StatusNotAcceptable)
+		w.Write([]byte("acceptable types: " + strings.Join(offers, ", ")))
+	}
+
+	return mediaType
+}
+
+// Render writes obj in mediaType picked by Accept, CSV is written only for
+// CSVMarshalers.
+func Render(w http.ResponseWriter, r *http.Request, mediaType string, statusCode int, obj any) {
+	switch mediaType {
+	case MediaTypeJSON:
+		jsonBytes, _ := json.Marshal(obj)
+		w.Header().Set("content-type", MediaTypeJSON)
+		w.WriteHeader(statusCode)
+		w.Write(jsonBytes)
+	case MediaTypeXML:
+		xmlBytes, err := xml.Marshal(obj)
+		if err != nil {
+			log.Printf("ERROR: failed to encode xml response err=%s\n", err.Error())
+			InternalServerError(w, r)
+			return
+		}
+		w.Header().Set("content-type", MediaTypeXML+"; charset=utf-8")
+		w.WriteHeader(statusCode)
+		w.Write([]byte(xml.Header))
+		w.Write(xmlBytes)
+	case MediaTypeCSV:
+		cm, ok := obj.(CSVMarshaler)
+		if !ok {
+			log.Printf("ERROR: %T has no csv representation\n", obj)
+			InternalServerError(w, r)
+			return
+		}
+		header, records := cm.MarshalCSV()
+		w.Header().Set("content-type", MediaTypeCSV+"; charset=utf-8")
+		w.WriteHeader(statusCode)
+		cw := csv.NewWriter(w)
+		cw.Write(header)
+		cw.WriteAll(records)
+	default:
+		log.Printf("ERROR: unknown media type %q\n", mediaType)
+		InternalServerError(w, r)
+	}
+}
+
+// MediaTypeVariant names mediaType within an entity tag variant, so that
+// representations of a record are tagged apart, e.g. "xml".
+func MediaTypeVariant(mediaType string) string {
+	_, subtype, _ := strings.Cut(mediaType, "/")
+
+	return subtype
+}
+
+// Negotiate picks the offer with the highest quality in the Accept header,
+// earlier offers win ties and an empty header accepts the first offer. An
+// empty string is returned when no offer is acceptable.
+func Negotiate(accept string, offers []string) string {
+	if len(strings.TrimSpace(accept)) == 0 {
+		return offers[0]
+	}
+
+	type mediaRange struct {
+		typ, subtype string
+		q            float64
+	}
+
+	var ranges []mediaRange
+	for _, v := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+
+		typ, subtype, _ := strings.Cut(mediaType, "/")
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qs, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{typ, subtype, q})
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		typ, subtype, _ := strings.Cut(offer, "/")
+
+		// the most specific matching range sets the quality of an offer
+		q, specificity := 0.0, -1
+		for _, v := range ranges {
+			var s int
+			switch {
+			case v.typ == typ && v.subtype == subtype:
+				s = 2
+			case v.typ == typ && v.subtype == "*":
+				s = 1
+			case v.typ == "*" && v.subtype == "*":
+				s = 0
+			default:
+				continue
+			}
+
+			if s > specificity {
+				q, specificity = v.q, s
+			}
+		}
+
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type tabular struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func (t *tabular) MarshalCSV() ([]string, [][]string) {
+	return []string{"name"}, [][]string{{t.Name}}
+}
+
+func TestAcceptAndRender(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"", MediaTypeJSON, `{"name":"Alien"}`},
+		{"application/xml", MediaTypeXML + "; charset=utf-8", `<?xml version="1.0" encoding="UTF-8"?>` + "\n<tabular><name>Alien</name></tabular>"},
+		{"text/csv;q=0.9, application/json;q=0.5", MediaTypeCSV + "; charset=utf-8", "name\nAlien\n"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("accept", tt.accept)
+		w := httptest.NewRecorder()
+
+		mediaType := Accept(w, r, TabularOffers)
+		Render(w, r, mediaType, http.StatusOK, &tabular{Name: "Alien"})
+
+		if ct := w.Header().Get("content-type"); ct != tt.contentType || w.Body.String() != tt.body {
+			t.Errorf("Accept: %q rendered %q %q, want %q %q", tt.accept, ct, w.Body, tt.contentType, tt.body)
+		}
+	}
+}
+
+func TestAcceptRefuses(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("accept", "image/png")
+	w := httptest.NewRecorder()
+
+	if mediaType := Accept(w, r, TabularOffers); mediaType != "" || w.Code != http.StatusNotAcceptable {
+		t.Errorf("Accept() = %q with status %d, want none with %d", mediaType, w.Code, http.StatusNotAcceptable)
+	}
+}
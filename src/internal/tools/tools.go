@@ -38,6 +38,10 @@ func Forbidden(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusForbidden)
 }
 
+func PreconditionFailed(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusPreconditionFailed)
+}
+
 func OK(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
@@ -0,0 +1,56 @@
+package trash
+
+import "time"
+
+func ToItemResponse(i *Item) *ItemResponse {
+	res := &ItemResponse{
+		ID:   i.ID,
+		Name: i.Name,
+	}
+	if !i.DeletedAt.IsZero() {
+		res.DeletedAt = i.DeletedAt.Format(time.RFC3339)
+	}
+
+	return res
+}
+
+// ToTrashResponse splits items by type keeping their order.
+func ToTrashResponse(items []*Item) *TrashResponse {
+	res := &TrashResponse{
+		Films:  []*ItemResponse{},
+		Actors: []*ItemResponse{},
+	}
+
+	for _, v := range items {
+		switch v.Type {
+		case TypeFilm:
+			res.Films = append(res.Films, ToItemResponse(v))
+		case TypeActor:
+			res.Actors = append(res.Actors, ToItemResponse(v))
+		}
+	}
+
+	return res
+}
+
+func ToPurgeResponse(before time.Time, items []*Item) *PurgeResponse {
+	tr := ToTrashResponse(items)
+
+	return &PurgeResponse{
+		Before: before.Format(time.RFC3339),
+		Films:  tr.Films,
+		Actors: tr.Actors,
+	}
+}
+
+// ToOlderThan parses a validated olderThan query, retention is used when
+// the query is empty.
+func ToOlderThan(query string, retention time.Duration) time.Duration {
+	if len(query) == 0 {
+		return retention
+	}
+
+	d, _ := time.ParseDuration(query)
+
+	return d
+}
@@ -0,0 +1,137 @@
+package trash
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+var _ TrashHandler = (*Handler)(nil)
+
+type Handler struct {
+	service TrashService
+}
+
+func NewHandler(ts TrashService) *Handler {
+	return &Handler{
+		service: ts,
+	}
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetAll(r.Context())
+	if err != nil {
+		log.Printf("ERROR: failed to get trash err=%s\n", err.Error())
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) RestoreFilm(w http.ResponseWriter, r *http.Request) {
+	req := TrashIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.RestoreFilm(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to restore film err=%s\n", err.Error())
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) RestoreActor(w http.ResponseWriter, r *http.Request) {
+	req := TrashIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.RestoreActor(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to restore actor err=%s\n", err.Error())
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrActorNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) PurgeFilm(w http.ResponseWriter, r *http.Request) {
+	req := TrashIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.PurgeFilm(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to purge film err=%s\n", err.Error())
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) PurgeActor(w http.ResponseWriter, r *http.Request) {
+	req := TrashIdRequest{
+		ID: r.PathValue("id"),
+	}
+
+	res, err := h.service.PurgeActor(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to purge actor err=%s\n", err.Error())
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrActorNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) Purge(w http.ResponseWriter, r *http.Request) {
+	req := PurgeRequest{
+		OlderThanQuery: r.URL.Query().Get("olderThan"),
+	}
+
+	res, err := h.service.Purge(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to purge trash err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
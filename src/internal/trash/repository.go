@@ -0,0 +1,293 @@
+package trash
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"film-library/src/internal/db"
+)
+
+var (
+	ErrFilmNotExist  = errors.New("film is not in trash")
+	ErrActorNotExist = errors.New("actor is not in trash")
+)
+
+var _ TrashRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) GetAll(ctx context.Context) ([]*Item, error) {
+	const op = "trash.Repository.GetAll"
+
+	const query = `
+		SELECT 'film', movie_id, movie_name, deleted_at, COALESCE(poster_key, '')
+		FROM movie
+		WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'actor', actor_id, actor_name, deleted_at, COALESCE(headshot_key, '')
+		FROM actor
+		WHERE deleted_at IS NOT NULL
+		ORDER BY 4 DESC, 1, 2`
+
+	items, err := r.queryItems(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+func (r *Repository) RestoreFilm(ctx context.Context, id int) (*Item, error) {
+	const op = "trash.Repository.RestoreFilm"
+
+	const query = `
+		UPDATE movie SET deleted_at = NULL
+		WHERE movie_id = $1 AND deleted_at IS NOT NULL
+		RETURNING 'film', movie_id, movie_name, NULL::timestamptz, COALESCE(poster_key, '')`
+
+	item, err := r.queryItem(ctx, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: film with id=%d is not in trash\n", id)
+			return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return item, nil
+}
+
+func (r *Repository) RestoreActor(ctx context.Context, id int) (*Item, error) {
+	const op = "trash.Repository.RestoreActor"
+
+	const query = `
+		UPDATE actor SET deleted_at = NULL
+		WHERE actor_id = $1 AND deleted_at IS NOT NULL
+		RETURNING 'actor', actor_id, actor_name, NULL::timestamptz, COALESCE(headshot_key, '')`
+
+	item, err := r.queryItem(ctx, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: actor with id=%d is not in trash\n", id)
+			return nil, fmt.Errorf("%s: %w", op, ErrActorNotExist)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return item, nil
+}
+
+// PurgeFilm removes a film in trash for good, its credits, ratings,
+// reviews and list entries go along with it.
+func (r *Repository) PurgeFilm(ctx context.Context, id int) (*Item, error) {
+	const op = "trash.Repository.PurgeFilm"
+
+	const query = `
+		DELETE FROM movie
+		WHERE movie_id = $1 AND deleted_at IS NOT NULL
+		RETURNING 'film', movie_id, movie_name, deleted_at, COALESCE(poster_key, '')`
+
+	item, err := r.queryItem(ctx, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: film with id=%d is not in trash\n", id)
+			return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return item, nil
+}
+
+func (r *Repository) PurgeActor(ctx context.Context, id int) (*Item, error) {
+	const op = "trash.Repository.PurgeActor"
+
+	const query = `
+		DELETE FROM actor
+		WHERE actor_id = $1 AND deleted_at IS NOT NULL
+		RETURNING 'actor', actor_id, actor_name, deleted_at, COALESCE(headshot_key, '')`
+
+	item, err := r.queryItem(ctx, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: actor with id=%d is not in trash\n", id)
+			return nil, fmt.Errorf("%s: %w", op, ErrActorNotExist)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return item, nil
+}
+
+// Purge removes films and actors deleted before the given time in a
+// single statement.
+func (r *Repository) Purge(ctx context.Context, before time.Time) ([]*Item, error) {
+	const op = "trash.Repository.Purge"
+
+	const query = `
+		WITH f AS (
+			DELETE FROM movie WHERE deleted_at < $1
+			RETURNING 'film' AS type, movie_id AS id, movie_name AS name, deleted_at, COALESCE(poster_key, '') AS media_key
+		), a AS (
+			DELETE FROM actor WHERE deleted_at < $1
+			RETURNING 'actor' AS type, actor_id AS id, actor_name AS name, deleted_at, COALESCE(headshot_key, '') AS media_key
+		)
+		SELECT type, id, name, deleted_at, media_key FROM f
+		UNION ALL
+		SELECT type, id, name, deleted_at, media_key FROM a
+		ORDER BY 4, 1, 2`
+
+	items, err := r.queryItems(ctx, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+func (r *Repository) GetActorFilms(ctx context.Context, id int) ([]int, error) {
+	const op = "trash.Repository.GetActorFilms"
+
+	const query = `
+		SELECT movie_id FROM movie
+		WHERE deleted_at IS NULL AND movie_id IN (
+			SELECT movie_id FROM actor_in_movie WHERE actor_id = $1
+			UNION
+			SELECT movie_id FROM movie_crew WHERE person_id = $1
+		)
+		ORDER BY movie_id`
+
+	ids, err := r.queryIDs(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+func (r *Repository) GetTrashedActorsFilms(ctx context.Context, before time.Time) ([]int, error) {
+	const op = "trash.Repository.GetTrashedActorsFilms"
+
+	const query = `
+		WITH a AS (
+			SELECT actor_id FROM actor WHERE deleted_at < $1
+		)
+		SELECT movie_id FROM movie
+		WHERE deleted_at IS NULL AND movie_id IN (
+			SELECT movie_id FROM actor_in_movie WHERE actor_id IN (SELECT actor_id FROM a)
+			UNION
+			SELECT movie_id FROM movie_crew WHERE person_id IN (SELECT actor_id FROM a)
+		)
+		ORDER BY movie_id`
+
+	ids, err := r.queryIDs(ctx, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+func (r *Repository) queryItem(ctx context.Context, query string, args ...any) (*Item, error) {
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var i Item
+	var deletedAt sql.NullTime
+	err = stmt.QueryRowContext(ctx, args...).Scan(&i.Type, &i.ID, &i.Name, &deletedAt, &i.MediaKey)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: failed to execute query\n")
+		}
+		return nil, err
+	}
+	i.DeletedAt = deletedAt.Time
+
+	return &i, nil
+}
+
+func (r *Repository) queryItems(ctx context.Context, query string, args ...any) ([]*Item, error) {
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*Item
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(&i.Type, &i.ID, &i.Name, &i.DeletedAt, &i.MediaKey); err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, err
+		}
+
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *Repository) queryIDs(ctx context.Context, query string, args ...any) ([]int, error) {
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, err
+	}
+
+	return ids, nil
+}
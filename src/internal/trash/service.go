@@ -0,0 +1,237 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/history"
+	"film-library/src/internal/media"
+)
+
+var (
+	ErrIdInvalid = errors.New("invalid id")
+)
+
+var _ TrashService = (*Service)(nil)
+
+type Service struct {
+	repo      TrashRepository
+	tx        db.Transactor
+	history   history.Recorder
+	storage   media.Storage
+	retention time.Duration
+}
+
+// NewService returns a service purging items deleted longer than
+// retention ago unless a purge request tells otherwise, images of purged
+// items are removed from st.
+func NewService(tr TrashRepository, tx db.Transactor, hr history.Recorder, st media.Storage, retention time.Duration) *Service {
+	return &Service{
+		repo:      tr,
+		tx:        tx,
+		history:   hr,
+		storage:   st,
+		retention: retention,
+	}
+}
+
+func (s *Service) GetAll(ctx context.Context) (*TrashResponse, error) {
+	const op = "trash.Service.GetAll"
+
+	items, err := s.repo.GetAll(ctx)
+	if err != nil {
+		log.Printf("ERROR: failed to get trash records from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToTrashResponse(items)
+
+	return res, nil
+}
+
+// RestoreFilm brings a film back together with its credits, which are kept
+// while the film is in trash.
+func (s *Service) RestoreFilm(ctx context.Context, req *TrashIdRequest) (*ItemResponse, error) {
+	const op = "trash.Service.RestoreFilm"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	item, err := s.restore(ctx, history.EntityFilm, int(id), s.repo.RestoreFilm)
+	if err != nil {
+		log.Printf("ERROR: failed to restore film record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToItemResponse(item)
+
+	return res, nil
+}
+
+func (s *Service) RestoreActor(ctx context.Context, req *TrashIdRequest) (*ItemResponse, error) {
+	const op = "trash.Service.RestoreActor"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	item, err := s.restore(ctx, history.EntityActor, int(id), s.repo.RestoreActor)
+	if err != nil {
+		log.Printf("ERROR: failed to restore actor record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToItemResponse(item)
+
+	return res, nil
+}
+
+func (s *Service) PurgeFilm(ctx context.Context, req *TrashIdRequest) (*ItemResponse, error) {
+	const op = "trash.Service.PurgeFilm"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	item, err := s.repo.PurgeFilm(ctx, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to purge film record in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	s.discard(ctx, item)
+
+	res := ToItemResponse(item)
+
+	return res, nil
+}
+
+func (s *Service) PurgeActor(ctx context.Context, req *TrashIdRequest) (*ItemResponse, error) {
+	const op = "trash.Service.PurgeActor"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	var item *Item
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		filmIDs, err := s.repo.GetActorFilms(ctx, int(id))
+		if err != nil {
+			log.Printf("ERROR: failed to get films of actor from repository\n")
+			return err
+		}
+
+		item, err = s.repo.PurgeActor(ctx, int(id))
+		if err != nil {
+			log.Printf("ERROR: failed to purge actor record in repository\n")
+			return err
+		}
+
+		return s.recordCredits(ctx, filmIDs)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	s.discard(ctx, item)
+
+	res := ToItemResponse(item)
+
+	return res, nil
+}
+
+func (s *Service) Purge(ctx context.Context, req *PurgeRequest) (*PurgeResponse, error) {
+	const op = "trash.Service.Purge"
+
+	vErr := ValidatePurgeRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	before := time.Now().Add(-ToOlderThan(req.OlderThanQuery, s.retention))
+
+	var items []*Item
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		filmIDs, err := s.repo.GetTrashedActorsFilms(ctx, before)
+		if err != nil {
+			log.Printf("ERROR: failed to get films of trashed actors from repository\n")
+			return err
+		}
+
+		items, err = s.repo.Purge(ctx, before)
+		if err != nil {
+			log.Printf("ERROR: failed to purge trash records in repository\n")
+			return err
+		}
+
+		return s.recordCredits(ctx, filmIDs)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, v := range items {
+		s.discard(ctx, v)
+	}
+	log.Printf("INFO: purged %d items deleted before %s\n", len(items), before.Format(time.RFC3339))
+
+	res := ToPurgeResponse(before, items)
+
+	return res, nil
+}
+
+// restore brings an item back and records its version in a single
+// transaction.
+func (s *Service) restore(ctx context.Context, entity string, id int, restore func(ctx context.Context, id int) (*Item, error)) (*Item, error) {
+	var item *Item
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		item, err = restore(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.history.Record(ctx, entity, item.ID, history.ActionRestore)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// recordCredits keeps versions of live films whose credits of purged actors
+// cascaded away, so that their history does not list the credits anymore.
+func (s *Service) recordCredits(ctx context.Context, filmIDs []int) error {
+	for _, id := range filmIDs {
+		if err := s.history.Record(ctx, history.EntityFilm, id, history.ActionCredits); err != nil {
+			log.Printf("ERROR: failed to record film history\n")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// discard removes stored images of a purged item, failures are only
+// logged since the records are gone already.
+func (s *Service) discard(ctx context.Context, item *Item) {
+	if len(item.MediaKey) == 0 {
+		return
+	}
+
+	if err := s.storage.Delete(ctx, item.MediaKey); err != nil {
+		log.Printf("ERROR: failed to delete stored image key=%s err=%s\n", item.MediaKey, err.Error())
+	}
+}
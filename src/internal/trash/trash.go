@@ -0,0 +1,85 @@
+package trash
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	TypeFilm  = "film"
+	TypeActor = "actor"
+)
+
+// Item is a soft deleted film or actor, MediaKey is the key of its poster
+// or headshot and is empty when there is none.
+type Item struct {
+	Type      string
+	ID        int
+	Name      string
+	DeletedAt time.Time
+	MediaKey  string
+}
+
+// TrashRepository reaches films and actors that are hidden from regular
+// queries, purging returns the removed items so their images can be
+// discarded.
+type TrashRepository interface {
+	GetAll(ctx context.Context) ([]*Item, error)
+	RestoreFilm(ctx context.Context, id int) (*Item, error)
+	RestoreActor(ctx context.Context, id int) (*Item, error)
+	PurgeFilm(ctx context.Context, id int) (*Item, error)
+	PurgeActor(ctx context.Context, id int) (*Item, error)
+	Purge(ctx context.Context, before time.Time) ([]*Item, error)
+	// GetActorFilms returns live films crediting the actor in cast or crew,
+	// credits purging the actor takes away.
+	GetActorFilms(ctx context.Context, id int) ([]int, error)
+	// GetTrashedActorsFilms returns live films crediting actors deleted
+	// before the given time.
+	GetTrashedActorsFilms(ctx context.Context, before time.Time) ([]int, error)
+}
+
+type TrashService interface {
+	GetAll(ctx context.Context) (*TrashResponse, error)
+	RestoreFilm(ctx context.Context, req *TrashIdRequest) (*ItemResponse, error)
+	RestoreActor(ctx context.Context, req *TrashIdRequest) (*ItemResponse, error)
+	PurgeFilm(ctx context.Context, req *TrashIdRequest) (*ItemResponse, error)
+	PurgeActor(ctx context.Context, req *TrashIdRequest) (*ItemResponse, error)
+	Purge(ctx context.Context, req *PurgeRequest) (*PurgeResponse, error)
+}
+
+type TrashHandler interface {
+	GetAll(w http.ResponseWriter, r *http.Request)
+	RestoreFilm(w http.ResponseWriter, r *http.Request)
+	RestoreActor(w http.ResponseWriter, r *http.Request)
+	PurgeFilm(w http.ResponseWriter, r *http.Request)
+	PurgeActor(w http.ResponseWriter, r *http.Request)
+	Purge(w http.ResponseWriter, r *http.Request)
+}
+
+type ItemResponse struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	DeletedAt string `json:"deletedAt,omitempty"`
+}
+
+type TrashResponse struct {
+	Films  []*ItemResponse `json:"films"`
+	Actors []*ItemResponse `json:"actors"`
+}
+
+type PurgeResponse struct {
+	Before string          `json:"before"`
+	Films  []*ItemResponse `json:"films"`
+	Actors []*ItemResponse `json:"actors"`
+}
+
+type TrashIdRequest struct {
+	ID string
+}
+
+// PurgeRequest selects items deleted longer ago than OlderThanQuery, a
+// duration such as 720h, the configured retention applies when empty.
+type PurgeRequest struct {
+	OlderThanQuery string
+}
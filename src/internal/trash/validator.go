@@ -0,0 +1,26 @@
+package trash
+
+import (
+	"time"
+
+	"film-library/src/internal/tools"
+)
+
+func ValidatePurgeRequest(req *PurgeRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if len(req.OlderThanQuery) != 0 {
+		d, err := time.ParseDuration(req.OlderThanQuery)
+		if err != nil {
+			ve.AddViolation("olderThan is not a duration")
+		} else if d < 0 {
+			ve.AddViolation("olderThan is negative")
+		}
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
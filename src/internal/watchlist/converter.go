@@ -0,0 +1,86 @@
+package watchlist
+
+import (
+	"time"
+
+	"film-library/src/internal/tools"
+)
+
+func ToFilmShortResponse(f *FilmShort) FilmShortResponse {
+	return FilmShortResponse{
+		ID:          f.ID,
+		Name:        f.Name,
+		ReleaseDate: f.ReleaseDate.Format(time.DateOnly),
+	}
+}
+
+func ToEntryResponse(e *Entry) *EntryResponse {
+	return &EntryResponse{
+		Film:    ToFilmShortResponse(&e.Film),
+		AddedAt: e.AddedAt,
+	}
+}
+
+func ToDiaryEntryResponse(de *DiaryEntry) *DiaryEntryResponse {
+	return &DiaryEntryResponse{
+		ID:        de.ID,
+		Film:      ToFilmShortResponse(&de.Film),
+		WatchedOn: de.WatchedOn.Format(time.DateOnly),
+		Rating:    de.Rating,
+		Rewatch:   de.Rewatch,
+	}
+}
+
+func ToDiaryEntry(dei *DiaryEntryInfo) *DiaryEntry {
+	watchedOn, _ := time.Parse(time.DateOnly, dei.WatchedOn)
+
+	return &DiaryEntry{
+		Film: FilmShort{
+			ID: dei.FilmID,
+		},
+		WatchedOn: watchedOn,
+		Rating:    dei.Rating,
+		Rewatch:   dei.Rewatch,
+	}
+}
+
+func ToQuery(req *GetEntriesRequest) *Query {
+	limit, cursor, backward := tools.ToPageQuery(req.LimitQuery, req.AfterQuery, req.BeforeQuery)
+
+	return &Query{
+		UserID:   req.UserID,
+		Limit:    limit,
+		Cursor:   cursor,
+		Backward: backward,
+	}
+}
+
+// ToKeysetConditions applies keyset position, ordering and limit of q to
+// a query listing the latest entries first, col is the sort key and id
+// breaks ties between entries sharing it.
+func ToKeysetConditions(q *Query, qb *tools.SelectBuilder, col, id string) *tools.SelectBuilder {
+	cmp, order := "<", "DESC"
+	if q.Backward {
+		cmp, order = ">", "ASC"
+	}
+
+	if q.Cursor != nil {
+		qb.Where(tools.Expr("("+col+", "+id+") "+cmp+" (?, ?)", q.Cursor.Value, q.Cursor.ID))
+	}
+
+	return qb.OrderBy(col+" "+order, id+" "+order).Limit(q.Limit)
+}
+
+func ToEntryCursor(e *Entry) *tools.Cursor {
+	return &tools.Cursor{
+		Value: e.AddedAt.Format(time.RFC3339Nano),
+		ID:    e.Film.ID,
+	}
+}
+
+func ToDiaryEntryCursor(de *DiaryEntry) *tools.Cursor {
+	return &tools.Cursor{
+		Value: de.WatchedOn.Format(time.DateOnly),
+		ID:    de.ID,
+	}
+}
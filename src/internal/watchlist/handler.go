@@ -0,0 +1,297 @@
+package watchlist
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"film-library/src/internal/tools"
+)
+
+var _ WatchlistHandler = (*Handler)(nil)
+
+type Handler struct {
+	service WatchlistService
+}
+
+func NewHandler(ws WatchlistService) *Handler {
+	return &Handler{
+		service: ws,
+	}
+}
+
+func (h *Handler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	res, err := h.service.GetWatchlist(r.Context(), &GetEntriesRequest{
+		UserID:      uc.ID,
+		LimitQuery:  r.URL.Query().Get("limit"),
+		AfterQuery:  r.URL.Query().Get("after"),
+		BeforeQuery: r.URL.Query().Get("before"),
+		TotalQuery:  r.URL.Query().Get("total"),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to get watchlist err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) AddToWatchlist(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := WatchlistFilmRequest{
+		UserID: uc.ID,
+		FilmID: r.PathValue("filmId"),
+	}
+
+	res, err := h.service.AddToWatchlist(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to add film to watchlist err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrFilmNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteFromWatchlist(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := WatchlistFilmRequest{
+		UserID: uc.ID,
+		FilmID: r.PathValue("filmId"),
+	}
+
+	err := h.service.DeleteFromWatchlist(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to remove film from watchlist err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrEntryNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.OK(w, r)
+}
+
+func (h *Handler) GetDiary(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	res, err := h.service.GetDiary(r.Context(), &GetEntriesRequest{
+		UserID:      uc.ID,
+		LimitQuery:  r.URL.Query().Get("limit"),
+		AfterQuery:  r.URL.Query().Get("after"),
+		BeforeQuery: r.URL.Query().Get("before"),
+		TotalQuery:  r.URL.Query().Get("total"),
+	})
+	if err != nil {
+		log.Printf("ERROR: failed to get diary err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) GetDiaryEntry(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := DiaryEntryIdRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+
+	res, err := h.service.GetDiaryEntry(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to get diary entry err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrDiaryEntryNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) AddDiaryEntry(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := AddDiaryEntryRequest{
+		UserID: uc.ID,
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.AddDiaryEntry(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to add diary entry err=%s\n", err.Error())
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrFilmNotExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "film with given id does not exist",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) UpdateDiaryEntry(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := DiaryEntryIdInfoRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+	if ok := tools.BindJSON(w, r, &req.Info); !ok {
+		return
+	}
+
+	res, err := h.service.UpdateDiaryEntry(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to update diary entry err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrDiaryEntryNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		var ve *tools.ValidationError
+		if errors.As(err, &ve) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      ve.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, ErrFilmNotExist) {
+			tools.JSON(w, r, http.StatusBadRequest, &tools.ErrorMessage{
+				ErrorType: tools.ErrorTypeValidation,
+				Body:      "film with given id does not exist",
+			})
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteDiaryEntry(w http.ResponseWriter, r *http.Request) {
+	uc, ok := tools.UserClaimsFromContext(r.Context())
+	if !ok {
+		log.Printf("ERROR: no user claims in request context\n")
+		tools.Unauthorized(w, r)
+		return
+	}
+
+	req := DiaryEntryIdRequest{
+		ID:     r.PathValue("id"),
+		UserID: uc.ID,
+	}
+
+	res, err := h.service.DeleteDiaryEntry(r.Context(), &req)
+	if err != nil {
+		log.Printf("ERROR: failed to delete diary entry err=%s\n", err.Error())
+
+		if errors.Is(err, ErrIdInvalid) || errors.Is(err, ErrDiaryEntryNotExist) {
+			tools.NotFound(w, r)
+			return
+		}
+
+		tools.InternalServerError(w, r)
+		return
+	}
+
+	tools.JSON(w, r, http.StatusOK, res)
+}
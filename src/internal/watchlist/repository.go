@@ -0,0 +1,371 @@
+package watchlist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"film-library/src/internal/db"
+	"film-library/src/internal/tools"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrFilmNotExist       = errors.New("film with given id does not exist")
+	ErrEntryNotExist      = errors.New("film is not on the watchlist")
+	ErrDiaryEntryNotExist = errors.New("diary entry does not exist")
+)
+
+var _ WatchlistRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.DBTX
+}
+
+func NewRepository(db db.DBTX) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) GetWatchlist(ctx context.Context, q *Query) ([]*Entry, error) {
+	const op = "watchlist.Repository.GetWatchlist"
+
+	qb := tools.NewSelectBuilder(`
+		SELECT w.user_id, m.movie_id, m.movie_name, m.releasedate, w.added_at
+		FROM watchlist w
+		INNER JOIN movie m USING (movie_id)`)
+	qb.Where(tools.And(tools.Expr("w.user_id = ?", q.UserID), tools.Expr("m.deleted_at IS NULL")))
+	query, args := ToKeysetConditions(q, qb, "w.added_at", "w.movie_id").Build()
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var entries []*Entry
+
+	for rows.Next() {
+		var e Entry
+		err := rows.Scan(&e.UserID, &e.Film.ID, &e.Film.Name, &e.Film.ReleaseDate, &e.AddedAt)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func (r *Repository) CountWatchlist(ctx context.Context, userID int) (int, error) {
+	const op = "watchlist.Repository.CountWatchlist"
+
+	const query = `SELECT COUNT(*) FROM watchlist WHERE user_id = $1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var count int
+	err = stmt.QueryRowContext(ctx, userID).Scan(&count)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// AddToWatchlist keeps the original time of addition when the film is
+// already on the watchlist.
+func (r *Repository) AddToWatchlist(ctx context.Context, e *Entry) (*Entry, error) {
+	const op = "watchlist.Repository.AddToWatchlist"
+
+	const query = `
+		WITH w AS (
+			INSERT INTO watchlist(user_id, movie_id)
+			SELECT $1, movie_id FROM movie WHERE movie_id = $2 AND deleted_at IS NULL
+			ON CONFLICT (user_id, movie_id) DO UPDATE SET added_at = watchlist.added_at
+			RETURNING movie_id, added_at
+		)
+		SELECT m.movie_name, m.releasedate, w.added_at
+		FROM w
+		INNER JOIN movie m USING (movie_id)`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, e.UserID, e.Film.ID).Scan(&e.Film.Name, &e.Film.ReleaseDate, &e.AddedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: film with id=%d does not exist\n", e.Film.ID)
+			return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+		}
+
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "foreign_key_violation" {
+				log.Printf("ERROR: film with id=%d does not exist\n", e.Film.ID)
+				return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return e, nil
+}
+
+func (r *Repository) DeleteFromWatchlist(ctx context.Context, e *Entry) error {
+	const op = "watchlist.Repository.DeleteFromWatchlist"
+
+	const query = `DELETE FROM watchlist WHERE user_id = $1 AND movie_id = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, e.UserID, e.Film.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by delete\n")
+		return fmt.Errorf("%s: %w", op, ErrEntryNotExist)
+	}
+
+	return nil
+}
+
+const diaryEntryQuery = `
+	SELECT d.entry_id, d.user_id, m.movie_id, m.movie_name, m.releasedate,
+		d.watched_on, COALESCE(d.rating, 0), d.rewatch
+	FROM diary_entry d
+	INNER JOIN movie m USING (movie_id)`
+
+func (r *Repository) GetDiary(ctx context.Context, q *Query) ([]*DiaryEntry, error) {
+	const op = "watchlist.Repository.GetDiary"
+
+	qb := tools.NewSelectBuilder(diaryEntryQuery)
+	qb.Where(tools.And(tools.Expr("d.user_id = ?", q.UserID), tools.Expr("m.deleted_at IS NULL")))
+	query, args := ToKeysetConditions(q, qb, "d.watched_on", "d.entry_id").Build()
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var entries []*DiaryEntry
+
+	for rows.Next() {
+		var de DiaryEntry
+		err := rows.Scan(&de.ID, &de.UserID, &de.Film.ID, &de.Film.Name, &de.Film.ReleaseDate,
+			&de.WatchedOn, &de.Rating, &de.Rewatch)
+		if err != nil {
+			log.Printf("ERROR: failed to execute query\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		entries = append(entries, &de)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func (r *Repository) CountDiary(ctx context.Context, userID int) (int, error) {
+	const op = "watchlist.Repository.CountDiary"
+
+	const query = `SELECT COUNT(*) FROM diary_entry WHERE user_id = $1`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var count int
+	err = stmt.QueryRowContext(ctx, userID).Scan(&count)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *Repository) GetDiaryEntry(ctx context.Context, userID, id int) (*DiaryEntry, error) {
+	const op = "watchlist.Repository.GetDiaryEntry"
+
+	const query = diaryEntryQuery + ` WHERE d.entry_id = $1 AND d.user_id = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var de DiaryEntry
+	err = stmt.QueryRowContext(ctx, id, userID).Scan(&de.ID, &de.UserID, &de.Film.ID, &de.Film.Name, &de.Film.ReleaseDate,
+		&de.WatchedOn, &de.Rating, &de.Rewatch)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: diary entry with id=%d does not exist\n", id)
+			return nil, fmt.Errorf("%s: %w", op, ErrDiaryEntryNotExist)
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &de, nil
+}
+
+func (r *Repository) AddDiaryEntry(ctx context.Context, de *DiaryEntry) (*DiaryEntry, error) {
+	const op = "watchlist.Repository.AddDiaryEntry"
+
+	const query = `
+		INSERT INTO diary_entry(user_id, movie_id, watched_on, rating, rewatch)
+		SELECT $1, movie_id, $3, NULLIF($4, 0), $5 FROM movie WHERE movie_id = $2 AND deleted_at IS NULL
+		RETURNING entry_id`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, de.UserID, de.Film.ID, de.WatchedOn, de.Rating, de.Rewatch).Scan(&de.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("ERROR: film with id=%d does not exist\n", de.Film.ID)
+			return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+		}
+
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "foreign_key_violation" {
+				log.Printf("ERROR: film with id=%d does not exist\n", de.Film.ID)
+				return nil, fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return de, nil
+}
+
+func (r *Repository) UpdateDiaryEntry(ctx context.Context, de *DiaryEntry) error {
+	const op = "watchlist.Repository.UpdateDiaryEntry"
+
+	const query = `
+		UPDATE diary_entry
+		SET movie_id = $1, watched_on = $2, rating = NULLIF($3, 0), rewatch = $4
+		WHERE entry_id = $5 AND user_id = $6`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, de.Film.ID, de.WatchedOn, de.Rating, de.Rewatch, de.ID, de.UserID)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) {
+			if pgErr.Code.Name() == "foreign_key_violation" {
+				log.Printf("ERROR: film with id=%d does not exist\n", de.Film.ID)
+				return fmt.Errorf("%s: %w", op, ErrFilmNotExist)
+			}
+		}
+
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by update\n")
+		return fmt.Errorf("%s: %w", op, ErrDiaryEntryNotExist)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteDiaryEntry(ctx context.Context, userID, id int) error {
+	const op = "watchlist.Repository.DeleteDiaryEntry"
+
+	const query = `DELETE FROM diary_entry WHERE entry_id = $1 AND user_id = $2`
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("ERROR: failed to prepare query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id, userID)
+	if err != nil {
+		log.Printf("ERROR: failed to execute query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("ERROR: failed to retrieve amount of rows affected by query\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		log.Printf("ERROR: zero rows affected by delete\n")
+		return fmt.Errorf("%s: %w", op, ErrDiaryEntryNotExist)
+	}
+
+	return nil
+}
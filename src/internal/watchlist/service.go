@@ -0,0 +1,301 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+
+	"film-library/src/internal/tools"
+)
+
+var (
+	ErrIdInvalid = errors.New("invalid id")
+)
+
+var _ WatchlistService = (*Service)(nil)
+
+type Service struct {
+	repo WatchlistRepository
+}
+
+func NewService(wr WatchlistRepository) *Service {
+	return &Service{
+		repo: wr,
+	}
+}
+
+func (s *Service) GetWatchlist(ctx context.Context, req *GetEntriesRequest) (*GetWatchlistResponse, error) {
+	const op = "watchlist.Service.GetWatchlist"
+
+	vErr := ValidateGetEntriesRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	q := ToQuery(req)
+
+	// one extra entry is requested to find out whether the next page exists
+	limit := q.Limit
+	q.Limit = limit + 1
+
+	entries, err := s.repo.GetWatchlist(ctx, q)
+	if err != nil {
+		log.Printf("ERROR: failed to get watchlist entries from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hasMore := len(entries) > limit
+	if hasMore {
+		entries = entries[:limit]
+	}
+	if q.Backward {
+		slices.Reverse(entries)
+	}
+
+	res := &GetWatchlistResponse{
+		Entries: make([]*EntryResponse, 0, len(entries)),
+	}
+	for _, v := range entries {
+		res.Entries = append(res.Entries, ToEntryResponse(v))
+	}
+
+	next, prev := tools.PageLinks(hasMore, q.Backward, q.Cursor != nil)
+	if len(entries) != 0 {
+		if next {
+			res.NextCursor = tools.EncodeCursor(ToEntryCursor(entries[len(entries)-1]))
+		}
+		if prev {
+			res.PrevCursor = tools.EncodeCursor(ToEntryCursor(entries[0]))
+		}
+	}
+
+	if req.TotalQuery == "true" {
+		total, err := s.repo.CountWatchlist(ctx, req.UserID)
+		if err != nil {
+			log.Printf("ERROR: failed to count watchlist entries in repository\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		res.Total = &total
+	}
+
+	return res, nil
+}
+
+func (s *Service) AddToWatchlist(ctx context.Context, req *WatchlistFilmRequest) (*EntryResponse, error) {
+	const op = "watchlist.Service.AddToWatchlist"
+
+	id, err := strconv.ParseUint(req.FilmID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	e := &Entry{
+		UserID: req.UserID,
+		Film: FilmShort{
+			ID: int(id),
+		},
+	}
+	e, err = s.repo.AddToWatchlist(ctx, e)
+	if err != nil {
+		log.Printf("ERROR: failed to add film to watchlist\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToEntryResponse(e)
+
+	return res, nil
+}
+
+func (s *Service) DeleteFromWatchlist(ctx context.Context, req *WatchlistFilmRequest) error {
+	const op = "watchlist.Service.DeleteFromWatchlist"
+
+	id, err := strconv.ParseUint(req.FilmID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	e := &Entry{
+		UserID: req.UserID,
+		Film: FilmShort{
+			ID: int(id),
+		},
+	}
+	err = s.repo.DeleteFromWatchlist(ctx, e)
+	if err != nil {
+		log.Printf("ERROR: failed to remove film from watchlist\n")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetDiary(ctx context.Context, req *GetEntriesRequest) (*GetDiaryResponse, error) {
+	const op = "watchlist.Service.GetDiary"
+
+	vErr := ValidateGetEntriesRequest(req)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	q := ToQuery(req)
+
+	// one extra entry is requested to find out whether the next page exists
+	limit := q.Limit
+	q.Limit = limit + 1
+
+	entries, err := s.repo.GetDiary(ctx, q)
+	if err != nil {
+		log.Printf("ERROR: failed to get diary entries from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hasMore := len(entries) > limit
+	if hasMore {
+		entries = entries[:limit]
+	}
+	if q.Backward {
+		slices.Reverse(entries)
+	}
+
+	res := &GetDiaryResponse{
+		Entries: make([]*DiaryEntryResponse, 0, len(entries)),
+	}
+	for _, v := range entries {
+		res.Entries = append(res.Entries, ToDiaryEntryResponse(v))
+	}
+
+	next, prev := tools.PageLinks(hasMore, q.Backward, q.Cursor != nil)
+	if len(entries) != 0 {
+		if next {
+			res.NextCursor = tools.EncodeCursor(ToDiaryEntryCursor(entries[len(entries)-1]))
+		}
+		if prev {
+			res.PrevCursor = tools.EncodeCursor(ToDiaryEntryCursor(entries[0]))
+		}
+	}
+
+	if req.TotalQuery == "true" {
+		total, err := s.repo.CountDiary(ctx, req.UserID)
+		if err != nil {
+			log.Printf("ERROR: failed to count diary entries in repository\n")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		res.Total = &total
+	}
+
+	return res, nil
+}
+
+func (s *Service) GetDiaryEntry(ctx context.Context, req *DiaryEntryIdRequest) (*DiaryEntryResponse, error) {
+	const op = "watchlist.Service.GetDiaryEntry"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	de, err := s.repo.GetDiaryEntry(ctx, req.UserID, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get diary entry from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToDiaryEntryResponse(de)
+
+	return res, nil
+}
+
+func (s *Service) AddDiaryEntry(ctx context.Context, req *AddDiaryEntryRequest) (*DiaryEntryResponse, error) {
+	const op = "watchlist.Service.AddDiaryEntry"
+
+	vErr := ValidateDiaryEntryInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	de := ToDiaryEntry(&req.Info)
+	de.UserID = req.UserID
+
+	de, err := s.repo.AddDiaryEntry(ctx, de)
+	if err != nil {
+		log.Printf("ERROR: failed to add diary entry to repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	de, err = s.repo.GetDiaryEntry(ctx, req.UserID, de.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to get added diary entry from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToDiaryEntryResponse(de)
+
+	return res, nil
+}
+
+func (s *Service) UpdateDiaryEntry(ctx context.Context, req *DiaryEntryIdInfoRequest) (*DiaryEntryResponse, error) {
+	const op = "watchlist.Service.UpdateDiaryEntry"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	vErr := ValidateDiaryEntryInfo(&req.Info)
+	if vErr != nil {
+		log.Printf("ERROR: failed request validation\n")
+		return nil, fmt.Errorf("%s: %w", op, vErr)
+	}
+	de := ToDiaryEntry(&req.Info)
+	de.ID = int(id)
+	de.UserID = req.UserID
+
+	err = s.repo.UpdateDiaryEntry(ctx, de)
+	if err != nil {
+		log.Printf("ERROR: failed to update diary entry in repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	de, err = s.repo.GetDiaryEntry(ctx, req.UserID, de.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to get updated diary entry from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToDiaryEntryResponse(de)
+
+	return res, nil
+}
+
+func (s *Service) DeleteDiaryEntry(ctx context.Context, req *DiaryEntryIdRequest) (*DiaryEntryResponse, error) {
+	const op = "watchlist.Service.DeleteDiaryEntry"
+
+	id, err := strconv.ParseUint(req.ID, 10, 32)
+	if err != nil {
+		log.Printf("ERROR: failed id parameter conversion (string -> int32)\n")
+		return nil, fmt.Errorf("%s: %w", op, ErrIdInvalid)
+	}
+
+	de, err := s.repo.GetDiaryEntry(ctx, req.UserID, int(id))
+	if err != nil {
+		log.Printf("ERROR: failed to get diary entry from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repo.DeleteDiaryEntry(ctx, req.UserID, de.ID)
+	if err != nil {
+		log.Printf("ERROR: failed to delete diary entry from repository\n")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := ToDiaryEntryResponse(de)
+
+	return res, nil
+}
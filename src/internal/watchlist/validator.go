@@ -0,0 +1,45 @@
+package watchlist
+
+import (
+	"time"
+
+	"film-library/src/internal/tools"
+)
+
+func ValidateGetEntriesRequest(req *GetEntriesRequest) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	tools.ValidatePageQuery(ve, req.LimitQuery, req.AfterQuery, req.BeforeQuery, req.TotalQuery)
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
+
+func ValidateDiaryEntryInfo(dei *DiaryEntryInfo) *tools.ValidationError {
+	ve := &tools.ValidationError{}
+
+	if dei.FilmID <= 0 {
+		ve.AddViolation("incorrect filmId, expected positive integer")
+	}
+
+	watchedOn, err := time.Parse(time.DateOnly, dei.WatchedOn)
+	if err != nil {
+		ve.AddViolation("incorrect watchedOn format (expected format: 2006-01-02)")
+	} else if watchedOn.After(time.Now().Add(24 * time.Hour)) {
+		// a day of slack keeps clients in timezones ahead of the server valid
+		ve.AddViolation("watchedOn is in the future")
+	}
+
+	if dei.Rating != 0 && (dei.Rating < 1 || dei.Rating > 10) {
+		ve.AddViolation("incorrect rating, expected: 1 <= rating <= 10 or 0 for not rated")
+	}
+
+	if ve.NoViolations() {
+		return nil
+	}
+
+	return ve
+}
@@ -0,0 +1,144 @@
+package watchlist
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"film-library/src/internal/tools"
+)
+
+type Entry struct {
+	UserID  int
+	Film    FilmShort
+	AddedAt time.Time
+}
+
+// DiaryEntry records a single viewing of a film, Rating of zero means the
+// viewing was not rated.
+type DiaryEntry struct {
+	ID        int
+	UserID    int
+	Film      FilmShort
+	WatchedOn time.Time
+	Rating    int
+	Rewatch   bool
+}
+
+type FilmShort struct {
+	ID          int
+	Name        string
+	ReleaseDate time.Time
+}
+
+// Query selects a page of entries of a single user.
+type Query struct {
+	UserID   int
+	Limit    int
+	Cursor   *tools.Cursor
+	Backward bool
+}
+
+type WatchlistRepository interface {
+	GetWatchlist(ctx context.Context, q *Query) ([]*Entry, error)
+	CountWatchlist(ctx context.Context, userID int) (int, error)
+	AddToWatchlist(ctx context.Context, e *Entry) (*Entry, error)
+	DeleteFromWatchlist(ctx context.Context, e *Entry) error
+	GetDiary(ctx context.Context, q *Query) ([]*DiaryEntry, error)
+	CountDiary(ctx context.Context, userID int) (int, error)
+	GetDiaryEntry(ctx context.Context, userID, id int) (*DiaryEntry, error)
+	AddDiaryEntry(ctx context.Context, de *DiaryEntry) (*DiaryEntry, error)
+	UpdateDiaryEntry(ctx context.Context, de *DiaryEntry) error
+	DeleteDiaryEntry(ctx context.Context, userID, id int) error
+}
+
+type WatchlistService interface {
+	GetWatchlist(ctx context.Context, req *GetEntriesRequest) (*GetWatchlistResponse, error)
+	AddToWatchlist(ctx context.Context, req *WatchlistFilmRequest) (*EntryResponse, error)
+	DeleteFromWatchlist(ctx context.Context, req *WatchlistFilmRequest) error
+	GetDiary(ctx context.Context, req *GetEntriesRequest) (*GetDiaryResponse, error)
+	GetDiaryEntry(ctx context.Context, req *DiaryEntryIdRequest) (*DiaryEntryResponse, error)
+	AddDiaryEntry(ctx context.Context, req *AddDiaryEntryRequest) (*DiaryEntryResponse, error)
+	UpdateDiaryEntry(ctx context.Context, req *DiaryEntryIdInfoRequest) (*DiaryEntryResponse, error)
+	DeleteDiaryEntry(ctx context.Context, req *DiaryEntryIdRequest) (*DiaryEntryResponse, error)
+}
+
+type WatchlistHandler interface {
+	GetWatchlist(w http.ResponseWriter, r *http.Request)
+	AddToWatchlist(w http.ResponseWriter, r *http.Request)
+	DeleteFromWatchlist(w http.ResponseWriter, r *http.Request)
+	GetDiary(w http.ResponseWriter, r *http.Request)
+	GetDiaryEntry(w http.ResponseWriter, r *http.Request)
+	AddDiaryEntry(w http.ResponseWriter, r *http.Request)
+	UpdateDiaryEntry(w http.ResponseWriter, r *http.Request)
+	DeleteDiaryEntry(w http.ResponseWriter, r *http.Request)
+}
+
+type FilmShortResponse struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ReleaseDate string `json:"releasedate"`
+}
+
+type EntryResponse struct {
+	Film    FilmShortResponse `json:"film"`
+	AddedAt time.Time         `json:"addedAt"`
+}
+
+type DiaryEntryInfo struct {
+	FilmID    int    `json:"filmId"`
+	WatchedOn string `json:"watchedOn"`
+	Rating    int    `json:"rating"`
+	Rewatch   bool   `json:"rewatch"`
+}
+
+type DiaryEntryResponse struct {
+	ID        int               `json:"id"`
+	Film      FilmShortResponse `json:"film"`
+	WatchedOn string            `json:"watchedOn"`
+	Rating    int               `json:"rating,omitempty"`
+	Rewatch   bool              `json:"rewatch"`
+}
+
+type GetEntriesRequest struct {
+	UserID      int
+	LimitQuery  string
+	AfterQuery  string
+	BeforeQuery string
+	TotalQuery  string
+}
+
+type GetWatchlistResponse struct {
+	Entries    []*EntryResponse `json:"entries"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+	PrevCursor string           `json:"prevCursor,omitempty"`
+	Total      *int             `json:"total,omitempty"`
+}
+
+type GetDiaryResponse struct {
+	Entries    []*DiaryEntryResponse `json:"entries"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+	PrevCursor string                `json:"prevCursor,omitempty"`
+	Total      *int                  `json:"total,omitempty"`
+}
+
+type WatchlistFilmRequest struct {
+	UserID int
+	FilmID string
+}
+
+type AddDiaryEntryRequest struct {
+	UserID int
+	Info   DiaryEntryInfo
+}
+
+type DiaryEntryIdRequest struct {
+	ID     string
+	UserID int
+}
+
+type DiaryEntryIdInfoRequest struct {
+	ID     string
+	UserID int
+	Info   DiaryEntryInfo
+}